@@ -0,0 +1,107 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// accelerator provides the clients using the S3 Transfer Acceleration endpoint
+// for the buckets with the acceleration enabled.
+type accelerator struct {
+	mu      sync.Mutex
+	enabled map[string]bool
+	clients map[*s3.S3]*s3.S3
+	// bucketAccelerated returns whether the acceleration of the bucket is enabled.
+	bucketAccelerated func(ctx context.Context, client s3iface.S3API, bucket string) (bool, error)
+}
+
+func newAccelerator() *accelerator {
+	return &accelerator{
+		enabled: make(map[string]bool),
+		clients: make(map[*s3.S3]*s3.S3),
+		bucketAccelerated: func(ctx context.Context, client s3iface.S3API, bucket string) (bool, error) {
+			out, err := client.GetBucketAccelerateConfigurationWithContext(ctx, &s3.GetBucketAccelerateConfigurationInput{
+				Bucket: aws.String(bucket),
+			})
+			if err != nil {
+				return false, err
+			}
+			return aws.StringValue(out.Status) == s3.BucketAccelerateStatusEnabled, nil
+		},
+	}
+}
+
+// get returns the client using the accelerate endpoint derived from the base client
+// if the acceleration of the bucket is enabled.
+// The base client is returned as is if it is not *s3.S3, uses the custom endpoint,
+// the bucket name is not compatible with the acceleration (containing dots or an ARN),
+// or the acceleration of the bucket is disabled or can't be determined.
+func (a *accelerator) get(ctx context.Context, base s3iface.S3API, bucket string) s3iface.S3API {
+	c, ok := base.(*s3.S3)
+	if !ok || aws.StringValue(c.Config.Endpoint) != "" || strings.Contains(bucket, ".") || arn.IsARN(bucket) {
+		return base
+	}
+
+	a.mu.Lock()
+	enabled, ok := a.enabled[bucket]
+	a.mu.Unlock()
+	if !ok {
+		var err error
+		if enabled, err = a.bucketAccelerated(ctx, c, bucket); err != nil {
+			return base
+		}
+		if !enabled {
+			println("Transfer acceleration is not enabled for", bucket+", using the regular endpoint")
+		}
+		a.mu.Lock()
+		a.enabled[bucket] = enabled
+		a.mu.Unlock()
+	}
+	if !enabled {
+		return base
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ac, ok := a.clients[c]; ok {
+		return ac
+	}
+	sess, err := session.NewSession(c.Config.Copy(aws.NewConfig().WithS3UseAccelerate(true)))
+	if err != nil {
+		return base
+	}
+	ac := s3.New(sess)
+	// The handlers of the base client, e.g. read-only mode and the custom ones, are kept.
+	ac.Handlers = c.Handlers.Copy()
+	a.clients[c] = ac
+	return ac
+}
+
+// transferClient returns the client transferring the object data of the bucket,
+// which uses the accelerate endpoint if WithTransferAcceleration is enabled.
+func (m *Manager) transferClient(ctx context.Context, base s3iface.S3API, bucket string) s3iface.S3API {
+	if m.accelerator == nil {
+		return base
+	}
+	return m.accelerator.get(ctx, base, bucket)
+}
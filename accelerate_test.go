@@ -0,0 +1,100 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+func TestAccelerator(t *testing.T) {
+	ctx := context.Background()
+	newAccel := func(lookups map[string]int) *accelerator {
+		a := newAccelerator()
+		a.bucketAccelerated = func(ctx context.Context, client s3iface.S3API, bucket string) (bool, error) {
+			lookups[bucket]++
+			switch bucket {
+			case "accelerated", "accelerated2":
+				return true, nil
+			case "regular":
+				return false, nil
+			}
+			return false, errors.New("AccessDenied")
+		}
+		return a
+	}
+	newBase := func(cfg *aws.Config) *s3.S3 {
+		return s3.New(session.New(), aws.NewConfig().
+			WithRegion("us-east-1").
+			WithCredentials(credentials.NewStaticCredentials("id", "secret", "")), cfg)
+	}
+
+	t.Run("Accelerated", func(t *testing.T) {
+		lookups := make(map[string]int)
+		a := newAccel(lookups)
+		base := newBase(nil)
+
+		c, ok := a.get(ctx, base, "accelerated").(*s3.S3)
+		if !ok || c == base {
+			t.Fatal("Expected the accelerated client")
+		}
+		req, _ := c.PutObjectRequest(&s3.PutObjectInput{Bucket: aws.String("accelerated"), Key: aws.String("key")})
+		if err := req.Build(); err != nil {
+			t.Fatal(err)
+		}
+		if host := req.HTTPRequest.URL.Host; host != "accelerated.s3-accelerate.amazonaws.com" {
+			t.Errorf("Expected the accelerate endpoint, got %s", host)
+		}
+		if c2 := a.get(ctx, base, "accelerated2"); c2 != c {
+			t.Error("Expected the client to be shared by the buckets")
+		}
+		a.get(ctx, base, "accelerated")
+		if lookups["accelerated"] != 1 {
+			t.Errorf("Expected the status to be looked up once, got %d", lookups["accelerated"])
+		}
+	})
+	t.Run("Fallback", func(t *testing.T) {
+		testCases := map[string]struct {
+			base   s3iface.S3API
+			bucket string
+			lookup bool
+		}{
+			"Disabled":       {newBase(nil), "regular", true},
+			"LookupFailure":  {newBase(nil), "denied", true},
+			"DottedBucket":   {newBase(nil), "accelerated.example.com", false},
+			"NotS3Client":    {&dummyUnusedS3{}, "accelerated", false},
+			"CustomEndpoint": {newBase(aws.NewConfig().WithEndpoint("http://localhost:4572")), "accelerated", false},
+		}
+		for name, tc := range testCases {
+			tc := tc
+			t.Run(name, func(t *testing.T) {
+				lookups := make(map[string]int)
+				a := newAccel(lookups)
+				if c := a.get(ctx, tc.base, tc.bucket); c != tc.base {
+					t.Error("Expected the base client")
+				}
+				if (len(lookups) != 0) != tc.lookup {
+					t.Errorf("Unexpected lookups %v", lookups)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// copyObjectACL applies the ACL of the source object to the destination object.
+func (m *Manager) copyObjectACL(ctx context.Context, sourceBucket, sourceKey, destBucket, destKey string) error {
+	acl, err := m.regionalClient(ctx, m.sourceClient(), sourceBucket).GetObjectAclWithContext(ctx, &s3.GetObjectAclInput{
+		Bucket: aws.String(sourceBucket),
+		Key:    aws.String(sourceKey),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = m.destClient(ctx, destBucket).PutObjectAclWithContext(ctx, &s3.PutObjectAclInput{
+		Bucket: aws.String(destBucket),
+		Key:    aws.String(destKey),
+		AccessControlPolicy: &s3.AccessControlPolicy{
+			Grants: acl.Grants,
+			Owner:  acl.Owner,
+		},
+	})
+	return err
+}
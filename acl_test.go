@@ -0,0 +1,86 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyACLS3 struct {
+	s3iface.S3API
+	grants map[string][]*s3.Grant
+}
+
+func (s *dummyACLS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (s *dummyACLS3) GetObjectAclWithContext(ctx aws.Context, in *s3.GetObjectAclInput, opts ...request.Option) (*s3.GetObjectAclOutput, error) {
+	return &s3.GetObjectAclOutput{
+		Grants: s.grants[*in.Bucket+"/"+*in.Key],
+		Owner:  &s3.Owner{ID: aws.String("owner")},
+	}, nil
+}
+
+func (s *dummyACLS3) PutObjectAclWithContext(ctx aws.Context, in *s3.PutObjectAclInput, opts ...request.Option) (*s3.PutObjectAclOutput, error) {
+	s.grants[*in.Bucket+"/"+*in.Key] = in.AccessControlPolicy.Grants
+	return &s3.PutObjectAclOutput{}, nil
+}
+
+func TestACLCopy(t *testing.T) {
+	grant := &s3.Grant{
+		Grantee: &s3.Grantee{
+			Type: aws.String(s3.TypeGroup),
+			URI:  aws.String("http://acs.amazonaws.com/groups/global/AllUsers"),
+		},
+		Permission: aws.String(s3.PermissionRead),
+	}
+	for name, copyACL := range map[string]bool{"Enabled": true, "Disabled": false} {
+		copyACL := copyACL
+		t.Run(name, func(t *testing.T) {
+			s := &dummyACLS3{grants: map[string][]*s3.Grant{
+				"src/prefix/foo": {grant},
+			}}
+			var opts []Option
+			if copyACL {
+				opts = append(opts, WithACLCopy())
+			}
+			m := New(session.New(), opts...)
+			m.s3 = s
+
+			err := m.copyS3ToS3(context.Background(),
+				&fileInfo{name: "foo", size: 3},
+				&s3Path{bucket: "src", bucketPrefix: "prefix"},
+				&s3Path{bucket: "dst", bucketPrefix: "copied"},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			grants, ok := s.grants["dst/copied/foo"]
+			if ok != copyACL {
+				t.Fatalf("ACL copied: %v, expected: %v", ok, copyACL)
+			}
+			if copyACL && (len(grants) != 1 || grants[0] != grant) {
+				t.Errorf("Unexpected grants: %v", grants)
+			}
+		})
+	}
+}
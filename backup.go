@@ -0,0 +1,145 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// errBackupKind is returned if the backup and the destination are not the same kind,
+// i.e. both of them must be S3 or local.
+var errBackupKind = errors.New("backup prefix must be the same kind of the destination")
+
+// backupTimeFormat is the format of the timestamp suffix of the backup names.
+const backupTimeFormat = "20060102T150405Z"
+
+// backupSuffix returns the timestamp suffix of the backup name.
+func backupSuffix() string {
+	return "." + time.Now().UTC().Format(backupTimeFormat)
+}
+
+// backupTarget returns the S3 path of the backup, or the local directory of the backup.
+func (m *Manager) backupTarget() (*s3Path, string, error) {
+	u, err := parseURL(m.backup)
+	if err != nil {
+		return nil, "", err
+	}
+	if !isS3URL(u) {
+		return nil, m.backup, nil
+	}
+	p, err := urlToS3Path(u)
+	if err != nil {
+		return nil, "", err
+	}
+	if p.bucketPrefix != "" && !strings.HasSuffix(p.bucketPrefix, "/") {
+		p.bucketPrefix += "/"
+	}
+	return p, "", nil
+}
+
+// checkBackup returns an error if the backup can't be used for the destination.
+func (m *Manager) checkBackup(destIsS3 bool) error {
+	if m.backup == "" {
+		return nil
+	}
+	p, _, err := m.backupTarget()
+	if err != nil {
+		return err
+	}
+	if (p != nil) != destIsS3 {
+		return errBackupKind
+	}
+	return nil
+}
+
+// backupRemote copies the destination object to the backup prefix before the object
+// is deleted or overwritten. The backup key is the key of the object prefixed by the
+// backup prefix and suffixed by the timestamp.
+func (m *Manager) backupRemote(ctx context.Context, bucket, key string, size int64) error {
+	if m.backup == "" {
+		return nil
+	}
+	backup, _, err := m.backupTarget()
+	if err != nil {
+		return err
+	}
+	if backup == nil {
+		return errBackupKind
+	}
+	backupKey := backup.bucketPrefix + key + backupSuffix()
+	logOp(ctx, opAttrs(ctx, "backup", bucket, key, size), "Backing up", "s3://"+bucket+"/"+key, "to", "s3://"+backup.bucket+"/"+backupKey)
+
+	input := &s3.CopyObjectInput{
+		Bucket:                         aws.String(backup.bucket),
+		CopySource:                     aws.String(m.copySource(bucket, key)),
+		Key:                            aws.String(backupKey),
+		ServerSideEncryption:           m.sse,
+		SSEKMSKeyId:                    m.sseKMSKeyID,
+		SSECustomerAlgorithm:           m.sseCustomerAlgorithm,
+		SSECustomerKey:                 m.sseCustomerKey,
+		CopySourceSSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		CopySourceSSECustomerKey:       m.sseCustomerKey,
+	}
+	if needsMultipartCopy(size) {
+		return m.multipartCopy(ctx, input, size)
+	}
+	_, err = m.destClient(ctx, backup.bucket).CopyObjectWithContext(ctx, input)
+	return err
+}
+
+// backupLocal moves the local file under the destination path to the backup directory
+// before the file is deleted or overwritten. The backup file has the same relative
+// path as the file suffixed by the timestamp.
+func (m *Manager) backupLocal(ctx context.Context, destPath, filename string) error {
+	_, dir, err := m.backupTarget()
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		return errBackupKind
+	}
+	backupFilename := filepath.Join(dir, relativeName(destPath, filename)) + backupSuffix()
+	logOp(ctx, []interface{}{"op", "backup", "path", filename}, "Backing up", filename, "to", backupFilename)
+
+	if err := os.MkdirAll(filepath.Dir(backupFilename), 0755); err != nil {
+		return err
+	}
+	return moveLocalFile(filename, backupFilename)
+}
+
+func copyLocalFile(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
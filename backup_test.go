@@ -0,0 +1,129 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyBackupS3 struct {
+	dummyCopyDestS3
+	deleted []string
+}
+
+func (s *dummyBackupS3) DeleteObjectWithContext(ctx aws.Context, in *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, aws.StringValue(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+var backupTimestamp = regexp.MustCompile(`\.[0-9]{8}T[0-9]{6}Z$`)
+
+func TestBackupPrefix_Local(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	dest := filepath.Join(temp, "dest")
+	backup := filepath.Join(temp, "backup")
+
+	for name, data := range map[string]string{"a": "old", "dir/stale": "stale"} {
+		filename := filepath.Join(dest, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+			t.Fatal("Failed to write", err)
+		}
+	}
+
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}}
+	m := New(session.New(), WithDelete(), WithBackupPrefix(backup))
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", dest); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := ioutil.ReadFile(filepath.Join(dest, "a")); err != nil || string(data) != "a" {
+		t.Errorf("Expected the file to be updated, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "dir", "stale")); !os.IsNotExist(err) {
+		t.Error("Expected the stale file to be deleted")
+	}
+	for name, expected := range map[string]string{"a": "old", "dir/stale": "stale"} {
+		matches, err := filepath.Glob(filepath.Join(backup, name) + ".*")
+		if err != nil || len(matches) != 1 || !backupTimestamp.MatchString(matches[0]) {
+			t.Errorf("Expected a backup of %s, got %v", name, matches)
+			continue
+		}
+		if data, err := ioutil.ReadFile(matches[0]); err != nil || string(data) != expected {
+			t.Errorf("Expected the backup of %s to be %q, got %q, %v", name, expected, data, err)
+		}
+	}
+}
+
+func TestBackupPrefix_Remote(t *testing.T) {
+	t.Run("Overwrite", func(t *testing.T) {
+		src := &dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}
+		dst := &dummyBackupS3{dummyCopyDestS3: dummyCopyDestS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}}}
+		m := NewWithClients(src, dst,
+			WithBackupPrefix("s3://backup-bucket/backup"),
+			WithComparator(ComparatorFunc(func(src, dst *FileInfo) bool { return true })),
+		)
+		if err := m.Sync(context.Background(), "s3://src-bucket/prefix", "s3://dst-bucket/prefix"); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(dst.copied)
+		if len(dst.copied) != 3 ||
+			!regexp.MustCompile(`^backup-bucket/backup/prefix/a\.[0-9]{8}T[0-9]{6}Z$`).MatchString(dst.copied[0]) ||
+			dst.copied[1] != "dst-bucket/prefix/a" || dst.copied[2] != "dst-bucket/prefix/b" {
+			t.Errorf("Expected the overwritten object to be backed up, got %v", dst.copied)
+		}
+	})
+	t.Run("Delete", func(t *testing.T) {
+		s := &dummyBackupS3{}
+		m := New(session.New(), WithBackupPrefix("s3://backup-bucket/backup/"))
+		m.s3 = s
+
+		files := []*fileInfo{{name: "a"}, {name: "b"}}
+		if errs := m.deleteRemoteBatch(context.Background(), files, &s3Path{bucket: "bucket", bucketPrefix: "prefix"}); len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if len(s.copied) != 2 || len(s.deleted) != 2 {
+			t.Errorf("Expected 2 backups and deletions, got %v and %v", s.copied, s.deleted)
+		}
+	})
+}
+
+func TestBackupPrefix_Kind(t *testing.T) {
+	m := New(session.New(), WithBackupPrefix("s3://backup-bucket/backup/"))
+	m.s3 = &dummyUnusedS3{}
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", "local"); !errors.Is(err, errBackupKind) {
+		t.Errorf("Expected %v, got %v", errBackupKind, err)
+	}
+}
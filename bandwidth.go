@@ -0,0 +1,133 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// rateLimiter is the token bucket rate limiter shared by the transfer workers.
+// Tokens are consumed after the transfer, and the caller waits until
+// the debt is paid off.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(bytesPerSec int64) *rateLimiter {
+	return &rateLimiter{
+		rate:   float64(bytesPerSec),
+		burst:  float64(bytesPerSec),
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+// wait consumes n tokens and blocks until the token count becomes non-negative.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	d := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limitReader returns the reader limited by the bandwidth limit.
+func (m *Manager) limitReader(ctx context.Context, r io.Reader) io.Reader {
+	if m.bandwidth == nil {
+		return r
+	}
+	lr := &limitedReader{ctx: ctx, r: r, l: m.bandwidth}
+	if _, ok := r.(readSeekerAt); ok {
+		return &limitedReadSeekerAt{lr}
+	}
+	return lr
+}
+
+// limitWriterAt returns the writer limited by the bandwidth limit.
+func (m *Manager) limitWriterAt(ctx context.Context, w io.WriterAt) io.WriterAt {
+	if m.bandwidth == nil {
+		return w
+	}
+	return &limitedWriterAt{ctx: ctx, w: w, l: m.bandwidth}
+}
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *rateLimiter
+}
+
+func (r *limitedReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if werr := r.l.wait(r.ctx, n); werr != nil {
+		return n, werr
+	}
+	return n, err
+}
+
+type limitedReadSeekerAt struct {
+	*limitedReader
+}
+
+func (r *limitedReadSeekerAt) Seek(offset int64, whence int) (int64, error) {
+	return r.r.(io.Seeker).Seek(offset, whence)
+}
+
+func (r *limitedReadSeekerAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := r.r.(io.ReaderAt).ReadAt(b, off)
+	if werr := r.l.wait(r.ctx, n); werr != nil {
+		return n, werr
+	}
+	return n, err
+}
+
+type limitedWriterAt struct {
+	ctx context.Context
+	w   io.WriterAt
+	l   *rateLimiter
+}
+
+func (w *limitedWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	if err := w.l.wait(w.ctx, len(b)); err != nil {
+		return 0, err
+	}
+	return w.w.WriteAt(b, off)
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	const rate = 10 * 1024 * 1024
+	m := &Manager{}
+	WithBandwidthLimit(rate)(m)
+
+	// Initial burst is the amount of one second.
+	t0 := time.Now()
+	r := m.limitReader(context.Background(), bytes.NewReader(make([]byte, rate*3/2)))
+	if _, ok := r.(io.ReaderAt); !ok {
+		t.Error("ReaderAt interface must be kept")
+	}
+	n, err := io.Copy(ioutil.Discard, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != rate*3/2 {
+		t.Fatalf("Expected to read %d bytes, got %d", rate*3/2, n)
+	}
+	if d := time.Since(t0); d < 400*time.Millisecond || d > 2*time.Second {
+		t.Errorf("Expected to take 0.5s, took %v", d)
+	}
+}
+
+func TestRateLimiter_Cancel(t *testing.T) {
+	l := newRateLimiter(1024)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, 1024*10); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	m := &Manager{}
+	WithBandwidthLimit(0)(m)
+	r := bytes.NewReader(nil)
+	if m.limitReader(context.Background(), r) != r {
+		t.Error("Reader must not be wrapped without the limit")
+	}
+}
@@ -0,0 +1,493 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DefaultBiSyncMaxDelete is the default maximum percentage of the files on a side
+// deleted by a BiSync.
+const DefaultBiSyncMaxDelete = 50
+
+var errNoBiSyncState = errors.New("BiSync requires WithBiSyncState")
+
+// ErrBiSyncTooManyDeletes is returned by BiSync refusing to delete the files on a side,
+// because the other side is listed as empty or the deletions exceed WithBiSyncMaxDelete.
+var ErrBiSyncTooManyDeletes = errors.New("too many deletions")
+
+// Side is a side of BiSync.
+type Side int
+
+const (
+	// SideA is the first path of BiSync.
+	SideA Side = iota
+	// SideB is the second path of BiSync.
+	SideB
+)
+
+// ConflictPolicy is the policy of BiSync on the files changed on both sides
+// since the previous run. The file of the winning side is copied to the other side,
+// or the file of the other side is deleted if the winning side deleted the file.
+// The zero value is NewerWins.
+type ConflictPolicy struct {
+	resolve func(a, b *FileInfo) Side
+}
+
+var (
+	// NewerWins keeps the file modified later. A deletion loses against a modification.
+	NewerWins = ConflictPolicy{resolve: newerWins}
+	// LargerWins keeps the larger file, or the newer one of the same size.
+	// A deletion loses against a modification.
+	LargerWins = ConflictPolicy{resolve: largerWins}
+	// PreferA keeps the change on the side A.
+	PreferA = ConflictPolicy{resolve: func(a, b *FileInfo) Side { return SideA }}
+	// PreferB keeps the change on the side B.
+	PreferB = ConflictPolicy{resolve: func(a, b *FileInfo) Side { return SideB }}
+)
+
+// ConflictFunc returns the policy to keep the side returned by f.
+// f is called with nil for the side deleting the file.
+// f is called for each conflict in turn before any change is applied.
+func ConflictFunc(f func(a, b *FileInfo) Side) ConflictPolicy {
+	return ConflictPolicy{resolve: f}
+}
+
+func newerWins(a, b *FileInfo) Side {
+	if a == nil || b != nil && b.LastModified.After(a.LastModified) {
+		return SideB
+	}
+	return SideA
+}
+
+func largerWins(a, b *FileInfo) Side {
+	if a == nil || b != nil && b.Size > a.Size {
+		return SideB
+	}
+	if b == nil || a.Size > b.Size {
+		return SideA
+	}
+	return newerWins(a, b)
+}
+
+// biSyncStateFile is the content of the BiSync state file, the files on both sides
+// after the previous run.
+type biSyncStateFile struct {
+	PathA string                    `json:"pathA"`
+	PathB string                    `json:"pathB"`
+	Files [2]map[string]biSyncEntry `json:"files"`
+}
+
+// biSyncEntry is the state of a file on a side.
+type biSyncEntry struct {
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	ETag         string    `json:"etag,omitempty"`
+}
+
+// biSyncSide is the local directory or the S3 path of a side.
+type biSyncSide struct {
+	dir  string
+	path *s3Path
+}
+
+func (s biSyncSide) String() string {
+	if s.path != nil {
+		return s.path.String()
+	}
+	return s.dir
+}
+
+// BiSync propagates the changes of the files in both directions between pathA and pathB,
+// each of which is a local directory or an S3 url.
+// The changes are detected by comparing the files with the state of the previous run
+// stored in the file given by WithBiSyncState. The new and modified files are copied
+// to the other side, and the deleted files are deleted from the other side.
+// The files changed on both sides are resolved by the policy.
+// On the first run without the state, the files on either side are copied to the other side,
+// and the files differing on both sides are resolved by the policy.
+// The failed files are retried on the next run. Both sides are accessed by the client
+// of the destination, and the filters are applied to both sides.
+func (m *Manager) BiSync(ctx context.Context, pathA, pathB string, policy ConflictPolicy) (err error) {
+	if m.biSyncStatePath == "" {
+		return errNoBiSyncState
+	}
+	ctx, done := m.trackCompletion(ctx, pathA, pathB)
+	defer done(&err)
+
+	var sides [2]biSyncSide
+	for i, p := range []string{pathA, pathB} {
+		if sides[i], err = parseBiSyncSide(p); err != nil {
+			return err
+		}
+	}
+	aIsS3, bIsS3 := sides[SideA].path != nil, sides[SideB].path != nil
+	if !aIsS3 && !bIsS3 {
+		return errors.New("local to local sync is not supported")
+	}
+	if err := m.checkPreset(); err != nil {
+		return err
+	}
+	if err := m.checkDirection(aIsS3, bIsS3); err != nil {
+		return err
+	}
+	if err := m.checkDirection(bIsS3, aIsS3); err != nil {
+		return err
+	}
+	prev := m.loadBiSyncState(pathA, pathB)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = m.withErrorPolicy(ctx, cancel)
+
+	var cur [2]map[string]*fileInfo
+	for i := range sides {
+		if cur[i], err = m.listBiSyncSide(ctx, sides[i]); err != nil {
+			return err
+		}
+	}
+
+	var ops []biSyncOp
+	for _, name := range biSyncNames(cur) {
+		from, ok := biSyncWinner(prev, cur, name, policy)
+		if !ok {
+			continue
+		}
+		to := 1 - from
+		ops = append(ops, biSyncOp{name: name, from: from, file: cur[from][name], target: cur[to][name]})
+	}
+	if err := m.checkBiSyncDeletes(prev, cur, sides, ops); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	failed := make(map[string]bool)
+	applied := make(map[string]*fileInfo)
+	wg := &sync.WaitGroup{}
+	errs := newSyncErrors(ctx)
+	workers, stopWorkers := m.startWorkers()
+	for _, op := range ops {
+		op := op
+		wg.Add(1)
+		var size int64
+		if op.file != nil {
+			size = op.file.size
+		}
+		workers.run(size, func() {
+			defer wg.Done()
+			var result *fileInfo
+			if err := m.retry(ctx, func(ctx context.Context) (err error) {
+				result, err = m.biSyncApply(ctx, op.file, op.target, sides[op.from], sides[1-op.from])
+				return err
+			}); err != nil {
+				errs.Append(err)
+				mu.Lock()
+				failed[op.name] = true
+				mu.Unlock()
+				return
+			}
+			mu.Lock()
+			applied[op.name] = result
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	stopWorkers()
+	if m.dryrun {
+		return errs.ErrOrNil()
+	}
+
+	// The state is the files listed before the changes, updated by the changes applied,
+	// so that the files changed during the run are detected on the next run.
+	state := newBiSyncState(pathA, pathB, cur)
+	for _, op := range ops {
+		to := 1 - op.from
+		if failed[op.name] {
+			for i := range state.Files {
+				if e, ok := prev.Files[i][op.name]; ok {
+					state.Files[i][op.name] = e
+				} else {
+					delete(state.Files[i], op.name)
+				}
+			}
+		} else if result := applied[op.name]; result == nil {
+			delete(state.Files[to], op.name)
+		} else {
+			state.Files[to][op.name] = newBiSyncEntry(result)
+		}
+	}
+	if werr := m.writeBiSyncState(state); werr != nil {
+		errs.Append(werr)
+	}
+	return errs.ErrOrNil()
+}
+
+// biSyncOp is a change propagated by BiSync, the file of the side from copied over
+// the target file on the other side, or the target file deleted if the file is nil.
+type biSyncOp struct {
+	name         string
+	from         Side
+	file, target *fileInfo
+}
+
+// checkBiSyncDeletes returns ErrBiSyncTooManyDeletes if the ops delete all the files
+// on a side because the other side is listed as empty, e.g. an unmounted directory
+// or a wrong prefix, or delete more than WithBiSyncMaxDelete percent of the files.
+func (m *Manager) checkBiSyncDeletes(prev *biSyncStateFile, cur [2]map[string]*fileInfo, sides [2]biSyncSide, ops []biSyncOp) error {
+	maxDelete := m.biSyncMaxDelete
+	if maxDelete <= 0 {
+		maxDelete = DefaultBiSyncMaxDelete
+	}
+	if maxDelete >= 100 {
+		return nil
+	}
+	var deletes [2]int
+	for _, op := range ops {
+		if op.file == nil {
+			deletes[1-op.from]++
+		}
+	}
+	for i := range sides {
+		other := 1 - i
+		if deletes[i] == 0 {
+			continue
+		}
+		if len(cur[other]) == 0 {
+			return fmt.Errorf("%w: %s is empty", ErrBiSyncTooManyDeletes, sides[other])
+		}
+		if deletes[i]*100 > maxDelete*len(prev.Files[i]) {
+			return fmt.Errorf("%w: %d of %d files on %s", ErrBiSyncTooManyDeletes, deletes[i], len(prev.Files[i]), sides[i])
+		}
+	}
+	return nil
+}
+
+// parseBiSyncSide returns the side of the local directory or the S3 url.
+func parseBiSyncSide(p string) (biSyncSide, error) {
+	u, err := parseURL(p)
+	if err != nil {
+		return biSyncSide{}, err
+	}
+	if !isS3URL(u) {
+		return biSyncSide{dir: p}, nil
+	}
+	path, err := urlToS3Path(u)
+	if err != nil {
+		return biSyncSide{}, err
+	}
+	return biSyncSide{path: path}, nil
+}
+
+// listBiSyncSide returns the files on the side passing the filters.
+func (m *Manager) listBiSyncSide(ctx context.Context, side biSyncSide) (map[string]*fileInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if side.path != nil {
+		return fileInfoChanToMap(m.applyFilters(ctx, m.listS3Files(ctx, side.path, nil)))
+	}
+	return fileInfoChanToMap(m.applyFilters(ctx, m.listLocalFiles(ctx, side.dir, nil)))
+}
+
+// biSyncNames returns the sorted names of the files on either side.
+func biSyncNames(files [2]map[string]*fileInfo) []string {
+	var names []string
+	for name := range files[SideA] {
+		names = append(names, name)
+	}
+	for name := range files[SideB] {
+		if _, ok := files[SideA][name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// biSyncWinner returns the side whose file is propagated to the other side,
+// or false if the file is in sync.
+func biSyncWinner(prev *biSyncStateFile, cur [2]map[string]*fileInfo, name string, policy ConflictPolicy) (Side, bool) {
+	a, b := cur[SideA][name], cur[SideB][name]
+	changedA := biSyncChanged(prev.Files[SideA], name, a)
+	changedB := biSyncChanged(prev.Files[SideB], name, b)
+	switch {
+	case !changedA && !changedB:
+		return 0, false
+	case !changedB:
+		return SideA, true
+	case !changedA:
+		return SideB, true
+	case a == nil && b == nil:
+		return 0, false
+	case a != nil && b != nil && sameBiSyncContent(a, b):
+		return 0, false
+	}
+	resolve := policy.resolve
+	if resolve == nil {
+		resolve = newerWins
+	}
+	var fa, fb *FileInfo
+	if a != nil {
+		fa = a.export()
+	}
+	if b != nil {
+		fb = b.export()
+	}
+	return resolve(fa, fb), true
+}
+
+// biSyncChanged returns whether the file is changed from the previous state,
+// where nil is the file not existing.
+func biSyncChanged(prev map[string]biSyncEntry, name string, file *fileInfo) bool {
+	e, ok := prev[name]
+	if !ok || file == nil {
+		return ok != (file != nil)
+	}
+	if e.Size != file.size {
+		return true
+	}
+	if e.ETag != "" && file.etag != "" {
+		return e.ETag != file.etag
+	}
+	return !e.LastModified.Equal(file.lastModified)
+}
+
+// sameBiSyncContent returns whether the files on both sides are considered the same.
+// S3 stores the modification time in seconds.
+func sameBiSyncContent(a, b *fileInfo) bool {
+	if a.size != b.size {
+		return false
+	}
+	if a.etag != "" && b.etag != "" {
+		return a.etag == b.etag
+	}
+	return a.lastModified.Truncate(time.Second).Equal(b.lastModified.Truncate(time.Second))
+}
+
+// biSyncApply copies the file to the other side, or deletes the target file
+// if the file is deleted. It returns the file on the other side after the copy,
+// or nil after the deletion.
+func (m *Manager) biSyncApply(ctx context.Context, file, target *fileInfo, from, to biSyncSide) (*fileInfo, error) {
+	if file == nil {
+		if to.path != nil {
+			return nil, m.deleteRemote(ctx, target, to.path)
+		}
+		return nil, m.deleteLocal(ctx, target, to.dir)
+	}
+	file.overwritten = target
+	var err error
+	switch {
+	case from.path != nil && to.path != nil:
+		err = m.copyS3ToS3(ctx, file, from.path, to.path)
+	case from.path != nil:
+		err = m.download(ctx, file, from.path, to.dir)
+	default:
+		err = m.upload(ctx, file, from.dir, to.path)
+	}
+	if err != nil || m.dryrun {
+		return nil, err
+	}
+	return m.statBiSyncFile(ctx, file, to)
+}
+
+// statBiSyncFile returns the copied file on the side.
+// The S3 objects get the modification time and the ETag on the upload.
+func (m *Manager) statBiSyncFile(ctx context.Context, file *fileInfo, side biSyncSide) (*fileInfo, error) {
+	if side.path == nil {
+		filename, _ := m.localFilename(side.dir, file.name)
+		stat, err := os.Lstat(filename)
+		if err != nil {
+			return nil, err
+		}
+		return &fileInfo{name: file.name, size: stat.Size(), lastModified: stat.ModTime()}, nil
+	}
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
+	head, err := m.client(ctx, side.path).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(side.path.bucket),
+		Key:                  aws.String(objectKey(side.path.bucketPrefix, file.destKeyName())),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{
+		name:         file.name,
+		size:         aws.Int64Value(head.ContentLength),
+		lastModified: aws.TimeValue(head.LastModified),
+		etag:         aws.StringValue(head.ETag),
+	}, nil
+}
+
+// loadBiSyncState returns the state of the previous run between the paths,
+// or the empty state if not exists.
+func (m *Manager) loadBiSyncState(pathA, pathB string) *biSyncStateFile {
+	b, err := ioutil.ReadFile(m.biSyncStatePath)
+	if err == nil {
+		var prev biSyncStateFile
+		if err = json.Unmarshal(b, &prev); err != nil {
+			println("Ignoring the BiSync state file", err.Error())
+		} else if prev.PathA != pathA || prev.PathB != pathB {
+			println("Ignoring the BiSync state file of", prev.PathA, "and", prev.PathB)
+		} else {
+			return &prev
+		}
+	} else if !os.IsNotExist(err) {
+		println("Ignoring the BiSync state file", err.Error())
+	}
+	return &biSyncStateFile{PathA: pathA, PathB: pathB}
+}
+
+// newBiSyncState returns the state of the files on both sides.
+func newBiSyncState(pathA, pathB string, files [2]map[string]*fileInfo) *biSyncStateFile {
+	state := &biSyncStateFile{PathA: pathA, PathB: pathB}
+	for i := range files {
+		state.Files[i] = make(map[string]biSyncEntry, len(files[i]))
+		for name, f := range files[i] {
+			state.Files[i][name] = newBiSyncEntry(f)
+		}
+	}
+	return state
+}
+
+// newBiSyncEntry returns the state of the file.
+func newBiSyncEntry(f *fileInfo) biSyncEntry {
+	return biSyncEntry{
+		Size:         f.size,
+		LastModified: f.lastModified,
+		ETag:         f.etag,
+	}
+}
+
+// writeBiSyncState writes the state to the BiSync state file. The file is replaced
+// atomically since the truncated state would be read as the first run, which restores
+// the files deleted on either side.
+func (m *Manager) writeBiSyncState(state *biSyncStateFile) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeStateFile(m.biSyncStatePath, b)
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestBiSyncWinner(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	synced := &biSyncStateFile{Files: [2]map[string]biSyncEntry{
+		{"f": {Size: 1, LastModified: t0}},
+		{"f": {Size: 1, LastModified: t0, ETag: "e0"}},
+	}}
+	empty := &biSyncStateFile{}
+	unchangedA := &fileInfo{size: 1, lastModified: t0}
+	unchangedB := &fileInfo{size: 1, lastModified: t0, etag: "e0"}
+	modifiedA := &fileInfo{size: 2, lastModified: t1}
+	modifiedB := &fileInfo{size: 3, lastModified: t0, etag: "e1"}
+
+	testCases := map[string]struct {
+		prev   *biSyncStateFile
+		a, b   *fileInfo
+		policy ConflictPolicy
+		side   Side
+		sync   bool
+	}{
+		"Unchanged":      {prev: synced, a: unchangedA, b: unchangedB},
+		"ModifiedA":      {prev: synced, a: modifiedA, b: unchangedB, side: SideA, sync: true},
+		"ModifiedB":      {prev: synced, a: unchangedA, b: modifiedB, side: SideB, sync: true},
+		"DeletedA":       {prev: synced, b: unchangedB, side: SideA, sync: true},
+		"DeletedBoth":    {prev: synced},
+		"NewA":           {prev: empty, a: modifiedA, side: SideA, sync: true},
+		"NewB":           {prev: empty, b: modifiedB, side: SideB, sync: true},
+		"NewSame":        {prev: empty, a: unchangedA, b: &fileInfo{size: 1, lastModified: t0.Add(time.Millisecond)}},
+		"NewerWins":      {prev: synced, a: modifiedA, b: modifiedB, policy: NewerWins, side: SideA, sync: true},
+		"DefaultPolicy":  {prev: synced, a: modifiedA, b: modifiedB, side: SideA, sync: true},
+		"LargerWins":     {prev: synced, a: modifiedA, b: modifiedB, policy: LargerWins, side: SideB, sync: true},
+		"PreferB":        {prev: synced, a: modifiedA, b: modifiedB, policy: PreferB, side: SideB, sync: true},
+		"DeleteVsModify": {prev: synced, a: modifiedA, policy: NewerWins, side: SideA, sync: true},
+		"PreferDelete":   {prev: synced, a: modifiedA, policy: PreferB, side: SideB, sync: true},
+		"ConflictFunc": {prev: synced, a: modifiedA, b: modifiedB, side: SideB, sync: true,
+			policy: ConflictFunc(func(a, b *FileInfo) Side {
+				if b.ETag == "e1" {
+					return SideB
+				}
+				return SideA
+			}),
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var cur [2]map[string]*fileInfo
+			for i, f := range []*fileInfo{tc.a, tc.b} {
+				cur[i] = make(map[string]*fileInfo)
+				if f != nil {
+					cur[i]["f"] = f
+				}
+			}
+			side, sync := biSyncWinner(tc.prev, cur, "f", tc.policy)
+			if sync != tc.sync || sync && side != tc.side {
+				t.Errorf("Expected (%v, %v), got (%v, %v)", tc.side, tc.sync, side, sync)
+			}
+		})
+	}
+}
+
+func TestBiSync(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	local := filepath.Join(temp, "local")
+	state := filepath.Join(temp, "state.json")
+
+	s := &dummyFolderS3{dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+		"prefix/a", "prefix/b",
+	}}}}
+
+	t.Run("NoState", func(t *testing.T) {
+		m := New(session.New())
+		m.s3 = s
+		if err := m.BiSync(context.Background(), local, "s3://bucket/prefix", NewerWins); err != errNoBiSyncState {
+			t.Fatalf("Expected %v, got %v", errNoBiSyncState, err)
+		}
+	})
+
+	m := New(session.New(), WithBiSyncState(state))
+	m.s3 = s
+
+	t.Run("DryRun", func(t *testing.T) {
+		m := New(session.New(), WithBiSyncState(state), WithDryRun())
+		m.s3 = s
+		if err := m.BiSync(context.Background(), local, "s3://bucket/prefix", NewerWins); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(state); !os.IsNotExist(err) {
+			t.Errorf("Expected no state file in dry-run, got %v", err)
+		}
+	})
+	t.Run("Initial", func(t *testing.T) {
+		if err := m.BiSync(context.Background(), local, "s3://bucket/prefix", NewerWins); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(s.downloaded)
+		if expected := []string{"prefix/a", "prefix/b"}; !reflect.DeepEqual(expected, s.downloaded) {
+			t.Errorf("Expected %v to be downloaded, got %v", expected, s.downloaded)
+		}
+		if _, err := os.Stat(state); err != nil {
+			t.Errorf("Expected the state file: %v", err)
+		}
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		s.downloaded = nil
+		if err := m.BiSync(context.Background(), local, "s3://bucket/prefix", NewerWins); err != nil {
+			t.Fatal(err)
+		}
+		if len(s.downloaded) != 0 || len(s.put) != 0 || len(s.deleted) != 0 {
+			t.Errorf("Expected no change, got downloaded %v, put %v, deleted %v", s.downloaded, s.put, s.deleted)
+		}
+	})
+	t.Run("DeletedLocal", func(t *testing.T) {
+		if err := os.Remove(filepath.Join(local, "a")); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.BiSync(context.Background(), local, "s3://bucket/prefix", NewerWins); err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"prefix/a"}; !reflect.DeepEqual(expected, s.deleted) {
+			t.Errorf("Expected %v to be deleted, got %v", expected, s.deleted)
+		}
+		if len(s.downloaded) != 0 {
+			t.Errorf("Expected no download, got %v", s.downloaded)
+		}
+	})
+	t.Run("EmptySide", func(t *testing.T) {
+		s.deleted = nil
+		if err := os.RemoveAll(local); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.BiSync(context.Background(), local, "s3://bucket/prefix", NewerWins); !errors.Is(err, ErrBiSyncTooManyDeletes) {
+			t.Fatalf("Expected %v, got %v", ErrBiSyncTooManyDeletes, err)
+		}
+		if len(s.deleted) != 0 {
+			t.Errorf("Expected no deletion, got %v", s.deleted)
+		}
+	})
+}
+
+func TestCheckBiSyncDeletes(t *testing.T) {
+	prev := &biSyncStateFile{Files: [2]map[string]biSyncEntry{
+		{"a": {}, "b": {}, "c": {}, "d": {}},
+		{"a": {}, "b": {}, "c": {}, "d": {}},
+	}}
+	all := map[string]*fileInfo{"a": {}, "b": {}, "c": {}, "d": {}}
+	remaining := map[string]*fileInfo{"c": {}, "d": {}}
+	deleteA := func(names ...string) []biSyncOp {
+		var ops []biSyncOp
+		for _, name := range names {
+			ops = append(ops, biSyncOp{name: name, from: SideB, target: &fileInfo{}})
+		}
+		return ops
+	}
+
+	testCases := map[string]struct {
+		maxDelete int
+		cur       [2]map[string]*fileInfo
+		ops       []biSyncOp
+		err       bool
+	}{
+		"WithinDefault": {cur: [2]map[string]*fileInfo{all, remaining}, ops: deleteA("a", "b")},
+		"OverDefault":   {cur: [2]map[string]*fileInfo{all, {"d": {}}}, ops: deleteA("a", "b", "c"), err: true},
+		"OverMax":       {maxDelete: 25, cur: [2]map[string]*fileInfo{all, remaining}, ops: deleteA("a", "b"), err: true},
+		"EmptySide":     {maxDelete: 99, cur: [2]map[string]*fileInfo{all, {}}, ops: deleteA("a", "b", "c", "d"), err: true},
+		"Unlimited":     {maxDelete: 100, cur: [2]map[string]*fileInfo{all, {}}, ops: deleteA("a", "b", "c", "d")},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), WithBiSyncMaxDelete(tc.maxDelete))
+			err := m.checkBiSyncDeletes(prev, tc.cur, [2]biSyncSide{{dir: "a"}, {dir: "b"}}, tc.ops)
+			if tc.err != errors.Is(err, ErrBiSyncTooManyDeletes) {
+				t.Errorf("Expected error %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
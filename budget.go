@@ -0,0 +1,246 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// DefaultTimeBudgetMargin is the default remaining time to stop starting
+// new operations in SyncWithTimeBudget.
+const DefaultTimeBudgetMargin = 30 * time.Second
+
+// CheckpointStore persists the continuation point of SyncWithTimeBudget.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or empty string if not saved.
+	Load(ctx context.Context) (string, error)
+	// Save saves the checkpoint. Empty checkpoint clears the saved one.
+	Save(ctx context.Context, checkpoint string) error
+}
+
+type timeBudgetKey struct{}
+
+// timeBudget limits the operations of the sync to the remaining time.
+type timeBudget struct {
+	remaining func() time.Duration
+	margin    time.Duration
+	// after is the checkpoint of the previous run. Source files up to it are skipped.
+	after string
+
+	mu        sync.Mutex
+	last      string
+	exhausted bool
+}
+
+// timeBudgetFrom returns the time budget of the SyncWithTimeBudget call, or nil.
+func timeBudgetFrom(ctx context.Context) *timeBudget {
+	b, _ := ctx.Value(timeBudgetKey{}).(*timeBudget)
+	return b
+}
+
+// done returns whether the source file has been processed by the previous run.
+func (b *timeBudget) done(name string) bool {
+	return b.after != "" && name <= b.after
+}
+
+// limit passes the operations until the remaining time becomes shorter than the margin.
+// The operations must be sent in the lexicographic order of the source names.
+func (b *timeBudget) limit(ctx context.Context, ops chan *fileOp) chan *fileOp {
+	c := make(chan *fileOp)
+
+	go func() {
+		defer close(c)
+		for op := range ops {
+			if b.remaining() < b.margin {
+				b.mu.Lock()
+				b.exhausted = true
+				b.mu.Unlock()
+				// Unblock the listing until the sync is canceled.
+				go func() {
+					for range ops {
+					}
+				}()
+				return
+			}
+			if op.err == nil {
+				b.mu.Lock()
+				if op.name > b.last {
+					b.last = op.name
+				}
+				b.mu.Unlock()
+			}
+			select {
+			case c <- op:
+			case <-ctx.Done():
+				go func() {
+					for range ops {
+					}
+				}()
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// SyncWithTimeBudget syncs the files like Sync, but stops starting new operations
+// when the remaining time returned by the given function becomes shorter than
+// the margin (DefaultTimeBudgetMargin by default), for the environment with
+// the limited execution time like AWS Lambda.
+// When stopped, the continuation point is saved to the store, and the next call
+// resumes the sync from it. It returns true when the whole sync has finished and
+// the continuation point is cleared.
+// If any of the operations failed, the continuation point is not advanced.
+func (m *Manager) SyncWithTimeBudget(ctx context.Context, source, dest string, remaining func() time.Duration, store CheckpointStore) (bool, error) {
+	after, err := store.Load(ctx)
+	if err != nil {
+		return false, err
+	}
+	margin := m.timeBudgetMargin
+	if margin <= 0 {
+		margin = DefaultTimeBudgetMargin
+	}
+	b := &timeBudget{remaining: remaining, margin: margin, after: after, last: after}
+	if err := m.Sync(context.WithValue(ctx, timeBudgetKey{}, b), source, dest); err != nil {
+		return false, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.exhausted {
+		return false, store.Save(ctx, b.last)
+	}
+	return true, store.Save(ctx, "")
+}
+
+// S3CheckpointStore is the CheckpointStore saving the checkpoint to the s3 object.
+type S3CheckpointStore struct {
+	s3     s3iface.S3API
+	bucket string
+	key    string
+}
+
+// NewS3CheckpointStore returns a new S3CheckpointStore.
+func NewS3CheckpointStore(client s3iface.S3API, bucket, key string) *S3CheckpointStore {
+	return &S3CheckpointStore{
+		s3:     client,
+		bucket: bucket,
+		key:    key,
+	}
+}
+
+// Load implements CheckpointStore.
+func (s *S3CheckpointStore) Load(ctx context.Context) (string, error) {
+	out, err := s.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer out.Body.Close()
+	b, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Save implements CheckpointStore.
+func (s *S3CheckpointStore) Save(ctx context.Context, checkpoint string) error {
+	if checkpoint == "" {
+		_, err := s.s3.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(s.key),
+		})
+		return err
+	}
+	_, err := s.s3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+		Body:   strings.NewReader(checkpoint),
+	})
+	return err
+}
+
+// DynamoDBCheckpointStore is the CheckpointStore saving the checkpoint to the DynamoDB item.
+// The table must have the string partition key named "id".
+type DynamoDBCheckpointStore struct {
+	dynamodb dynamodbiface.DynamoDBAPI
+	table    string
+	id       string
+}
+
+// NewDynamoDBCheckpointStore returns a new DynamoDBCheckpointStore saving the checkpoint
+// to the item of the given id.
+func NewDynamoDBCheckpointStore(client dynamodbiface.DynamoDBAPI, table, id string) *DynamoDBCheckpointStore {
+	return &DynamoDBCheckpointStore{
+		dynamodb: client,
+		table:    table,
+		id:       id,
+	}
+}
+
+func (s *DynamoDBCheckpointStore) key() map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id": {S: aws.String(s.id)},
+	}
+}
+
+// Load implements CheckpointStore.
+func (s *DynamoDBCheckpointStore) Load(ctx context.Context) (string, error) {
+	out, err := s.dynamodb.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.table),
+		Key:            s.key(),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	if v, ok := out.Item["checkpoint"]; ok {
+		return aws.StringValue(v.S), nil
+	}
+	return "", nil
+}
+
+// Save implements CheckpointStore.
+func (s *DynamoDBCheckpointStore) Save(ctx context.Context, checkpoint string) error {
+	if checkpoint == "" {
+		_, err := s.dynamodb.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(s.table),
+			Key:       s.key(),
+		})
+		return err
+	}
+	item := s.key()
+	item["checkpoint"] = &dynamodb.AttributeValue{S: aws.String(checkpoint)}
+	_, err := s.dynamodb.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      item,
+	})
+	return err
+}
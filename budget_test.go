@@ -0,0 +1,138 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyBudgetS3 struct {
+	dummyKeyRangeS3
+	mu         sync.Mutex
+	downloaded []string
+}
+
+func (s *dummyBudgetS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.downloaded = append(s.downloaded, *in.Key)
+	return &s3.GetObjectOutput{
+		Body:          ioutil.NopCloser(strings.NewReader("a")),
+		ContentLength: aws.Int64(1),
+	}, nil
+}
+
+type memoryCheckpointStore struct {
+	checkpoint string
+}
+
+func (s *memoryCheckpointStore) Load(context.Context) (string, error) {
+	return s.checkpoint, nil
+}
+
+func (s *memoryCheckpointStore) Save(_ context.Context, checkpoint string) error {
+	s.checkpoint = checkpoint
+	return nil
+}
+
+func TestSyncWithTimeBudget(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+		"prefix/a", "prefix/b", "prefix/c", "prefix/d", "prefix/e",
+	}}}
+	m := New(session.New(), WithParallel(1))
+	m.s3 = s
+	store := &memoryCheckpointStore{}
+
+	// Time runs out on the third operation.
+	var calls int
+	remaining := func() time.Duration {
+		if calls++; calls > 2 {
+			return 0
+		}
+		return time.Hour
+	}
+	done, err := m.SyncWithTimeBudget(context.Background(), "s3://bucket/prefix", temp, remaining, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done {
+		t.Error("Sync must not be done")
+	}
+	if store.checkpoint != "b" {
+		t.Errorf("Expected checkpoint b, got %s", store.checkpoint)
+	}
+	sort.Strings(s.downloaded)
+	if expected := []string{"prefix/a", "prefix/b"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected %v to be downloaded, got %v", expected, s.downloaded)
+	}
+
+	// Remove the synced files to check that the next run skips them anyway.
+	os.Remove(filepath.Join(temp, "a"))
+	os.Remove(filepath.Join(temp, "b"))
+	s.downloaded = nil
+	done, err = m.SyncWithTimeBudget(context.Background(), "s3://bucket/prefix", temp,
+		func() time.Duration { return time.Hour }, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done {
+		t.Error("Sync must be done")
+	}
+	if store.checkpoint != "" {
+		t.Errorf("Checkpoint must be cleared, got %s", store.checkpoint)
+	}
+	sort.Strings(s.downloaded)
+	if expected := []string{"prefix/c", "prefix/d", "prefix/e"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected %v to be downloaded, got %v", expected, s.downloaded)
+	}
+}
+
+type dummyCheckpointS3 struct {
+	dummyKeyRangeS3
+}
+
+func (s *dummyCheckpointS3) GetObjectWithContext(aws.Context, *s3.GetObjectInput, ...request.Option) (*s3.GetObjectOutput, error) {
+	return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+}
+
+func TestS3CheckpointStore_NotFound(t *testing.T) {
+	checkpoint, err := NewS3CheckpointStore(&dummyCheckpointS3{}, "bucket", "checkpoint").Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint != "" {
+		t.Errorf("Expected empty checkpoint, got %s", checkpoint)
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var (
+	errChecksumUnavailable  = errors.New("checksum is not available")
+	errChecksumIncomparable = errors.New("checksum of multipart object is not comparable")
+)
+
+// checksumAlgorithms is the list of the supported checksum algorithms in the order of preference.
+var checksumAlgorithms = []string{
+	s3.ChecksumAlgorithmSha256,
+	s3.ChecksumAlgorithmSha1,
+	s3.ChecksumAlgorithmCrc32c,
+	s3.ChecksumAlgorithmCrc32,
+}
+
+func newChecksumHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case s3.ChecksumAlgorithmSha256:
+		return sha256.New()
+	case s3.ChecksumAlgorithmSha1:
+		return sha1.New()
+	case s3.ChecksumAlgorithmCrc32c:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case s3.ChecksumAlgorithmCrc32:
+		return crc32.NewIEEE()
+	}
+	return nil
+}
+
+// objectChecksums returns the function to get the base64 encoded checksums
+// of the object by HeadObject.
+// The checksums are not included in the listing, so they are requested only
+// when the comparator needs them.
+func (m *Manager) objectChecksums(ctx context.Context, path *s3Path, key string) func() (map[string]string, error) {
+	return func() (map[string]string, error) {
+		head, err := m.client(ctx, path).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket:               aws.String(path.bucket),
+			Key:                  aws.String(key),
+			ChecksumMode:         aws.String(s3.ChecksumModeEnabled),
+			SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+			SSECustomerKey:       m.sseCustomerKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return headChecksums(head), nil
+	}
+}
+
+// headChecksums returns the base64 encoded checksums of the object in the HeadObject output.
+func headChecksums(head *s3.HeadObjectOutput) map[string]string {
+	sums := make(map[string]string)
+	for algorithm, sum := range map[string]*string{
+		s3.ChecksumAlgorithmSha256: head.ChecksumSHA256,
+		s3.ChecksumAlgorithmSha1:   head.ChecksumSHA1,
+		s3.ChecksumAlgorithmCrc32c: head.ChecksumCRC32C,
+		s3.ChecksumAlgorithmCrc32:  head.ChecksumCRC32,
+	} {
+		if sum != nil {
+			sums[algorithm] = *sum
+		}
+	}
+	return sums
+}
+
+// calcChecksum calculates the base64 encoded checksum of the contents of the file.
+func (f *FileInfo) calcChecksum(algorithm string) (string, error) {
+	h := newChecksumHash(algorithm)
+	if h == nil {
+		return "", errChecksumUnavailable
+	}
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// calcPartChecksum calculates the checksum of the multipart object, which is
+// the base64 encoded checksum of the checksums of the parts suffixed by the number of the parts.
+func (f *FileInfo) calcPartChecksum(algorithm string, partSize int64) (string, error) {
+	h := newChecksumHash(algorithm)
+	if h == nil {
+		return "", errChecksumUnavailable
+	}
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	var sums []byte
+	var parts int
+	for {
+		ph := newChecksumHash(algorithm)
+		n, err := io.CopyN(ph, r, partSize)
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if n > 0 {
+			sums = ph.Sum(sums)
+			parts++
+		}
+		if n < partSize {
+			break
+		}
+	}
+	h.Write(sums)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(parts), nil
+}
+
+// sameChecksum compares the checksum of the object stored in S3 with
+// the one calculated from the contents of the other file.
+// The first algorithm available on the object is used.
+func sameChecksum(src, dst *FileInfo, algorithms []string) (bool, error) {
+	obj, file := dst, src
+	if obj.checksums == nil {
+		obj, file = src, dst
+	}
+	if obj.checksums == nil || file.open == nil {
+		return false, errChecksumUnavailable
+	}
+	sums, err := obj.checksums()
+	if err != nil {
+		return false, err
+	}
+	for _, algorithm := range algorithms {
+		sum, ok := sums[algorithm]
+		if !ok {
+			continue
+		}
+		if strings.Contains(sum, "-") {
+			// Checksum of checksums of the parts.
+			return false, errChecksumIncomparable
+		}
+		calc, err := file.calcChecksum(algorithm)
+		if err != nil {
+			return false, err
+		}
+		return calc == sum, nil
+	}
+	return false, errChecksumUnavailable
+}
+
+// sameStoredChecksum compares the checksums stored in S3 of both objects.
+func sameStoredChecksum(src, dst *FileInfo, algorithm string) (bool, error) {
+	if src.checksums == nil || dst.checksums == nil {
+		return false, errChecksumUnavailable
+	}
+	var sums [2]string
+	for i, f := range []*FileInfo{src, dst} {
+		s, err := f.checksums()
+		if err != nil {
+			return false, err
+		}
+		sum, ok := s[algorithm]
+		if !ok {
+			return false, errChecksumUnavailable
+		}
+		sums[i] = sum
+	}
+	if strings.Contains(sums[0], "-") != strings.Contains(sums[1], "-") {
+		// Same contents have different checksums if uploaded in different ways.
+		return false, errChecksumIncomparable
+	}
+	return sums[0] == sums[1], nil
+}
+
+// ChecksumPolicy is the policy of the checksums to compare and store the objects.
+type ChecksumPolicy int
+
+const (
+	// ChecksumDefault compares the MD5 based ETag by the checksum comparators,
+	// and the additional checksums only if the ETag can't be compared.
+	ChecksumDefault ChecksumPolicy = iota
+	// ChecksumSHA256Only compares only the SHA-256 checksums of the objects without MD5
+	// for the environments where MD5 is unavailable or disallowed, e.g. FIPS mode.
+	// The objects are uploaded and copied with the SHA-256 checksum to be compared later,
+	// and the downloaded files are validated by the SHA-256 checksums of the objects.
+	ChecksumSHA256Only
+)
+
+// SHA256ChecksumComparator syncs the file if the size or the SHA-256 checksum differs.
+// ETag is never compared, and MD5 is never calculated.
+// The SHA-256 checksum of the object is requested by HeadObject, so the objects
+// must be uploaded with the SHA-256 checksum, e.g. by WithChecksumPolicy(ChecksumSHA256Only).
+// If the checksums can't be compared, it falls back to DefaultComparator.
+var SHA256ChecksumComparator Comparator = ComparatorFunc(func(src, dst *FileInfo) bool {
+	if src.Size != dst.Size {
+		return true
+	}
+	var same bool
+	var err error
+	if src.checksums != nil && dst.checksums != nil {
+		same, err = sameStoredChecksum(src, dst, s3.ChecksumAlgorithmSha256)
+	} else {
+		same, err = sameChecksum(src, dst, []string{s3.ChecksumAlgorithmSha256})
+	}
+	if err != nil {
+		return DefaultComparator.ShouldSync(src, dst)
+	}
+	return !same
+})
+
+// checksumAlgorithm returns the checksum algorithm of the uploaded and copied objects.
+// SHA-256 is used for ChecksumSHA256Only and VerifySHA256.
+func (m *Manager) checksumAlgorithm() *string {
+	if m.checksumPolicy == ChecksumSHA256Only || m.verifyMode&VerifySHA256 != 0 {
+		return aws.String(s3.ChecksumAlgorithmSha256)
+	}
+	return nil
+}
+
+// sha256Checksums returns the request option of an upload sending the SHA-256 checksums
+// calculated from the contents, since the uploader sends only the checksum algorithm.
+// The checksum of each part of the multipart upload is sent with the part,
+// and the checksums of the parts are sent with the completion of the upload.
+func sha256Checksums() request.Option {
+	var mu sync.Mutex
+	parts := make(map[int64]*string)
+	return func(r *request.Request) {
+		r.Handlers.Build.PushFront(func(r *request.Request) {
+			switch in := r.Params.(type) {
+			case *s3.PutObjectInput:
+				in.ChecksumSHA256, r.Error = readerChecksum(in.Body)
+			case *s3.UploadPartInput:
+				sum, err := readerChecksum(in.Body)
+				if err != nil {
+					r.Error = err
+					return
+				}
+				in.ChecksumAlgorithm = aws.String(s3.ChecksumAlgorithmSha256)
+				in.ChecksumSHA256 = sum
+				mu.Lock()
+				parts[aws.Int64Value(in.PartNumber)] = sum
+				mu.Unlock()
+			case *s3.CompleteMultipartUploadInput:
+				if in.MultipartUpload == nil {
+					return
+				}
+				mu.Lock()
+				for _, part := range in.MultipartUpload.Parts {
+					part.ChecksumSHA256 = parts[aws.Int64Value(part.PartNumber)]
+				}
+				mu.Unlock()
+			}
+		})
+	}
+}
+
+// readerChecksum returns the base64 encoded SHA-256 checksum of the rest of the body,
+// and rewinds the body to be sent.
+func readerChecksum(body io.ReadSeeker) (*string, error) {
+	h := sha256.New()
+	if body == nil {
+		return aws.String(base64.StdEncoding.EncodeToString(h.Sum(nil))), nil
+	}
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, body); err != nil {
+		return nil, err
+	}
+	if _, err := body.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return aws.String(base64.StdEncoding.EncodeToString(h.Sum(nil))), nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func TestSHA256Checksums(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	const partSize = s3manager.MinUploadPartSize
+	parts := []string{strings.Repeat("a", int(partSize)), "b"}
+	for name, contents := range map[string]string{
+		"single":    "contents",
+		"multipart": strings.Join(parts, ""),
+	} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var putSum string
+	partSums := make(map[int64]string)
+	var completed []*s3.CompletedPart
+	c := s3.New(session.New(), aws.NewConfig().
+		WithRegion("us-east-1").
+		WithCredentials(credentials.AnonymousCredentials))
+	c.Handlers.Send.Clear()
+	c.Handlers.Unmarshal.Clear()
+	c.Handlers.Send.PushBack(func(r *request.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var algorithm *string
+		switch in := r.Params.(type) {
+		case *s3.PutObjectInput:
+			algorithm = in.ChecksumAlgorithm
+			putSum = r.HTTPRequest.Header.Get("X-Amz-Checksum-Sha256")
+		case *s3.CreateMultipartUploadInput:
+			algorithm = in.ChecksumAlgorithm
+			r.Data.(*s3.CreateMultipartUploadOutput).UploadId = aws.String("upload-id")
+		case *s3.UploadPartInput:
+			algorithm = in.ChecksumAlgorithm
+			partSums[*in.PartNumber] = r.HTTPRequest.Header.Get("X-Amz-Checksum-Sha256")
+			r.Data.(*s3.UploadPartOutput).ETag = aws.String("etag")
+		case *s3.CompleteMultipartUploadInput:
+			algorithm = aws.String(s3.ChecksumAlgorithmSha256)
+			completed = in.MultipartUpload.Parts
+		}
+		if aws.StringValue(algorithm) != s3.ChecksumAlgorithmSha256 {
+			r.Error = errors.New("checksum algorithm must be sent")
+			return
+		}
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			// CompleteMultipartUpload fails by the empty body.
+			Body: ioutil.NopCloser(strings.NewReader("<Result></Result>")),
+		}
+	})
+
+	m := New(session.New(), WithChecksumPolicy(ChecksumSHA256Only), WithUploadPartSize(partSize))
+	m.s3 = c
+	for name, size := range map[string]int64{"single": 8, "multipart": partSize + 1} {
+		file := &fileInfo{name: name, path: filepath.Join(temp, name), size: size, local: true}
+		if err := m.upload(context.Background(), file, temp, &s3Path{bucket: "bucket", bucketPrefix: "prefix/"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected := sha256Checksum("contents"); putSum != expected {
+		t.Errorf("Expected the checksum %s to be sent, got %s", expected, putSum)
+	}
+	if len(completed) != len(parts) {
+		t.Fatalf("Expected %d parts, got %d", len(parts), len(completed))
+	}
+	for i, part := range completed {
+		expected := sha256Checksum(parts[i])
+		if sum := partSums[int64(i+1)]; sum != expected {
+			t.Errorf("Expected the checksum %s to be sent with the part %d, got %s", expected, i+1, sum)
+		}
+		if sum := aws.StringValue(part.ChecksumSHA256); sum != expected {
+			t.Errorf("Expected the checksum %s of the part %d to be completed, got %s", expected, i+1, sum)
+		}
+	}
+}
+
+func TestReaderChecksum(t *testing.T) {
+	r := bytes.NewReader([]byte("skipped contents"))
+	if _, err := r.Seek(8, 0); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := readerChecksum(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := sha256Checksum("contents"); aws.StringValue(sum) != expected {
+		t.Errorf("Expected %s, got %s", expected, aws.StringValue(sum))
+	}
+	if b, _ := ioutil.ReadAll(r); string(b) != "contents" {
+		t.Errorf("Expected the body to be rewound, got %q", b)
+	}
+}
+
+func TestSHA256Checksums_Download(t *testing.T) {
+	// Contents of the objects of dummyBudgetS3 is "a".
+	for name, tc := range map[string]struct {
+		sum string
+		err bool
+	}{
+		"Match":    {sha256Checksum("a"), false},
+		"Mismatch": {sha256Checksum("b"), true},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyVerifyS3{
+				dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}},
+				head:          &s3.HeadObjectOutput{ChecksumSHA256: aws.String(tc.sum)},
+			}
+			m := New(session.New(), WithChecksumPolicy(ChecksumSHA256Only))
+			m.s3 = s
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			if tc.err != errors.Is(err, ErrVerification) {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// clientPool is the pool of the clients of the regions of the buckets.
+// The clients are created on demand from the base clients and reused
+// by all requests to the buckets in the same region.
+type clientPool struct {
+	mu      sync.Mutex
+	regions map[string]string
+	clients map[clientPoolKey]*s3.S3
+	// bucketRegion returns the region of the bucket.
+	bucketRegion func(ctx context.Context, client s3iface.S3API, bucket string) (string, error)
+}
+
+type clientPoolKey struct {
+	base   *s3.S3
+	region string
+}
+
+func newClientPool() *clientPool {
+	return &clientPool{
+		regions: make(map[string]string),
+		clients: make(map[clientPoolKey]*s3.S3),
+		bucketRegion: func(ctx context.Context, client s3iface.S3API, bucket string) (string, error) {
+			return s3manager.GetBucketRegionWithClient(ctx, client, bucket)
+		},
+	}
+}
+
+// get returns the client of the bucket region derived from the base client.
+// The base client is returned as is if it is not *s3.S3, uses the custom endpoint,
+// the bucket is an ARN, or the region of the bucket can't be determined.
+func (p *clientPool) get(ctx context.Context, base s3iface.S3API, bucket string) s3iface.S3API {
+	c, ok := base.(*s3.S3)
+	if !ok || aws.StringValue(c.Config.Endpoint) != "" || arn.IsARN(bucket) {
+		return base
+	}
+
+	p.mu.Lock()
+	region, ok := p.regions[bucket]
+	p.mu.Unlock()
+	if !ok {
+		var err error
+		if region, err = p.bucketRegion(ctx, c, bucket); err != nil {
+			return base
+		}
+		p.mu.Lock()
+		p.regions[bucket] = region
+		p.mu.Unlock()
+	}
+	if region == aws.StringValue(c.Config.Region) {
+		return base
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := clientPoolKey{base: c, region: region}
+	if rc, ok := p.clients[key]; ok {
+		return rc
+	}
+	// The copy of the config shares the credentials and the HTTP client,
+	// so the connections are pooled across the regions.
+	sess, err := session.NewSession(c.Config.Copy(aws.NewConfig().WithRegion(region)))
+	if err != nil {
+		return base
+	}
+	rc := s3.New(sess)
+	// The handlers of the base client, e.g. read-only mode and the custom ones, are kept.
+	rc.Handlers = c.Handlers.Copy()
+	p.clients[key] = rc
+	return rc
+}
+
+// regionalClient returns the client of the region of the bucket
+// if WithRegionalClients is enabled, otherwise the base client.
+func (m *Manager) regionalClient(ctx context.Context, base s3iface.S3API, bucket string) s3iface.S3API {
+	if m.clientPool == nil {
+		return base
+	}
+	return m.clientPool.get(ctx, base, bucket)
+}
+
+// destClient returns the client accessing the destination bucket.
+func (m *Manager) destClient(ctx context.Context, bucket string) s3iface.S3API {
+	return m.regionalClient(ctx, m.s3, bucket)
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+func TestClientPool(t *testing.T) {
+	newPool := func(lookups map[string]int) *clientPool {
+		p := newClientPool()
+		p.bucketRegion = func(ctx context.Context, client s3iface.S3API, bucket string) (string, error) {
+			lookups[bucket]++
+			switch bucket {
+			case "eu-bucket", "eu-bucket2":
+				return "eu-west-1", nil
+			case "us-bucket":
+				return "us-east-1", nil
+			}
+			return "", errors.New("NotFound")
+		}
+		return p
+	}
+	newBase := func(cfg *aws.Config) *s3.S3 {
+		return s3.New(session.New(), aws.NewConfig().
+			WithRegion("us-east-1").
+			WithCredentials(credentials.NewStaticCredentials("id", "secret", "")), cfg)
+	}
+	ctx := context.Background()
+
+	t.Run("Regional", func(t *testing.T) {
+		lookups := make(map[string]int)
+		p := newPool(lookups)
+		base := newBase(nil)
+
+		c, ok := p.get(ctx, base, "eu-bucket").(*s3.S3)
+		if !ok || c == base {
+			t.Fatal("Expected the client of the bucket region")
+		}
+		if region := aws.StringValue(c.Config.Region); region != "eu-west-1" {
+			t.Errorf("Expected region eu-west-1, got %s", region)
+		}
+		if c2 := p.get(ctx, base, "eu-bucket"); c2 != c {
+			t.Error("Expected the client to be reused")
+		}
+		if c2 := p.get(ctx, base, "eu-bucket2"); c2 != c {
+			t.Error("Expected the client to be shared by the buckets in the same region")
+		}
+		if lookups["eu-bucket"] != 1 {
+			t.Errorf("Expected the region to be looked up once, got %d", lookups["eu-bucket"])
+		}
+		if c := p.get(ctx, base, "us-bucket"); c != base {
+			t.Error("Expected the base client for the bucket in the same region")
+		}
+	})
+	t.Run("LookupFailure", func(t *testing.T) {
+		lookups := make(map[string]int)
+		p := newPool(lookups)
+		base := newBase(nil)
+
+		for i := 0; i < 2; i++ {
+			if c := p.get(ctx, base, "unknown"); c != base {
+				t.Error("Expected the base client")
+			}
+		}
+		if lookups["unknown"] != 2 {
+			t.Errorf("Expected the failure not to be cached, got %d lookups", lookups["unknown"])
+		}
+	})
+	t.Run("AsIs", func(t *testing.T) {
+		testCases := map[string]struct {
+			base   s3iface.S3API
+			bucket string
+		}{
+			"NotS3Client":    {&dummyUnusedS3{}, "eu-bucket"},
+			"CustomEndpoint": {newBase(aws.NewConfig().WithEndpoint("http://localhost:4572")), "eu-bucket"},
+			"AccessPoint":    {newBase(nil), "arn:aws:s3:eu-west-1:123456789012:accesspoint/ap"},
+		}
+		for name, tc := range testCases {
+			tc := tc
+			t.Run(name, func(t *testing.T) {
+				lookups := make(map[string]int)
+				p := newPool(lookups)
+				if c := p.get(ctx, tc.base, tc.bucket); c != tc.base {
+					t.Error("Expected the base client")
+				}
+				if len(lookups) != 0 {
+					t.Errorf("Expected no lookup, got %v", lookups)
+				}
+			})
+		}
+	})
+	t.Run("KeepsHandlers", func(t *testing.T) {
+		m := NewWithClients(nil, newBase(nil), WithRegionalClients(), WithReadOnly())
+		m.clientPool.bucketRegion = newPool(make(map[string]int)).bucketRegion
+
+		c := m.destClient(ctx, "eu-bucket")
+		if c == m.s3 {
+			t.Fatal("Expected the client of the bucket region")
+		}
+		_, err := c.PutObjectWithContext(ctx, &s3.PutObjectInput{
+			Bucket: aws.String("eu-bucket"),
+			Key:    aws.String("key"),
+		})
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected %v, got %v", ErrReadOnly, err)
+		}
+	})
+}
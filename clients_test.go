@@ -0,0 +1,83 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyCopyDestS3 struct {
+	dummyKeyRangeS3
+	mu     sync.Mutex
+	copied []string
+}
+
+func (s *dummyCopyDestS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.copied = append(s.copied, aws.StringValue(in.Bucket)+"/"+aws.StringValue(in.Key))
+	return &s3.CopyObjectOutput{}, nil
+}
+
+// dummyUnusedS3 fails the test by panic if any API is called.
+type dummyUnusedS3 struct {
+	s3iface.S3API
+}
+
+func TestNewWithClients(t *testing.T) {
+	t.Run("S3ToS3", func(t *testing.T) {
+		src := &dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}
+		dst := &dummyCopyDestS3{}
+		m := NewWithClients(src, dst, WithParallel(1))
+
+		if err := m.Sync(context.Background(), "s3://src-bucket/prefix", "s3://dst-bucket/prefix"); err != nil {
+			t.Fatal(err)
+		}
+		if len(src.startAfter) == 0 || len(dst.startAfter) == 0 {
+			t.Error("Both of the source and destination must be listed by each client")
+		}
+		sort.Strings(dst.copied)
+		if expected := []string{"dst-bucket/prefix/a", "dst-bucket/prefix/b"}; !reflect.DeepEqual(expected, dst.copied) {
+			t.Errorf("Expected %v to be copied by the destination client, got %v", expected, dst.copied)
+		}
+	})
+	t.Run("S3ToLocal", func(t *testing.T) {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+
+		src := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}}
+		m := NewWithClients(src, &dummyUnusedS3{}, WithParallel(1))
+
+		if err := m.Sync(context.Background(), "s3://src-bucket/prefix", temp); err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"prefix/a"}; !reflect.DeepEqual(expected, src.downloaded) {
+			t.Errorf("Expected %v to be downloaded by the source client, got %v", expected, src.downloaded)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command s3sync syncs the files between S3 and the local disk.
+//
+// Usage:
+//
+//	s3sync [flags] SOURCE DEST
+//
+// SOURCE and DEST are the s3 urls (s3://bucket/prefix) or the local paths.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/gmohmad/s3sync"
+)
+
+// filterFlag is the flag adding the include or exclude filter each time given.
+// The include and exclude filters share the list to keep the order of the flags,
+// since the last matching filter wins.
+type filterFlag struct {
+	include bool
+	options *[]s3sync.Option
+}
+
+func (f filterFlag) String() string {
+	return ""
+}
+
+func (f filterFlag) Set(glob string) error {
+	if f.include {
+		*f.options = append(*f.options, s3sync.WithInclude(glob))
+	} else {
+		*f.options = append(*f.options, s3sync.WithExclude(glob))
+	}
+	return nil
+}
+
+// config is the parsed command line.
+type config struct {
+	source, dest string
+	region       string
+	progress     bool
+	deleteList   string
+	auditPaths   bool
+	options      []s3sync.Option
+}
+
+// parseFlags parses the command line arguments without the command name.
+func parseFlags(args []string, stderr io.Writer) (*config, error) {
+	c := &config{}
+	fs := flag.NewFlagSet("s3sync", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: s3sync [flags] SOURCE DEST")
+		fs.PrintDefaults()
+	}
+
+	del := fs.Bool("delete", false, "delete the destination files not existing in the source")
+	dryRun := fs.Bool("dry-run", false, "show the operations without performing them")
+	parallel := fs.Int("parallel", s3sync.DefaultParallel, "maximum number of the parallel file operations")
+	acl := fs.String("acl", "", "canned ACL of the uploaded and copied objects")
+	storageClass := fs.String("storage-class", "", "storage class of the uploaded and copied objects")
+	fs.StringVar(&c.region, "region", "", "AWS region (defaults to the shared config and environment)")
+	endpoint := fs.String("endpoint", "", "endpoint url of the S3 compatible storage")
+	pathStyle := fs.Bool("path-style", false, "use the path-style addressing for the endpoint")
+	fs.StringVar(&c.deleteList, "delete-list", "", "file to write the paths to be deleted before the deletions start")
+	fs.BoolVar(&c.auditPaths, "audit-paths", false, "report the destination paths problematic on some platforms instead of syncing")
+	fs.BoolVar(&c.progress, "progress", false, "show the progress of the files")
+	var filters []s3sync.Option
+	fs.Var(filterFlag{include: true, options: &filters}, "include", "glob pattern of the files to include (repeatable)")
+	fs.Var(filterFlag{include: false, options: &filters}, "exclude", "glob pattern of the files to exclude (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return nil, flag.ErrHelp
+	}
+	c.source, c.dest = fs.Arg(0), fs.Arg(1)
+
+	c.options = append(c.options, s3sync.WithParallel(*parallel))
+	if *del {
+		c.options = append(c.options, s3sync.WithDelete())
+	}
+	if *dryRun {
+		c.options = append(c.options, s3sync.WithDryRun())
+	}
+	if *acl != "" {
+		c.options = append(c.options, s3sync.WithACL(*acl))
+	}
+	if *storageClass != "" {
+		c.options = append(c.options, s3sync.WithStorageClass(*storageClass))
+	}
+	if *endpoint != "" {
+		c.options = append(c.options, s3sync.WithEndpoint(*endpoint, *pathStyle))
+	}
+	c.options = append(c.options, filters...)
+	if c.progress {
+		c.options = append(c.options, s3sync.WithProgressFunc(progressPrinter(stderr)))
+	}
+	return c, nil
+}
+
+// progressPrinter returns the progress function printing the completed files.
+func progressPrinter(w io.Writer) func(s3sync.ProgressEvent) {
+	return func(e s3sync.ProgressEvent) {
+		if e.Type != s3sync.ProgressFileCompleted {
+			return
+		}
+		status := "done"
+		if e.Err != nil {
+			status = "failed"
+		}
+		if p := e.Progress.Percent(); p >= 0 {
+			fmt.Fprintf(w, "[%5.1f%%] %s %s %s\n", p, e.Op, e.Name, status)
+		} else {
+			fmt.Fprintf(w, "[%d files] %s %s %s\n", e.Progress.Files, e.Op, e.Name, status)
+		}
+	}
+}
+
+func run(args []string, stderr io.Writer) int {
+	c, err := parseFlags(args, stderr)
+	if err != nil {
+		// The usage and the error are printed by the flag set.
+		return 2
+	}
+
+	cfg := aws.NewConfig()
+	if c.region != "" {
+		cfg = cfg.WithRegion(c.region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if c.auditPaths {
+		issues, err := s3sync.New(sess, c.options...).AuditPaths(ctx, c.source, c.dest)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, issue := range issues {
+			fmt.Fprintln(stderr, issue)
+		}
+		if len(issues) > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	options := c.options
+	if c.deleteList != "" {
+		f, err := os.Create(c.deleteList)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer f.Close()
+		options = append(options, s3sync.WithDeleteList(s3sync.WriteDeleteList(f)))
+	}
+
+	if err := s3sync.New(sess, options...).Sync(ctx, c.source, c.dest); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/gmohmad/s3sync"
+)
+
+func TestParseFlags(t *testing.T) {
+	c, err := parseFlags([]string{
+		"--delete", "--parallel", "4", "--region", "us-west-2",
+		"--exclude", "*", "--include", "*.txt", "--audit-paths", "s3://bucket/prefix", "dir",
+	}, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.source != "s3://bucket/prefix" || c.dest != "dir" {
+		t.Errorf("Unexpected source and dest: %s, %s", c.source, c.dest)
+	}
+	if !c.auditPaths {
+		t.Error("Expected audit-paths to be set")
+	}
+	if c.region != "us-west-2" {
+		t.Errorf("Expected region us-west-2, got %s", c.region)
+	}
+	// parallel, delete, exclude and include
+	if len(c.options) != 4 {
+		t.Errorf("Expected 4 options, got %d", len(c.options))
+	}
+}
+
+func TestParseFlags_Usage(t *testing.T) {
+	for name, args := range map[string][]string{
+		"NoDest":      {"s3://bucket"},
+		"UnknownFlag": {"--unknown", "s3://bucket", "dir"},
+	} {
+		args := args
+		t.Run(name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			if _, err := parseFlags(args, stderr); err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(stderr.String(), "Usage: s3sync") {
+				t.Errorf("Expected usage, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func TestProgressPrinter(t *testing.T) {
+	w := &bytes.Buffer{}
+	p := progressPrinter(w)
+	p(s3sync.ProgressEvent{Type: s3sync.ProgressBytesTransferred, Op: "upload", Name: "a"})
+	p(s3sync.ProgressEvent{Type: s3sync.ProgressFileCompleted, Op: "upload", Name: "a",
+		Progress: s3sync.Progress{Files: 1, Bytes: 1, TotalBytes: 4}})
+	p(s3sync.ProgressEvent{Type: s3sync.ProgressFileCompleted, Op: "download", Name: "b",
+		Progress: s3sync.Progress{Files: 2}, Err: errors.New("error")})
+
+	expected := "[ 25.0%] upload a done\n[2 files] download b failed\n"
+	if w.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, w.String())
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// FileInfo is the information of the source or destination file
+// compared to decide whether the file should be synced.
+type FileInfo struct {
+	// Name is the path relative to the sync root.
+	Name string
+	// Path is the local file path or the object key.
+	Path string
+	// Size is the size of the file in bytes.
+	Size int64
+	// LastModified is the modification time of the file.
+	LastModified time.Time
+	// ETag is the entity tag of the object. Empty for local files.
+	ETag string
+
+	open      func() (io.ReadCloser, error)
+	checksums func() (map[string]string, error)
+	head      func() (*s3.HeadObjectOutput, error)
+	// remote is true for s3 objects.
+	remote bool
+}
+
+var errNoContent = errors.New("content is not available")
+
+// Open returns the reader of the local file or the provided content.
+// It returns an error for s3 objects.
+func (f *FileInfo) Open() (io.ReadCloser, error) {
+	if f.open == nil {
+		return nil, errNoContent
+	}
+	return f.open()
+}
+
+// export returns the FileInfo passed to the user provided functions.
+func (f *fileInfo) export() *FileInfo {
+	fi := &FileInfo{
+		Name:         f.name,
+		Path:         f.path,
+		Size:         f.size,
+		LastModified: f.lastModified,
+		ETag:         f.etag,
+		checksums:    f.checksums,
+		head:         f.head,
+		remote:       !f.local && f.provider == nil,
+	}
+	switch {
+	case f.provider != nil:
+		fi.open = f.provider.Open
+	case f.local:
+		path := f.path
+		fi.open = func() (io.ReadCloser, error) {
+			return os.Open(path)
+		}
+	}
+	return fi
+}
+
+// Comparator decides whether the source file should be synced
+// to the existing destination file.
+type Comparator interface {
+	ShouldSync(src, dst *FileInfo) bool
+}
+
+// ComparatorFunc is the function implementing Comparator.
+type ComparatorFunc func(src, dst *FileInfo) bool
+
+// ShouldSync implements Comparator.
+func (f ComparatorFunc) ShouldSync(src, dst *FileInfo) bool {
+	return f(src, dst)
+}
+
+// fileComparator returns the comparator of the Manager comparing the timestamps
+// of the objects shifted by WithClockSkew, ignoring the sizes of the objects
+// compressed by WithCompression, and the sizes of the encrypted objects without
+// the authentication tags.
+// The source version tracked by WithVersionTracking takes precedence over the
+// comparator if it can be compared.
+func (m *Manager) fileComparator() Comparator {
+	if m.clockSkew == 0 && m.compression == nil && m.encryption == nil && !m.versionTracking {
+		return m.comparator
+	}
+	skew := func(f *FileInfo) *FileInfo {
+		if !f.remote || m.clockSkew == 0 {
+			return f
+		}
+		shifted := *f
+		shifted.LastModified = f.LastModified.Add(m.clockSkew)
+		return &shifted
+	}
+	return ComparatorFunc(func(src, dst *FileInfo) bool {
+		if same, ok := sameVersion(src, dst); ok {
+			return !same
+		}
+		return m.comparator.ShouldSync(m.uncompressedSizes(m.encryptedSizes(skew(src), skew(dst))))
+	})
+}
+
+// DefaultComparator syncs the file if the size differs or
+// the source is newer than the destination.
+var DefaultComparator Comparator = ComparatorFunc(func(src, dst *FileInfo) bool {
+	return src.Size != dst.Size || src.LastModified.After(dst.LastModified)
+})
+
+// SizeOnlyComparator syncs the file only if the size differs.
+var SizeOnlyComparator Comparator = ComparatorFunc(func(src, dst *FileInfo) bool {
+	return src.Size != dst.Size
+})
+
+// ExactTimestampsComparator syncs the file if the size or the modification time differs.
+// It is intended for s3 to local sync, since the modification time of the uploaded
+// object is the time of uploading.
+var ExactTimestampsComparator Comparator = ComparatorFunc(func(src, dst *FileInfo) bool {
+	return src.Size != dst.Size || !src.LastModified.Equal(dst.LastModified)
+})
+
+// ChecksumComparator syncs the file if the size or the MD5 checksum differs.
+// This is the same as ETagComparator with the default part size of s3manager.Uploader.
+var ChecksumComparator = ETagComparator(s3manager.DefaultUploadPartSize)
+
+// ETagComparator returns the comparator which syncs the file if the size or the ETag differs.
+// ETag of the local file is calculated from its MD5 checksum. For the object uploaded
+// by multipart upload, it is calculated from the MD5 checksums of the parts split by
+// the given part size, which must be the same as the one used for uploading.
+// If the ETag can't be compared, the additional checksum (SHA256, SHA1, CRC32C or CRC32)
+// of the object is requested by HeadObject and compared.
+// If neither can be compared, it falls back to DefaultComparator.
+func ETagComparator(partSize int64) Comparator {
+	return ComparatorFunc(func(src, dst *FileInfo) bool {
+		if src.Size != dst.Size {
+			return true
+		}
+		same, err := sameETag(src, dst, partSize)
+		if err != nil {
+			same, err = sameChecksum(src, dst, checksumAlgorithms)
+		}
+		if err != nil {
+			return DefaultComparator.ShouldSync(src, dst)
+		}
+		return !same
+	})
+}
+
+var (
+	errETagUnavailable  = errors.New("ETag is not available")
+	errETagIncomparable = errors.New("ETags are not comparable")
+	errPartsMismatch    = errors.New("number of parts doesn't match the part size")
+)
+
+func sameETag(src, dst *FileInfo, partSize int64) (bool, error) {
+	switch {
+	case src.ETag != "" && dst.ETag != "":
+		if src.ETag == dst.ETag {
+			return true, nil
+		}
+		if isMultipartETag(src.ETag) || isMultipartETag(dst.ETag) {
+			// Same contents have different ETags if uploaded in different ways.
+			return false, errETagIncomparable
+		}
+		return false, nil
+	case src.ETag != "":
+		return matchETag(dst, src.ETag, partSize)
+	case dst.ETag != "":
+		return matchETag(src, dst.ETag, partSize)
+	}
+	return false, errETagUnavailable
+}
+
+func isMultipartETag(etag string) bool {
+	return strings.Contains(etag, "-")
+}
+
+// matchETag calculates ETag of the contents of the file in the format of the given ETag
+// and returns whether they match.
+func matchETag(f *FileInfo, etag string, partSize int64) (bool, error) {
+	etag = strings.Trim(etag, `"`)
+	if !isMultipartETag(etag) {
+		calc, err := f.calcETag(0)
+		if err != nil {
+			return false, err
+		}
+		return calc == etag, nil
+	}
+	parts, err := strconv.ParseInt(etag[strings.LastIndex(etag, "-")+1:], 10, 64)
+	if err != nil {
+		return false, err
+	}
+	if partSize <= 0 || (f.Size+partSize-1)/partSize != parts {
+		return false, errPartsMismatch
+	}
+	calc, err := f.calcETag(partSize)
+	if err != nil {
+		return false, err
+	}
+	return calc == etag, nil
+}
+
+// calcETag calculates ETag of the contents of the file.
+// If partSize is zero, it returns hex encoded MD5 checksum of the whole contents,
+// otherwise it returns ETag of the multipart object with the given part size.
+func (f *FileInfo) calcETag(partSize int64) (string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	if partSize == 0 {
+		h := md5.New()
+		if _, err := io.Copy(h, r); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	var sums []byte
+	var parts int
+	for {
+		h := md5.New()
+		n, err := io.CopyN(h, r, partSize)
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if n > 0 {
+			sums = h.Sum(sums)
+			parts++
+		}
+		if n < partSize {
+			break
+		}
+	}
+	sum := md5.Sum(sums)
+	return hex.EncodeToString(sum[:]) + "-" + strconv.Itoa(parts), nil
+}
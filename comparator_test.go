@@ -0,0 +1,199 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComparators(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	filename := filepath.Join(temp, "foo")
+	if err := ioutil.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal("Failed to write", err)
+	}
+	const md5Foo = `"acbd18db4cc2f85cedef654fccc4a4d8"`
+	const md5Bar = `"37b51d194a7513e14ea5a07c6b5a0aa9"`
+
+	t0 := time.Now()
+	t1 := t0.Add(time.Second)
+	local := func(modTime time.Time) *FileInfo {
+		return (&fileInfo{name: "foo", path: filename, size: 3, lastModified: modTime, local: true}).export()
+	}
+	object := func(size int64, modTime time.Time, etag string) *FileInfo {
+		return (&fileInfo{name: "foo", path: "foo", size: size, lastModified: modTime, etag: etag}).export()
+	}
+	withChecksums := func(f *FileInfo, sums map[string]string) *FileInfo {
+		f.checksums = func() (map[string]string, error) {
+			return sums, nil
+		}
+		return f
+	}
+	const sha256Foo = "LCa0a2j/xo/5m0U8HTBBNBNCLXBkg7+g+YpeiGJm564="
+	const crc32Foo = "jHNlIQ=="
+
+	testCases := map[string]struct {
+		cmp      Comparator
+		src, dst *FileInfo
+		expected bool
+	}{
+		"Default_Same":           {DefaultComparator, object(3, t0, md5Foo), object(3, t0, md5Foo), false},
+		"Default_SourceNewer":    {DefaultComparator, object(3, t1, md5Foo), object(3, t0, md5Foo), true},
+		"Default_SourceOlder":    {DefaultComparator, object(3, t0, md5Foo), object(3, t1, md5Foo), false},
+		"Default_SizeDiffers":    {DefaultComparator, object(3, t0, md5Foo), object(4, t0, md5Foo), true},
+		"SizeOnly_SourceNewer":   {SizeOnlyComparator, object(3, t1, md5Foo), object(3, t0, md5Foo), false},
+		"SizeOnly_SizeDiffers":   {SizeOnlyComparator, object(3, t0, md5Foo), object(4, t0, md5Foo), true},
+		"Exact_Same":             {ExactTimestampsComparator, object(3, t0, md5Foo), object(3, t0, md5Foo), false},
+		"Exact_SourceOlder":      {ExactTimestampsComparator, object(3, t0, md5Foo), object(3, t1, md5Foo), true},
+		"Checksum_SameLocal":     {ChecksumComparator, local(t1), object(3, t0, md5Foo), false},
+		"Checksum_DiffersLocal":  {ChecksumComparator, local(t0), object(3, t1, md5Bar), true},
+		"Checksum_SameObject":    {ChecksumComparator, object(3, t1, md5Foo), object(3, t0, md5Foo), false},
+		"Checksum_SizeDiffers":   {ChecksumComparator, object(3, t0, md5Foo), object(4, t0, md5Foo), true},
+		"Checksum_MultipartOld":  {ChecksumComparator, local(t0), object(3, t1, `"abc-2"`), false},
+		"Checksum_MultipartNew":  {ChecksumComparator, local(t1), object(3, t0, `"abc-2"`), true},
+		"Checksum_ObjectNoLocal": {ChecksumComparator, object(3, t1, ""), object(3, t0, md5Foo), true},
+		"ETag_MultipartSame":     {ETagComparator(2), local(t1), object(3, t0, `"8afd3fb5c46b7d65bcbaa9cee0af23ad-2"`), false},
+		"ETag_MultipartDiffers":  {ETagComparator(2), local(t0), object(3, t1, `"8afd3fb5c46b7d65bcbaa9cee0af23ae-2"`), true},
+		"ETag_MixedObjects":      {ETagComparator(2), object(3, t0, md5Foo), object(3, t1, `"abc-2"`), false},
+		"Checksum_SHA256Same": {ChecksumComparator, local(t1),
+			withChecksums(object(3, t0, `"abc-2"`), map[string]string{"SHA256": sha256Foo, "CRC32": "AAAAAA=="}), false},
+		"Checksum_CRC32Differs": {ChecksumComparator, local(t0),
+			withChecksums(object(3, t1, `"abc-2"`), map[string]string{"CRC32": "AAAAAA=="}), true},
+		"Checksum_CRC32Same": {ChecksumComparator, local(t1),
+			withChecksums(object(3, t0, `"abc-2"`), map[string]string{"CRC32": crc32Foo}), false},
+		"Checksum_Composite": {ChecksumComparator, local(t1),
+			withChecksums(object(3, t0, `"abc-2"`), map[string]string{"SHA256": sha256Foo + "-2"}), true},
+		"SHA256_SameLocal": {SHA256ChecksumComparator, local(t0),
+			withChecksums(object(3, t1, md5Bar), map[string]string{"SHA256": sha256Foo}), false},
+		"SHA256_DiffersLocal": {SHA256ChecksumComparator, local(t1),
+			withChecksums(object(3, t0, md5Foo), map[string]string{"SHA256": "AAAA"}), true},
+		"SHA256_SameObject": {SHA256ChecksumComparator,
+			withChecksums(object(3, t1, md5Bar), map[string]string{"SHA256": sha256Foo}),
+			withChecksums(object(3, t0, md5Foo), map[string]string{"SHA256": sha256Foo}), false},
+		"SHA256_DiffersObject": {SHA256ChecksumComparator,
+			withChecksums(object(3, t0, md5Foo), map[string]string{"SHA256": sha256Foo}),
+			withChecksums(object(3, t1, md5Foo), map[string]string{"SHA256": "AAAA"}), true},
+		"SHA256_SizeDiffers": {SHA256ChecksumComparator, local(t0),
+			withChecksums(object(4, t1, md5Foo), map[string]string{"SHA256": sha256Foo}), true},
+		"SHA256_Unavailable": {SHA256ChecksumComparator, local(t1),
+			withChecksums(object(3, t0, md5Foo), map[string]string{"CRC32": crc32Foo}), true},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if ret := tt.cmp.ShouldSync(tt.src, tt.dst); ret != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, ret)
+			}
+		})
+	}
+}
+
+func TestFileInfo_Open(t *testing.T) {
+	if _, err := (&fileInfo{name: "foo", path: "foo", etag: `"abc"`}).export().Open(); err != errNoContent {
+		t.Fatalf("Expected %v, got %v", errNoContent, err)
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	t0 := time.Now()
+	// The local clock is an hour ahead of S3.
+	local := &fileInfo{name: "a", size: 1, lastModified: t0.Add(time.Hour), local: true}
+	remote := &fileInfo{name: "a", size: 1, lastModified: t0.Add(time.Minute)}
+
+	testCases := map[string]struct {
+		skew     time.Duration
+		src, dst *fileInfo
+		expected bool
+	}{
+		"NoSkewUpload":   {0, local, remote, true},
+		"Upload":         {time.Hour, local, remote, false},
+		"Download":       {time.Hour, remote, local, true},
+		"ModifiedUpload": {time.Hour, &fileInfo{size: 1, lastModified: t0.Add(2 * time.Hour), local: true}, remote, true},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := &Manager{comparator: DefaultComparator}
+			WithClockSkew(tc.skew)(m)
+			if got := m.fileComparator().ShouldSync(tc.src.export(), tc.dst.export()); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterFilesForSync(t *testing.T) {
+	t0 := time.Now()
+	list := func(files ...*fileInfo) chan *fileInfo {
+		c := make(chan *fileInfo, len(files))
+		for _, f := range files {
+			c <- f
+		}
+		close(c)
+		return c
+	}
+	source := list(
+		&fileInfo{name: "same", size: 1, lastModified: t0},
+		&fileInfo{name: "newer", size: 1, lastModified: t0.Add(time.Second)},
+		&fileInfo{name: "new", size: 1, lastModified: t0},
+	)
+	dest := list(
+		&fileInfo{name: "same", size: 1, lastModified: t0},
+		&fileInfo{name: "newer", size: 1, lastModified: t0},
+		&fileInfo{name: "deleted", size: 1, lastModified: t0},
+	)
+
+	ops := make(map[string]operation)
+	for op := range filterFilesForSync(source, dest, true, SizeOnlyComparator, nil) {
+		ops[op.name] = op.op
+	}
+	if len(ops) != 2 || ops["new"] != opUpdate || ops["deleted"] != opDelete {
+		t.Fatalf("Unexpected operations %v", ops)
+	}
+}
+
+func TestFileInfo_calcETag(t *testing.T) {
+	fi := &FileInfo{
+		open: func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader("foo")), nil
+		},
+	}
+	testCases := map[int64]string{
+		0: "acbd18db4cc2f85cedef654fccc4a4d8",
+		// md5(md5("fo") + md5("o"))
+		2: "8afd3fb5c46b7d65bcbaa9cee0af23ad-2",
+		// md5(md5("foo"))
+		3: "47847ae721df523d6388aebc9c94d656-1",
+	}
+	for partSize, expected := range testCases {
+		etag, err := fi.calcETag(partSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if etag != expected {
+			t.Errorf("ETag with part size %d is expected to be %s, got %s", partSize, expected, etag)
+		}
+	}
+}
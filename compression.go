@@ -0,0 +1,127 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Compression is the content coding of the objects compressed on upload.
+// Other codings like zstd can be used by implementing it with the third party packages.
+type Compression interface {
+	// Encoding returns the Content-Encoding of the compressed objects, e.g. "gzip".
+	Encoding() string
+	// NewWriter returns the writer compressing the contents to w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns the reader decompressing the contents from r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// Gzip compresses the objects by gzip.
+var Gzip Compression = gzipCompression{}
+
+type gzipCompression struct{}
+
+func (gzipCompression) Encoding() string {
+	return "gzip"
+}
+
+func (gzipCompression) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCompression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// compressionRule is the rule of the files compressed on upload.
+type compressionRule struct {
+	codec   Compression
+	minSize int64
+	exts    map[string]bool
+}
+
+// matches returns true if the extension of the name is included by the rule.
+func (c *compressionRule) matches(name string) bool {
+	return len(c.exts) == 0 || c.exts[normalizeExt(filepath.Ext(name))]
+}
+
+// compressible returns true if the file is compressed on upload.
+func (m *Manager) compressible(name string, size int64) bool {
+	return m.compression != nil && size >= m.compression.minSize && m.compression.matches(name)
+}
+
+// compressReader returns the reader of the contents of r compressed by the codec.
+// The returned reader must be closed to stop compressing.
+func compressReader(codec Compression, r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		cw, err := codec.NewWriter(pw)
+		if err == nil {
+			_, err = io.Copy(cw, r)
+			if cerr := cw.Close(); err == nil {
+				err = cerr
+			}
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// uncompressedSizes returns the pair of the files with the size of the compressed
+// object replaced by the size of the local file, since the sizes are not comparable.
+func (m *Manager) uncompressedSizes(src, dst *FileInfo) (*FileInfo, *FileInfo) {
+	switch {
+	case m.compression == nil || src.remote == dst.remote:
+	case src.remote && m.compressible(dst.Name, dst.Size):
+		replaced := *src
+		replaced.Size = dst.Size
+		return &replaced, dst
+	case dst.remote && m.compressible(src.Name, src.Size):
+		replaced := *dst
+		replaced.Size = src.Size
+		return src, &replaced
+	}
+	return src, dst
+}
+
+// getDecodedObject downloads the object by a single request and decompresses
+// the contents if the object is compressed by the codec of WithCompression.
+// The Accept-Encoding header prevents the http client from decompressing
+// the gzip contents by itself.
+func (m *Manager) getDecodedObject(ctx context.Context, w io.WriterAt, in *s3.GetObjectInput) (int64, error) {
+	out, err := m.regionalClient(ctx, m.sourceClient(), aws.StringValue(in.Bucket)).GetObjectWithContext(ctx, in,
+		request.WithSetRequestHeaders(map[string]string{"Accept-Encoding": "identity"}))
+	if err != nil {
+		return 0, err
+	}
+	defer out.Body.Close()
+	var body io.Reader = out.Body
+	if aws.StringValue(out.ContentEncoding) == m.compression.codec.Encoding() {
+		r, err := m.compression.codec.NewReader(out.Body)
+		if err != nil {
+			return 0, err
+		}
+		defer r.Close()
+		body = r
+	}
+	return io.Copy(&offsetWriter{w: w}, body)
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const compressedContents = "compressed contents"
+
+type dummyCompressionS3 struct {
+	dummyKeyRangeS3
+	mu         sync.Mutex
+	downloaded []string
+	identity   []string
+}
+
+func (s *dummyCompressionS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	r := &request.Request{HTTPRequest: &http.Request{Header: http.Header{}}}
+	r.ApplyOptions(opts...)
+	s.mu.Lock()
+	s.downloaded = append(s.downloaded, *in.Key)
+	if r.HTTPRequest.Header.Get("Accept-Encoding") == "identity" {
+		s.identity = append(s.identity, *in.Key)
+	}
+	s.mu.Unlock()
+
+	if !strings.HasSuffix(*in.Key, ".log") {
+		return &s3.GetObjectOutput{
+			Body:          ioutil.NopCloser(strings.NewReader("b")),
+			ContentLength: aws.Int64(1),
+		}, nil
+	}
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.Write([]byte(compressedContents))
+	w.Close()
+	return &s3.GetObjectOutput{
+		Body:            ioutil.NopCloser(buf),
+		ContentEncoding: aws.String("gzip"),
+	}, nil
+}
+
+func TestCompression(t *testing.T) {
+	t.Run("Upload", func(t *testing.T) {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+		files := map[string]string{"a.log": compressedContents, "b.txt": "plain", "c.log": "s"}
+		for name, contents := range files {
+			if err := ioutil.WriteFile(filepath.Join(temp, name), []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var mu sync.Mutex
+		puts := make(map[string]*s3.PutObjectInput)
+		bodies := make(map[string][]byte)
+		c := s3.New(session.New(), aws.NewConfig().
+			WithRegion("us-east-1").
+			WithCredentials(credentials.AnonymousCredentials))
+		c.Handlers.Send.Clear()
+		c.Handlers.Send.PushBack(func(r *request.Request) {
+			in := r.Params.(*s3.PutObjectInput)
+			b, err := ioutil.ReadAll(in.Body)
+			if err != nil {
+				r.Error = err
+				return
+			}
+			mu.Lock()
+			puts[*in.Key] = in
+			bodies[*in.Key] = b
+			mu.Unlock()
+			r.HTTPResponse = &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}
+		})
+		m := New(session.New(), WithCompression(Gzip, 2, "LOG"))
+		m.s3 = c
+
+		for name, contents := range files {
+			file := &fileInfo{name: name, path: filepath.Join(temp, name), size: int64(len(contents)), local: true}
+			if err := m.upload(context.Background(), file, temp, &s3Path{bucket: "bucket", bucketPrefix: "prefix"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for name, contents := range files {
+			in := puts["prefix/"+name]
+			if in == nil {
+				t.Fatalf("%s is not uploaded", name)
+			}
+			var r io.Reader = bytes.NewReader(bodies["prefix/"+name])
+			if name == "a.log" {
+				if enc := aws.StringValue(in.ContentEncoding); enc != "gzip" {
+					t.Errorf("%s: expected gzip Content-Encoding, got %q", name, enc)
+				}
+				if r, err = gzip.NewReader(r); err != nil {
+					t.Fatal(err)
+				}
+			} else if in.ContentEncoding != nil {
+				t.Errorf("%s: expected no Content-Encoding, got %q", name, *in.ContentEncoding)
+			}
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != contents {
+				t.Errorf("%s: expected %q, got %q", name, contents, b)
+			}
+		}
+	})
+	t.Run("Download", func(t *testing.T) {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+
+		s := &dummyCompressionS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a.log", "prefix/b.txt"}}}
+		m := New(session.New(), WithCompression(Gzip, 0, ".log"))
+		m.s3 = s
+		for i := 0; i < 2; i++ {
+			// The sizes of the compressed objects are not compared on the second sync.
+			if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sort.Strings(s.downloaded)
+		if expected := []string{"prefix/a.log", "prefix/b.txt"}; !reflect.DeepEqual(expected, s.downloaded) {
+			t.Errorf("Expected downloads %v, got %v", expected, s.downloaded)
+		}
+		if expected := []string{"prefix/a.log"}; !reflect.DeepEqual(expected, s.identity) {
+			t.Errorf("Expected the identity encoding requests of %v, got %v", expected, s.identity)
+		}
+		b, err := ioutil.ReadFile(filepath.Join(temp, "a.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != compressedContents {
+			t.Errorf("Expected decompressed contents, got %q", b)
+		}
+	})
+}
+
+func TestCompressionComparator(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := &FileInfo{Name: "a.log", Size: 100, LastModified: t0}
+	remote := &FileInfo{Name: "a.log", Size: 30, LastModified: t0, remote: true}
+	testCases := map[string]struct {
+		options []Option
+		src     *FileInfo
+		dst     *FileInfo
+		sync    bool
+	}{
+		"Upload":        {[]Option{WithCompression(Gzip, 0)}, local, remote, false},
+		"Download":      {[]Option{WithCompression(Gzip, 0)}, remote, local, false},
+		"Disabled":      {nil, local, remote, true},
+		"Small":         {[]Option{WithCompression(Gzip, 101)}, local, remote, true},
+		"OtherExt":      {[]Option{WithCompression(Gzip, 0, ".txt")}, local, remote, true},
+		"BothRemote":    {[]Option{WithCompression(Gzip, 0)}, remote, &FileInfo{Name: "a.log", Size: 100, remote: true}, true},
+		"WithClockSkew": {[]Option{WithCompression(Gzip, 0), WithClockSkew(time.Hour)}, local, remote, false},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), tc.options...)
+			if sync := m.fileComparator().ShouldSync(tc.src, tc.dst); sync != tc.sync {
+				t.Errorf("Expected %v, got %v", tc.sync, sync)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// mappedContentType returns the MIME type mapped to the extension of the name
+// by WithContentTypeMap, or nil if not mapped.
+func (m *Manager) mappedContentType(name string) *string {
+	if len(m.contentTypeMap) == 0 {
+		return nil
+	}
+	if mime, ok := m.contentTypeMap[strings.ToLower(filepath.Ext(name))]; ok {
+		return aws.String(mime)
+	}
+	return nil
+}
+
+// sniffContentType returns true if the MIME type of the file is detected from
+// the contents on upload.
+func (m *Manager) sniffContentType(file *fileInfo) bool {
+	if !m.guessMime || m.contentType != nil || m.mappedContentType(file.name) != nil {
+		return false
+	}
+	if m.sniffMaxSize > 0 && (file.size == 0 || file.size > m.sniffMaxSize) {
+		return false
+	}
+	return true
+}
+
+// normalizeExt returns the lower-cased extension with the leading dot.
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
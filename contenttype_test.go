@@ -0,0 +1,55 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestContentTypeMap(t *testing.T) {
+	types := map[string]string{".css": "text/css", "JS": "text/javascript"}
+	testCases := map[string]struct {
+		options []Option
+		name    string
+		size    int64
+		mapped  string
+		sniff   bool
+	}{
+		"Mapped":           {[]Option{WithContentTypeMap(types)}, "a/style.css", 10, "text/css", false},
+		"CaseInsensitive":  {[]Option{WithContentTypeMap(types)}, "app.min.Js", 10, "text/javascript", false},
+		"NotMapped":        {[]Option{WithContentTypeMap(types)}, "image.svg", 10, "", true},
+		"NoExtension":      {[]Option{WithContentTypeMap(types)}, "css", 10, "", true},
+		"ContentType":      {[]Option{WithContentType("text/plain")}, "image.svg", 10, "", false},
+		"WithoutGuessMime": {[]Option{WithoutGuessMimeType()}, "image.svg", 10, "", false},
+		"Empty":            {[]Option{WithMimeSniffMaxSize(100)}, "image.svg", 0, "", false},
+		"Huge":             {[]Option{WithMimeSniffMaxSize(100)}, "image.svg", 101, "", false},
+		"BelowLimit":       {[]Option{WithMimeSniffMaxSize(100)}, "image.svg", 100, "", true},
+		"EmptyNoLimit":     {nil, "image.svg", 0, "", true},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), tc.options...)
+			if mapped := aws.StringValue(m.mappedContentType(tc.name)); mapped != tc.mapped {
+				t.Errorf("Expected the mapped type %q, got %q", tc.mapped, mapped)
+			}
+			if sniff := m.sniffContentType(&fileInfo{name: tc.name, size: tc.size}); sniff != tc.sniff {
+				t.Errorf("Expected sniffing to be %v, got %v", tc.sniff, sniff)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	// maxCopyObjectSize is the maximum size of the object copied by a single CopyObject.
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	// DefaultCopyPartSize is the default part size of the multipart copy.
+	DefaultCopyPartSize = 256 * 1024 * 1024
+	// DefaultCopyConcurrency is the default number of the parts of each object copied in parallel.
+	DefaultCopyConcurrency = 4
+)
+
+// needsMultipartCopy returns whether the object of the given size can't be copied by CopyObject.
+func needsMultipartCopy(size int64) bool {
+	return size > maxCopyObjectSize
+}
+
+// partSizeForCopy returns the part size to copy the object of the given size.
+// The configured part size is enlarged if the number of the parts exceeds the limit.
+func (m *Manager) partSizeForCopy(size int64) int64 {
+	partSize := m.copyPartSize
+	if partSize <= 0 {
+		partSize = DefaultCopyPartSize
+	}
+	if min := (size + s3manager.MaxUploadParts - 1) / s3manager.MaxUploadParts; partSize < min {
+		partSize = min
+	}
+	return partSize
+}
+
+// multipartCopy copies the object of the given size by UploadPartCopy
+// with the same parameters as the CopyObject input.
+// The metadata must be set to the input with REPLACE directive since
+// the multipart upload doesn't copy the metadata of the source.
+func (m *Manager) multipartCopy(ctx context.Context, in *s3.CopyObjectInput, size int64) (err error) {
+	putRate := m.putRateOption(aws.StringValue(in.Bucket), aws.StringValue(in.Key))
+	client := m.destClient(ctx, aws.StringValue(in.Bucket))
+	upload, err := client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               in.Bucket,
+		Key:                  in.Key,
+		ACL:                  in.ACL,
+		CacheControl:         in.CacheControl,
+		ContentDisposition:   in.ContentDisposition,
+		ContentEncoding:      in.ContentEncoding,
+		ContentLanguage:      in.ContentLanguage,
+		ContentType:          in.ContentType,
+		Expires:              in.Expires,
+		Metadata:             in.Metadata,
+		ServerSideEncryption: in.ServerSideEncryption,
+		SSEKMSKeyId:          in.SSEKMSKeyId,
+		SSECustomerAlgorithm: in.SSECustomerAlgorithm,
+		SSECustomerKey:       in.SSECustomerKey,
+		StorageClass:         in.StorageClass,
+		Tagging:              in.Tagging,
+		ChecksumAlgorithm:    in.ChecksumAlgorithm,
+	}, putRate)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			// Context may be already canceled, but the parts must be cleaned up.
+			_, _ = client.AbortMultipartUploadWithContext(context.Background(), &s3.AbortMultipartUploadInput{
+				Bucket:   in.Bucket,
+				Key:      in.Key,
+				UploadId: upload.UploadId,
+			})
+		}
+	}()
+
+	partSize := m.partSizeForCopy(size)
+	parts := make([]*s3.CompletedPart, (size+partSize-1)/partSize)
+
+	concurrency := m.copyConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCopyConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := &multiErr{}
+	chPart := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range chPart {
+				first := int64(i) * partSize
+				last := first + partSize - 1
+				if last >= size {
+					last = size - 1
+				}
+				out, err := client.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+					Bucket:                         in.Bucket,
+					Key:                            in.Key,
+					UploadId:                       upload.UploadId,
+					PartNumber:                     aws.Int64(int64(i + 1)),
+					CopySource:                     in.CopySource,
+					CopySourceRange:                aws.String(fmt.Sprintf("bytes=%d-%d", first, last)),
+					SSECustomerAlgorithm:           in.SSECustomerAlgorithm,
+					SSECustomerKey:                 in.SSECustomerKey,
+					CopySourceSSECustomerAlgorithm: in.CopySourceSSECustomerAlgorithm,
+					CopySourceSSECustomerKey:       in.CopySourceSSECustomerKey,
+				}, putRate)
+				if err != nil {
+					errs.Append(err)
+					cancel()
+					continue
+				}
+				parts[i] = &s3.CompletedPart{
+					ETag:           out.CopyPartResult.ETag,
+					ChecksumSHA256: out.CopyPartResult.ChecksumSHA256,
+					PartNumber:     aws.Int64(int64(i + 1)),
+				}
+			}
+		}()
+	}
+	for i := range parts {
+		select {
+		case chPart <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(chPart)
+	wg.Wait()
+	if err := errs.ErrOrNil(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:               in.Bucket,
+		Key:                  in.Key,
+		UploadId:             upload.UploadId,
+		MultipartUpload:      &s3.CompletedMultipartUpload{Parts: parts},
+		SSECustomerAlgorithm: in.SSECustomerAlgorithm,
+		SSECustomerKey:       in.SSECustomerKey,
+	}, putRate)
+	return err
+}
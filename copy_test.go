@@ -0,0 +1,160 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyCopyS3 struct {
+	s3iface.S3API
+	mu          sync.Mutex
+	ranges      map[int64]string
+	failPart    int64
+	contentType *string
+	tagging     *string
+	completed   []*s3.CompletedPart
+	aborted     bool
+	copied      bool
+	copySource  string
+}
+
+func (s *dummyCopyS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	return &s3.HeadObjectOutput{ContentType: aws.String("text/plain")}, nil
+}
+
+func (s *dummyCopyS3) GetObjectTaggingWithContext(ctx aws.Context, in *s3.GetObjectTaggingInput, opts ...request.Option) (*s3.GetObjectTaggingOutput, error) {
+	return &s3.GetObjectTaggingOutput{TagSet: []*s3.Tag{
+		{Key: aws.String("env"), Value: aws.String("prod")},
+		{Key: aws.String("team"), Value: aws.String("a&b")},
+	}}, nil
+}
+
+func (s *dummyCopyS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	s.copied = true
+	s.copySource = *in.CopySource
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (s *dummyCopyS3) CreateMultipartUploadWithContext(ctx aws.Context, in *s3.CreateMultipartUploadInput, opts ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	s.contentType = in.ContentType
+	s.tagging = in.Tagging
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (s *dummyCopyS3) UploadPartCopyWithContext(ctx aws.Context, in *s3.UploadPartCopyInput, opts ...request.Option) (*s3.UploadPartCopyOutput, error) {
+	if *in.PartNumber == s.failPart {
+		return nil, errors.New("part failed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ranges[*in.PartNumber] = *in.CopySourceRange
+	return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String(`"etag"`)}}, nil
+}
+
+func (s *dummyCopyS3) CompleteMultipartUploadWithContext(ctx aws.Context, in *s3.CompleteMultipartUploadInput, opts ...request.Option) (*s3.CompleteMultipartUploadOutput, error) {
+	s.completed = in.MultipartUpload.Parts
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (s *dummyCopyS3) AbortMultipartUploadWithContext(ctx aws.Context, in *s3.AbortMultipartUploadInput, opts ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	s.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestMultipartCopy(t *testing.T) {
+	const size = 6 * 1024 * 1024 * 1024
+	source := &s3Path{bucket: "source", bucketPrefix: "prefix"}
+	dest := &s3Path{bucket: "dest", bucketPrefix: "prefix"}
+
+	t.Run("SmallObject", func(t *testing.T) {
+		s := &dummyCopyS3{ranges: make(map[int64]string)}
+		m := New(session.New())
+		m.s3 = s
+		if err := m.copyS3ToS3(context.Background(), &fileInfo{name: "file", size: 1024}, source, dest); err != nil {
+			t.Fatal(err)
+		}
+		if !s.copied || len(s.ranges) != 0 {
+			t.Error("Small object must be copied by CopyObject")
+		}
+	})
+	t.Run("SpecialCharacters", func(t *testing.T) {
+		s := &dummyCopyS3{ranges: make(map[int64]string)}
+		m := New(session.New())
+		m.s3 = s
+		if err := m.copyS3ToS3(context.Background(), &fileInfo{name: "a+b c/#日本.txt", size: 1024}, source, dest); err != nil {
+			t.Fatal(err)
+		}
+		if expected := "source/prefix/a%2Bb%20c/%23%E6%97%A5%E6%9C%AC.txt"; s.copySource != expected {
+			t.Errorf("Expected CopySource %s, got %s", expected, s.copySource)
+		}
+	})
+	t.Run("LargeObject", func(t *testing.T) {
+		s := &dummyCopyS3{ranges: make(map[int64]string)}
+		m := New(session.New(), WithCopyPartSize(1024*1024*1024), WithCopyConcurrency(2))
+		m.s3 = s
+		if err := m.copyS3ToS3(context.Background(), &fileInfo{name: "file", size: size + 1}, source, dest); err != nil {
+			t.Fatal(err)
+		}
+		if s.copied {
+			t.Error("Large object must not be copied by CopyObject")
+		}
+		if len(s.completed) != 7 {
+			t.Fatalf("Expected 7 parts, got %d", len(s.completed))
+		}
+		if r := s.ranges[1]; r != "bytes=0-1073741823" {
+			t.Errorf("Unexpected range of the first part %s", r)
+		}
+		if r := s.ranges[7]; r != "bytes=6442450944-6442450944" {
+			t.Errorf("Unexpected range of the last part %s", r)
+		}
+		if ct := aws.StringValue(s.contentType); ct != "text/plain" {
+			t.Errorf("Metadata of the source must be copied, got content type %s", ct)
+		}
+		if tags := aws.StringValue(s.tagging); tags != "env=prod&team=a%26b" {
+			t.Errorf("Tags of the source must be copied, got %s", tags)
+		}
+	})
+	t.Run("PartFailed", func(t *testing.T) {
+		s := &dummyCopyS3{ranges: make(map[int64]string), failPart: 3}
+		m := New(session.New())
+		m.s3 = s
+		if err := m.copyS3ToS3(context.Background(), &fileInfo{name: "file", size: size}, source, dest); err == nil {
+			t.Fatal("Expected error")
+		}
+		if !s.aborted || s.completed != nil {
+			t.Error("Failed multipart copy must be aborted")
+		}
+	})
+}
+
+func TestPartSizeForCopy(t *testing.T) {
+	m := &Manager{}
+	if ps := m.partSizeForCopy(1024); ps != DefaultCopyPartSize {
+		t.Errorf("Expected default part size, got %d", ps)
+	}
+	const size = 5 * 1024 * 1024 * 1024 * 1024
+	if ps := m.partSizeForCopy(size); (size+ps-1)/ps > 10000 {
+		t.Errorf("Part size %d exceeds the maximum number of parts", ps)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// maxDeleteObjects is the maximum number of the keys deleted by a DeleteObjects request.
+const maxDeleteObjects = 1000
+
+// DeleteOrdering is the order of the deletions of WithDelete relative to the updates.
+type DeleteOrdering int
+
+const (
+	// DeleteDuring deletes the files interleaved with the updates as they are found.
+	DeleteDuring DeleteOrdering = iota
+	// DeleteBefore deletes the files before any update.
+	// The updates are held in memory until the listings are finished and all of
+	// the deletions complete.
+	DeleteBefore
+	// DeleteAfter deletes the files after all of the updates complete,
+	// e.g. to deploy a website without removing the old content still referenced.
+	// The deletions are skipped if any update fails.
+	// The deletions are held in memory until the updates complete.
+	DeleteAfter
+)
+
+// deferOp returns whether the file operation is deferred until
+// the other operations complete according to the delete ordering.
+// The deletions are always deferred if WithDeleteList is given.
+func (m *Manager) deferOp(file *fileOp) bool {
+	if file.err != nil {
+		return false
+	}
+	if m.deleteList != nil && file.op == opDelete {
+		return true
+	}
+	switch m.deleteOrdering {
+	case DeleteBefore:
+		return file.op == opUpdate
+	case DeleteAfter:
+		return file.op == opDelete
+	}
+	return false
+}
+
+// deferredOps returns the phases of the deferred file operations to run in order
+// after the others. Each phase starts after the previous one completes.
+// The deferred deletions are dropped if any of the updates failed with DeleteAfter,
+// or rejected by the function of WithDeleteList.
+func (m *Manager) deferredOps(files []*fileOp, failed bool, dest string) ([][]*fileOp, error) {
+	var updates, deletes []*fileOp
+	for _, file := range files {
+		if file.op == opDelete {
+			deletes = append(deletes, file)
+		} else {
+			updates = append(updates, file)
+		}
+	}
+	if m.deleteOrdering == DeleteAfter && failed && len(deletes) > 0 {
+		println("Skipping", len(deletes), "deletions since the sync failed")
+		deletes = nil
+	}
+	var err error
+	if m.deleteList != nil && len(deletes) > 0 {
+		names := make([]string, len(deletes))
+		for i, file := range deletes {
+			names[i] = filepath.ToSlash(file.name)
+		}
+		if err = m.deleteList(dest, names); err != nil {
+			println("Skipping", len(deletes), "deletions:", err.Error())
+			deletes = nil
+		}
+	}
+	if m.deleteOrdering == DeleteBefore {
+		return [][]*fileOp{deletes, updates}, err
+	}
+	return [][]*fileOp{updates, deletes}, err
+}
+
+// WriteDeleteList returns the function of WithDeleteList writing the paths
+// of the files to be deleted to w, one per line.
+func WriteDeleteList(w io.Writer) func(dest string, names []string) error {
+	return func(dest string, names []string) error {
+		for _, name := range names {
+			if _, err := fmt.Fprintln(w, strings.TrimSuffix(dest, "/")+"/"+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// remoteFilePath returns the destination path of the file.
+func remoteFilePath(file *fileInfo, destPath *s3Path) *s3Path {
+	destFile := *destPath
+	if strings.HasSuffix(destPath.bucketPrefix, "/") || destPath.bucketPrefix == "" || !file.singleFile {
+		// If source is a single file and destination is not a directory, use destination URL as is.
+		// Using filepath.ToSlash for change backslash to slash on Windows
+		destFile.bucketPrefix = objectKey(destPath.bucketPrefix, file.name)
+	}
+	return &destFile
+}
+
+// deleteRemoteBatch deletes the destination objects by a DeleteObjects request.
+// The number of the files must not exceed maxDeleteObjects.
+// It returns the errors of the failed objects.
+func (m *Manager) deleteRemoteBatch(ctx context.Context, files []*fileInfo, destPath *s3Path) []error {
+	if len(files) == 1 || m.backup != "" {
+		// Each object is backed up by a request before its deletion anyway.
+		var errs []error
+		for _, file := range files {
+			file := file
+			if err := m.retry(ctx, func(ctx context.Context) error {
+				return m.deleteRemote(ctx, file, destPath)
+			}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	}
+	if err := m.refuseIfReadOnly("deleting", destPath.String()); err != nil {
+		return []error{err}
+	}
+
+	keys := make([]string, len(files))
+	attrs := make([][]interface{}, len(files))
+	objects := make([]*s3.ObjectIdentifier, len(files))
+	for i, file := range files {
+		destFile := remoteFilePath(file, destPath)
+		keys[i] = destFile.bucketPrefix
+		attrs[i] = opAttrs(ctx, "delete", destFile.bucket, destFile.bucketPrefix, file.size)
+		logOp(ctx, attrs[i], "Deleting", destFile.String())
+		objects[i] = &s3.ObjectIdentifier{Key: aws.String(destFile.bucketPrefix)}
+	}
+	if m.isDryRun(ctx) {
+		for _, file := range files {
+			m.planned(ctx, FileDeleted, file)
+		}
+		return nil
+	}
+
+	start := time.Now()
+	var out *s3.DeleteObjectsOutput
+	err := m.retry(ctx, func(ctx context.Context) (err error) {
+		ctx, cancel := withTimeout(ctx, m.opTimeout)
+		defer cancel()
+		out, err = m.destClient(ctx, destPath.bucket).DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(destPath.bucket),
+			Delete: &s3.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+			BypassGovernanceRetention: m.bypassGovernance,
+		}, m.putRateOption(destPath.bucket, keys[0]))
+		return err
+	})
+	failed := make(map[string]error)
+	if err == nil {
+		for _, e := range out.Errors {
+			key := aws.StringValue(e.Key)
+			failed[key] = &FileError{Op: "delete", Key: key, Err: awserr.New(aws.StringValue(e.Code), aws.StringValue(e.Message), nil)}
+		}
+	}
+
+	var errs []error
+	for i, file := range files {
+		ferr, ok := failed[keys[i]]
+		if err != nil {
+			ferr = err
+		}
+		logOpDone(ctx, attrs[i], start, &ferr)
+		m.emitDone(ctx, FileDeleted, file, start, &ferr)
+		if ok {
+			errs = append(errs, ferr)
+		} else if err == nil {
+			m.incrementDeletedFiles(ctx)
+		}
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return errs
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyDeleteS3 struct {
+	s3iface.S3API
+	requests int
+	deleted  []string
+	denied   map[string]bool
+	bypass   []bool
+}
+
+func (s *dummyDeleteS3) DeleteObjectWithContext(ctx aws.Context, in *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	s.requests++
+	s.deleted = append(s.deleted, *in.Key)
+	s.bypass = append(s.bypass, aws.BoolValue(in.BypassGovernanceRetention))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (s *dummyDeleteS3) DeleteObjectsWithContext(ctx aws.Context, in *s3.DeleteObjectsInput, opts ...request.Option) (*s3.DeleteObjectsOutput, error) {
+	s.requests++
+	s.bypass = append(s.bypass, aws.BoolValue(in.BypassGovernanceRetention))
+	out := &s3.DeleteObjectsOutput{}
+	for _, o := range in.Delete.Objects {
+		if s.denied[*o.Key] {
+			out.Errors = append(out.Errors, &s3.Error{Key: o.Key, Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")})
+			continue
+		}
+		s.deleted = append(s.deleted, *o.Key)
+	}
+	return out, nil
+}
+
+func TestDeleteRemoteBatch(t *testing.T) {
+	s := &dummyDeleteS3{denied: map[string]bool{"prefix/file1": true}}
+	m := New(session.New())
+	m.s3 = s
+
+	var files []*fileInfo
+	for i := 0; i < 5; i++ {
+		files = append(files, &fileInfo{name: fmt.Sprintf("file%d", i), size: 1})
+	}
+	errs := m.deleteRemoteBatch(context.Background(), files, &s3Path{bucket: "bucket", bucketPrefix: "prefix"})
+	if s.requests != 1 {
+		t.Errorf("Expected 1 request, got %d", s.requests)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var fe *FileError
+	if !errors.As(errs[0], &fe) || fe.Op != "delete" || fe.Key != "prefix/file1" {
+		t.Errorf("Expected FileError of prefix/file1, got %v", errs[0])
+	}
+	var aerr awserr.Error
+	if !errors.As(errs[0], &aerr) || aerr.Code() != "AccessDenied" {
+		t.Errorf("Expected AccessDenied, got %v", errs[0])
+	}
+	if len(s.deleted) != 4 {
+		t.Errorf("Expected 4 deleted objects, got %v", s.deleted)
+	}
+	if n := m.GetStatistics().DeletedFiles; n != 4 {
+		t.Errorf("Expected 4 deleted files in statistics, got %d", n)
+	}
+}
+
+func TestDeleteRemoteBatch_BypassGovernanceRetention(t *testing.T) {
+	s := &dummyDeleteS3{}
+	m := New(session.New(), WithBypassGovernanceRetention())
+	m.s3 = s
+
+	path := &s3Path{bucket: "bucket", bucketPrefix: "prefix"}
+	// A file is deleted by DeleteObject, and the others by DeleteObjects.
+	for _, files := range [][]*fileInfo{{{name: "a"}}, {{name: "b"}, {name: "c"}}} {
+		if errs := m.deleteRemoteBatch(context.Background(), files, path); len(errs) != 0 {
+			t.Fatal(errs)
+		}
+	}
+	if expected := []bool{true, true}; !reflect.DeepEqual(expected, s.bypass) {
+		t.Errorf("Expected bypass %v, got %v", expected, s.bypass)
+	}
+}
+
+func TestDeleteRemoteBatch_SpecialCharacters(t *testing.T) {
+	s := &dummyDeleteS3{}
+	m := New(session.New())
+	m.s3 = s
+
+	files := []*fileInfo{{name: "a+b c"}, {name: "#日本/100%"}}
+	if errs := m.deleteRemoteBatch(context.Background(), files, &s3Path{bucket: "bucket", bucketPrefix: "prefix"}); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if expected := []string{"prefix/a+b c", "prefix/#日本/100%"}; !reflect.DeepEqual(expected, s.deleted) {
+		t.Errorf("Expected %v, got %v", expected, s.deleted)
+	}
+}
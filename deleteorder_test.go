@@ -0,0 +1,169 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// dummyOrderS3 calls check on each download.
+type dummyOrderS3 struct {
+	dummyBudgetS3
+	check func(key string) error
+}
+
+func (s *dummyOrderS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	if err := s.check(*in.Key); err != nil {
+		return nil, err
+	}
+	return s.dummyBudgetS3.GetObjectWithContext(ctx, in, opts...)
+}
+
+func TestDeleteOrdering(t *testing.T) {
+	errDownload := errors.New("download failed")
+	testCases := map[string]struct {
+		ordering    DeleteOrdering
+		fail        bool
+		existing    bool
+		oldExpected bool
+	}{
+		"Before":      {ordering: DeleteBefore, existing: false, oldExpected: false},
+		"After":       {ordering: DeleteAfter, existing: true, oldExpected: false},
+		"AfterFailed": {ordering: DeleteAfter, fail: true, existing: true, oldExpected: true},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			old := filepath.Join(temp, "old")
+			if err := ioutil.WriteFile(old, []byte("old"), 0644); err != nil {
+				t.Fatal("Failed to write", err)
+			}
+
+			s := &dummyOrderS3{
+				dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}},
+				check: func(key string) error {
+					if _, err := os.Stat(old); (err == nil) != tc.existing {
+						t.Errorf("Expected the old file existing: %v on downloading %s", tc.existing, key)
+					}
+					if tc.fail && key == "prefix/b" {
+						return errDownload
+					}
+					return nil
+				},
+			}
+			m := New(session.New(), WithParallel(1), WithDelete(), WithDeleteOrdering(tc.ordering))
+			m.s3 = s
+
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			if tc.fail != errors.Is(err, errDownload) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := os.Stat(old); (err == nil) != tc.oldExpected {
+				t.Errorf("Expected the old file existing: %v after the sync", tc.oldExpected)
+			}
+		})
+	}
+}
+
+func TestDeleteList(t *testing.T) {
+	errRejected := errors.New("rejected")
+	testCases := map[string]struct {
+		ordering    DeleteOrdering
+		err         error
+		oldExpected bool
+	}{
+		"During":   {ordering: DeleteDuring},
+		"Before":   {ordering: DeleteBefore},
+		"Rejected": {ordering: DeleteDuring, err: errRejected, oldExpected: true},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			for _, name := range []string{"old", "dir/old"} {
+				if err := os.MkdirAll(filepath.Join(temp, "dir"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(filepath.Join(temp, name), []byte("old"), 0644); err != nil {
+					t.Fatal("Failed to write", err)
+				}
+			}
+
+			s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}}
+			var listed []string
+			m := New(session.New(), WithDelete(), WithDeleteOrdering(tc.ordering),
+				WithDeleteList(func(dest string, names []string) error {
+					if dest != temp {
+						t.Errorf("Expected dest %s, got %s", temp, dest)
+					}
+					for _, name := range names {
+						if _, err := os.Stat(filepath.Join(temp, name)); err != nil {
+							t.Errorf("Expected %s not deleted before the list, got %v", name, err)
+						}
+					}
+					listed = append(listed, names...)
+					return tc.err
+				}))
+			m.s3 = s
+
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			if !errors.Is(err, tc.err) || (err != nil) != (tc.err != nil) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			sort.Strings(listed)
+			if expected := []string{"dir/old", "old"}; !reflect.DeepEqual(expected, listed) {
+				t.Errorf("Expected %v, got %v", expected, listed)
+			}
+			for _, name := range []string{"old", "dir/old"} {
+				if _, err := os.Stat(filepath.Join(temp, name)); (err == nil) != tc.oldExpected {
+					t.Errorf("Expected %s existing: %v after the sync", name, tc.oldExpected)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteDeleteList(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := WriteDeleteList(buf)("s3://bucket/prefix/", []string{"a", "b/c"}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "s3://bucket/prefix/a\ns3://bucket/prefix/b/c\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// depthWalk returns the walk function which skips walking into the directories
+// deeper than WithMaxDepth.
+func (m *Manager) depthWalk(walk func(string, filepath.WalkFunc) error) func(string, filepath.WalkFunc) error {
+	if m.maxDepth <= 0 {
+		return walk
+	}
+	return func(root string, fn filepath.WalkFunc) error {
+		return walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				// The files in the directory of depth n are of depth n+1.
+				if rel, err := filepath.Rel(root, path); err == nil && rel != "." && nameDepth(rel) >= m.maxDepth {
+					return filepath.SkipDir
+				}
+			}
+			return fn(path, info, err)
+		})
+	}
+}
+
+// nameDepth returns the number of the segments of the relative name.
+func nameDepth(name string) int {
+	return strings.Count(filepath.ToSlash(name), "/") + 1
+}
+
+// listS3FilesByDepth lists the s3 files up to WithMaxDepth by the listings delimited by "/",
+// which don't list the objects under the deeper prefixes.
+// The files are sent in the order of the keys like the recursive listing.
+func (m *Manager) listS3FilesByDepth(ctx context.Context, c chan *fileInfo, path *s3Path, patterns []*regexp.Regexp) {
+	first := true
+	var list func(prefix string, depth int) bool
+	list = func(prefix string, depth int) bool {
+		objects, prefixes, err := m.listDelimited(ctx, path, prefix)
+		if err != nil {
+			sendErrorInfoToChannel(ctx, c, err)
+			return false
+		}
+		m.recordListing(ctx, path, patterns, first, objects)
+		first = false
+
+		// Objects and prefixes are merged in the order of the keys.
+		for len(objects) > 0 || len(prefixes) > 0 {
+			if len(prefixes) == 0 || len(objects) > 0 && *objects[0].Key < prefixes[0] {
+				if !m.sendS3Objects(ctx, c, path, objects[:1], patterns) {
+					return false
+				}
+				objects = objects[1:]
+				continue
+			}
+			p := prefixes[0]
+			prefixes = prefixes[1:]
+			switch {
+			case depth == 0 && p == path.bucketPrefix+"/":
+				// Directory of the prefix not ending with "/".
+				if !list(p, 0) {
+					return false
+				}
+			case depth == 0 && path.bucketPrefix != "" && !strings.HasSuffix(path.bucketPrefix, "/"):
+				// Other directories sharing the prefix.
+			case depth+1 < m.maxDepth:
+				if !list(p, depth+1) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	list(path.bucketPrefix, 0)
+}
+
+// listDelimited returns all of the objects and the common prefixes directly under the prefix.
+func (m *Manager) listDelimited(ctx context.Context, path *s3Path, prefix string) ([]*s3.Object, []string, error) {
+	var objects []*s3.Object
+	var prefixes []string
+	var token *string
+	for {
+		reqCtx, cancel := withTimeout(ctx, m.listTimeout)
+		list, err := m.client(reqCtx, path).ListObjectsV2WithContext(reqCtx, &s3.ListObjectsV2Input{
+			Bucket:            &path.bucket,
+			Prefix:            aws.String(prefix),
+			Delimiter:         aws.String("/"),
+			ContinuationToken: token,
+			StartAfter:        m.startAfter(path),
+		})
+		cancel()
+		if err != nil {
+			return nil, nil, err
+		}
+		m.updateStatistics(ctx, func(s *SyncStatistics) {
+			s.ListedObjects += int64(len(list.Contents))
+		})
+		objects = append(objects, list.Contents...)
+		for _, p := range list.CommonPrefixes {
+			prefixes = append(prefixes, aws.StringValue(p.Prefix))
+		}
+		if token = list.NextContinuationToken; token == nil {
+			break
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return *objects[i].Key < *objects[j].Key })
+	sort.Strings(prefixes)
+	return objects, prefixes, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyDelimiterS3 struct {
+	dummyBudgetS3
+	listed []string
+}
+
+func (s *dummyDelimiterS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := aws.StringValue(in.Prefix)
+	s.listed = append(s.listed, prefix+aws.StringValue(in.Delimiter))
+	out := &s3.ListObjectsV2Output{}
+	seen := make(map[string]bool)
+	for _, key := range s.keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if in.Delimiter != nil {
+			if i := strings.Index(key[len(prefix):], *in.Delimiter); i >= 0 {
+				p := key[:len(prefix)+i+1]
+				if !seen[p] {
+					seen[p] = true
+					out.CommonPrefixes = append(out.CommonPrefixes, &s3.CommonPrefix{Prefix: aws.String(p)})
+				}
+				continue
+			}
+		}
+		out.Contents = append(out.Contents, &s3.Object{
+			Key:          aws.String(key),
+			Size:         aws.Int64(1),
+			LastModified: aws.Time(time.Time{}),
+		})
+	}
+	return out, nil
+}
+
+func TestMaxDepth_S3(t *testing.T) {
+	keys := []string{
+		"prefix/a", "prefix/b.txt", "prefix/b/c", "prefix/b/d/e", "prefix/b/f", "prefix/g/h/i/j",
+	}
+	testCases := map[string]struct {
+		source     string
+		depth      int
+		downloaded []string
+		listed     []string
+	}{
+		"Unlimited": {
+			source:     "s3://bucket/prefix",
+			downloaded: []string{"prefix/a", "prefix/b.txt", "prefix/b/c", "prefix/b/d/e", "prefix/b/f", "prefix/g/h/i/j"},
+			listed:     []string{"prefix"},
+		},
+		"TopLevel": {
+			source:     "s3://bucket/prefix",
+			depth:      1,
+			downloaded: []string{"prefix/a", "prefix/b.txt"},
+			listed:     []string{"prefix/", "prefix//"},
+		},
+		"TwoLevels": {
+			source:     "s3://bucket/prefix/",
+			depth:      2,
+			downloaded: []string{"prefix/a", "prefix/b.txt", "prefix/b/c", "prefix/b/f"},
+			listed:     []string{"prefix//", "prefix/b//", "prefix/g//"},
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyDelimiterS3{dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: keys}}}
+			// Streaming diff requires the listing sorted by the keys.
+			m := New(session.New(), WithMaxDepth(tc.depth), WithStreamingDiff())
+			m.s3 = s
+			if err := m.Sync(context.Background(), tc.source, temp); err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(s.downloaded)
+			if !reflect.DeepEqual(tc.downloaded, s.downloaded) {
+				t.Errorf("Expected %v to be downloaded, got %v", tc.downloaded, s.downloaded)
+			}
+			if !reflect.DeepEqual(tc.listed, s.listed) {
+				t.Errorf("Expected %v to be listed, got %v", tc.listed, s.listed)
+			}
+		})
+	}
+}
+
+func TestMaxDepth_Local(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	for _, name := range []string{"a", "b/c", "b/d/e", "f/g/h"} {
+		filename := filepath.Join(temp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte("a"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for depth, expected := range map[int][]string{
+		0: {"a", "b/c", "b/d/e", "f/g/h"},
+		1: {"a"},
+		2: {"a", "b/c"},
+	} {
+		m := New(session.New(), WithMaxDepth(depth))
+		var names []string
+		for fi := range m.listLocalFiles(context.Background(), temp, nil) {
+			if fi.err != nil {
+				t.Fatal(fi.err)
+			}
+			names = append(names, filepath.ToSlash(fi.name))
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(expected, names) {
+			t.Errorf("Depth %d: expected %v, got %v", depth, expected, names)
+		}
+	}
+}
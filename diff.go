@@ -0,0 +1,253 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// filterFiles returns the channel which receives the file operations
+// to sync the destination to the source.
+// The streaming merge diff is used if enabled and the destination keys and
+// the local file names are not mapped.
+// If destFiles is nil, i.e. the destination is known empty, all of the source files
+// are synced without the diff.
+// The files are compared by the hash workers if WithHashWorkers is given.
+// The files out of the sizes of WithMinSize and WithMaxSize are excluded.
+// The comparisons of the files unchanged since the previous syncs are skipped by WithStateFile.
+func (m *Manager) filterFiles(ctx context.Context, sourceFiles, destFiles chan *fileInfo) chan *fileOp {
+	sourceFiles, destFiles = m.filterSizes(ctx, sourceFiles), m.filterSizes(ctx, destFiles)
+	cmp := m.stateComparator(ctx, m.fileComparator())
+	diffCmp := cmp
+	if m.hashWorkers > 0 {
+		diffCmp = syncAll
+	}
+	var ops chan *fileOp
+	if destFiles == nil {
+		ops = seedFilesForSync(sourceFiles, m.skipped(ctx))
+	} else if m.streamingDiff && len(m.keyMappers) == 0 && m.oddKeyPolicy != OddKeyEscape && m.nameCodec == nil {
+		ops = mergeFilesForSync(sourceFiles, destFiles, m.del, diffCmp, m.skipped(ctx))
+	} else {
+		ops = filterFilesForSync(sourceFiles, destFiles, m.del, diffCmp, m.skipped(ctx))
+	}
+	if m.hashWorkers > 0 {
+		ops = m.compareFiles(ctx, ops, cmp, m.skipped(ctx))
+	}
+	if b := timeBudgetFrom(ctx); b != nil {
+		return b.limit(ctx, ops)
+	}
+	return ops
+}
+
+// listLocalFiles lists the local files in the order required by the streaming merge diff if enabled,
+// handling the symbolic links by the policy.
+func (m *Manager) listLocalFiles(ctx context.Context, basePath string, patterns []*regexp.Regexp) chan *fileInfo {
+	return m.walkLocal(ctx, basePath, patterns, true)
+}
+
+// listDestLocalFiles lists the local destination files like listLocalFiles,
+// but doesn't exclude the files by the filters if WithDeleteExcluded is set.
+func (m *Manager) listDestLocalFiles(ctx context.Context, basePath string, patterns []*regexp.Regexp) chan *fileInfo {
+	return m.walkLocal(ctx, basePath, patterns, !m.deleteExcluded)
+}
+
+func (m *Manager) walkLocal(ctx context.Context, basePath string, patterns []*regexp.Regexp, filter bool) chan *fileInfo {
+	walk := filepath.Walk
+	if m.streamingDiff || timeBudgetFrom(ctx) != nil {
+		walk = walkSorted
+		if m.symlinkPolicy == SymlinkFollow {
+			walk = walkSortedFollow
+		}
+	}
+	walk = m.depthWalk(m.symlinkWalk(walk))
+	if filter {
+		walk = m.pruneWalk(m.ignoreWalk(ctx, walk))
+	}
+	return m.unescapeLocalFiles(ctx, walkLocalFiles(ctx, basePath, patterns, walk))
+}
+
+// seedFilesForSync returns the channel which receives all of the source files
+// to be synced to the empty destination, except the postponed ones.
+func seedFilesForSync(sourceFileChan chan *fileInfo, onSkip func(*fileInfo)) chan *fileOp {
+	c := make(chan *fileOp)
+
+	go func() {
+		defer close(c)
+		for sourceInfo := range sourceFileChan {
+			if sourceInfo.postponed {
+				if onSkip != nil {
+					onSkip(sourceInfo)
+				}
+				continue
+			}
+			c <- &fileOp{fileInfo: sourceInfo}
+		}
+	}()
+	return c
+}
+
+// mergeFilesForSync is the same as filterFilesForSync, but compares the source and
+// destination files by merging the listings sorted by the name in lexicographic byte order,
+// so that the memory usage doesn't depend on the number of the files.
+// It sends an error if the listing is not sorted.
+func mergeFilesForSync(sourceFileChan, destFileChan chan *fileInfo, del bool, cmp Comparator, onSkip func(*fileInfo)) chan *fileOp {
+	c := make(chan *fileOp)
+
+	go func() {
+		defer close(c)
+
+		var dest *fileInfo
+		var destName string
+		nextDest := func() bool {
+			prev := destName
+			d, ok := <-destFileChan
+			switch {
+			case !ok:
+				dest, destName = nil, ""
+				return true
+			case d.err != nil:
+				c <- &fileOp{fileInfo: &fileInfo{err: d.err}}
+				return false
+			}
+			dest, destName = d, filepath.ToSlash(d.name)
+			if prev != "" && destName <= prev {
+				c <- &fileOp{fileInfo: &fileInfo{err: fmt.Errorf("destination listing is not sorted: %s after %s", destName, prev)}}
+				return false
+			}
+			return true
+		}
+		if !nextDest() {
+			return
+		}
+
+		var prev string
+		for sourceInfo := range sourceFileChan {
+			if sourceInfo.err != nil {
+				c <- &fileOp{fileInfo: sourceInfo}
+				continue
+			}
+			name := filepath.ToSlash(sourceInfo.destKeyName())
+			if prev != "" && name <= prev {
+				c <- &fileOp{fileInfo: &fileInfo{err: fmt.Errorf("source listing is not sorted: %s after %s", name, prev)}}
+				return
+			}
+			prev = name
+
+			for dest != nil && destName < name {
+				// The source doesn't exist
+				if del {
+					c <- &fileOp{fileInfo: dest, op: opDelete}
+				}
+				if !nextDest() {
+					return
+				}
+			}
+			ok := dest != nil && destName == name
+			sourceInfo.destExists = ok
+			switch {
+			case sourceInfo.postponed:
+				if onSkip != nil {
+					onSkip(sourceInfo)
+				}
+			case !ok || cmp.ShouldSync(sourceInfo.export(), dest.export()):
+				if ok {
+					sourceInfo.overwritten = dest
+				}
+				c <- &fileOp{fileInfo: sourceInfo}
+			case onSkip != nil:
+				onSkip(sourceInfo)
+			}
+			if ok && !nextDest() {
+				return
+			}
+		}
+		for dest != nil {
+			if del {
+				c <- &fileOp{fileInfo: dest, op: opDelete}
+			}
+			if !nextDest() {
+				return
+			}
+		}
+	}()
+
+	return c
+}
+
+// walkSorted walks the file tree like filepath.Walk, but in the lexicographic byte order
+// of the slash separated paths, which is the same as the order of S3 listing.
+// filepath.Walk visits "a/b" before "a.txt" while "a.txt" < "a/b".
+func walkSorted(root string, fn filepath.WalkFunc) error {
+	return walkSortedLinks(root, fn, false)
+}
+
+// walkSortedFollow is walkSorted for SymlinkFollow, which sorts the symbolic links
+// to the directories as the directories since their contents are walked into.
+func walkSortedFollow(root string, fn filepath.WalkFunc) error {
+	return walkSortedLinks(root, fn, true)
+}
+
+func walkSortedLinks(root string, fn filepath.WalkFunc, follow bool) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkSortedDir(root, info, fn, follow)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkSortedDir(path string, info os.FileInfo, fn filepath.WalkFunc, follow bool) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+	entries, err := ioutil.ReadDir(path)
+	err1 := fn(path, info, err)
+	if err != nil || err1 != nil {
+		return err1
+	}
+	keys := make(map[string]string, len(entries))
+	for _, e := range entries {
+		isDir := e.IsDir()
+		if follow && e.Mode()&os.ModeSymlink != 0 {
+			if stat, err := os.Stat(filepath.Join(path, e.Name())); err == nil {
+				isDir = stat.IsDir()
+			}
+		}
+		keys[e.Name()] = e.Name()
+		if isDir {
+			keys[e.Name()] += "/"
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return keys[entries[i].Name()] < keys[entries[j].Name()]
+	})
+	for _, e := range entries {
+		if err := walkSortedDir(filepath.Join(path, e.Name()), e, fn, follow); err != nil {
+			if !e.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeFilesForSync(t *testing.T) {
+	t0 := time.Now()
+	source := listFileInfos(
+		&fileInfo{name: "a.txt", size: 1, lastModified: t0},
+		&fileInfo{name: "a/b", size: 1, lastModified: t0},
+		&fileInfo{name: "c", size: 2, lastModified: t0},
+		&fileInfo{name: "e", size: 1, lastModified: t0, postponed: true},
+		&fileInfo{name: "f", size: 1, lastModified: t0},
+	)
+	dest := listFileInfos(
+		&fileInfo{name: "0", size: 1, lastModified: t0},
+		&fileInfo{name: "a/b", size: 1, lastModified: t0},
+		&fileInfo{name: "c", size: 1, lastModified: t0},
+		&fileInfo{name: "d", size: 1, lastModified: t0},
+		&fileInfo{name: "e", size: 2, lastModified: t0},
+		&fileInfo{name: "g", size: 1, lastModified: t0},
+	)
+
+	var skipped []string
+	ops := make(map[string]operation)
+	for op := range mergeFilesForSync(source, dest, true, SizeOnlyComparator, func(f *fileInfo) {
+		skipped = append(skipped, f.name)
+	}) {
+		if op.err != nil {
+			t.Fatal(op.err)
+		}
+		ops[op.name] = op.op
+	}
+	expected := map[string]operation{
+		"0":     opDelete,
+		"a.txt": opUpdate,
+		"c":     opUpdate,
+		"d":     opDelete,
+		"f":     opUpdate,
+		"g":     opDelete,
+	}
+	if !reflect.DeepEqual(expected, ops) {
+		t.Errorf("Expected %v, got %v", expected, ops)
+	}
+	if !reflect.DeepEqual([]string{"a/b", "e"}, skipped) {
+		t.Errorf("Unexpected skipped files %v", skipped)
+	}
+}
+
+func TestMergeFilesForSync_Unsorted(t *testing.T) {
+	source := listFileInfos(
+		&fileInfo{name: "b"},
+		&fileInfo{name: "a"},
+	)
+	var errs int
+	for op := range mergeFilesForSync(source, listFileInfos(), false, SizeOnlyComparator, nil) {
+		if op.err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("Expected an error for the unsorted listing, got %d", errs)
+	}
+}
+
+func TestSeedFilesForSync(t *testing.T) {
+	source := listFileInfos(
+		&fileInfo{name: "a", size: 1},
+		&fileInfo{name: "b", size: 1, postponed: true},
+		&fileInfo{name: "c", size: 1},
+	)
+
+	var names, skipped []string
+	for op := range seedFilesForSync(source, func(f *fileInfo) {
+		skipped = append(skipped, f.name)
+	}) {
+		if op.op != opUpdate {
+			t.Errorf("Expected update of %s, got %v", op.name, op.op)
+		}
+		names = append(names, op.name)
+	}
+	if expected := []string{"a", "c"}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	if expected := []string{"b"}; !reflect.DeepEqual(expected, skipped) {
+		t.Errorf("Expected skipped %v, got %v", expected, skipped)
+	}
+}
+
+func TestWalkSorted(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	defer os.RemoveAll(temp)
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	for _, name := range []string{"a/b", "a.txt", "a-b/c", "b"} {
+		filename := filepath.Join(temp, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte("foo"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	err = walkSorted(temp, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(temp, path)
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"a-b/c", "a.txt", "a/b", "b"}
+	if !reflect.DeepEqual(expected, names) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
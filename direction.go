@@ -0,0 +1,58 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Direction is the allowed direction of the sync.
+type Direction int
+
+const (
+	// AnyDirection allows all directions of the sync.
+	AnyDirection Direction = iota
+	// DownloadOnly allows only S3 to local sync.
+	DownloadOnly
+	// UploadOnly allows only local to S3 sync.
+	UploadOnly
+)
+
+func (d Direction) String() string {
+	switch d {
+	case AnyDirection:
+		return "any"
+	case DownloadOnly:
+		return "download-only"
+	case UploadOnly:
+		return "upload-only"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// ErrDirection is returned if the sync is invoked in the direction not allowed by WithDirection.
+var ErrDirection = errors.New("sync direction is not allowed")
+
+// checkDirection returns ErrDirection if the sync between the given types
+// of the source and destination is not allowed.
+func (m *Manager) checkDirection(sourceIsS3, destIsS3 bool) error {
+	switch {
+	case m.direction == DownloadOnly && !(sourceIsS3 && !destIsS3):
+		return fmt.Errorf("%w: %s manager accepts only s3 to local sync", ErrDirection, m.direction)
+	case m.direction == UploadOnly && !(!sourceIsS3 && destIsS3):
+		return fmt.Errorf("%w: %s manager accepts only local to s3 sync", ErrDirection, m.direction)
+	}
+	return nil
+}
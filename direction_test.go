@@ -0,0 +1,54 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithDirection(t *testing.T) {
+	testCases := map[string]struct {
+		direction    Direction
+		source, dest string
+		allowed      bool
+	}{
+		"Any_Upload":            {AnyDirection, "local", "s3://bucket", true},
+		"DownloadOnly_Download": {DownloadOnly, "s3://bucket", "local", true},
+		"DownloadOnly_Upload":   {DownloadOnly, "local", "s3://bucket", false},
+		"DownloadOnly_Copy":     {DownloadOnly, "s3://bucket", "s3://bucket2", false},
+		"UploadOnly_Upload":     {UploadOnly, "local", "s3://bucket", true},
+		"UploadOnly_Download":   {UploadOnly, "s3://bucket", "local", false},
+		"UploadOnly_Copy":       {UploadOnly, "s3://bucket", "s3://bucket2", false},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m := New(getSession(), WithDirection(tt.direction))
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := m.Sync(ctx, tt.source, tt.dest)
+			if rejected := errors.Is(err, ErrDirection); rejected == tt.allowed {
+				t.Errorf("Expected allowed: %v, got %v", tt.allowed, err)
+			}
+		})
+	}
+	t.Run("Providers", func(t *testing.T) {
+		m := New(getSession(), WithDirection(DownloadOnly))
+		if err := m.SyncProviders(context.Background(), nil, "s3://bucket"); !errors.Is(err, ErrDirection) {
+			t.Errorf("Expected %v, got %v", ErrDirection, err)
+		}
+	})
+}
@@ -0,0 +1,390 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// doctorProbeSize is the size of the object transferred to measure the throughput.
+	doctorProbeSize = 1024 * 1024
+	// doctorSampleSize is the number of the source files sampled to check the filters.
+	doctorSampleSize = 1000
+	// maxClockSkew is the clock skew to be warned.
+	// Skew makes the modification time comparison unreliable.
+	maxClockSkew = time.Minute
+	// maxSignatureSkew is the clock skew which causes the request signature error.
+	maxSignatureSkew = 15 * time.Minute
+)
+
+// CheckStatus is the result status of the diagnostic check.
+type CheckStatus int
+
+const (
+	CheckOK CheckStatus = iota
+	CheckWarning
+	CheckError
+	CheckSkipped
+)
+
+func (s CheckStatus) String() string {
+	switch s {
+	case CheckOK:
+		return "OK"
+	case CheckWarning:
+		return "WARNING"
+	case CheckError:
+		return "ERROR"
+	case CheckSkipped:
+		return "SKIPPED"
+	}
+	return "CheckStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// DoctorCheck is the result of a diagnostic check.
+type DoctorCheck struct {
+	Name    string
+	Target  string
+	Status  CheckStatus
+	Message string
+}
+
+func (c DoctorCheck) String() string {
+	return fmt.Sprintf("[%s] %s (%s): %s", c.Status, c.Name, c.Target, c.Message)
+}
+
+// DoctorReport is the result of Doctor.
+type DoctorReport struct {
+	Checks []DoctorCheck
+}
+
+// Problems returns the checks resulted in warning or error.
+func (r *DoctorReport) Problems() []DoctorCheck {
+	var ret []DoctorCheck
+	for _, c := range r.Checks {
+		if c.Status == CheckWarning || c.Status == CheckError {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (r *DoctorReport) add(name, target string, status CheckStatus, format string, v ...interface{}) {
+	c := DoctorCheck{
+		Name:    name,
+		Target:  target,
+		Status:  status,
+		Message: fmt.Sprintf(format, v...),
+	}
+	if status == CheckWarning || status == CheckError {
+		println(c.String())
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// Doctor diagnoses the sync between the source and the destination before a big run.
+// It checks the clock skew, the bucket versioning and encryption settings,
+// the permissions, the throughput by a small probe transfer and the filter sanity,
+// and reports actionable warnings.
+// Write probes are skipped in dry-run and read-only mode.
+func (m *Manager) Doctor(ctx context.Context, source, dest string) (*DoctorReport, error) {
+	sourceURL, err := parseURL(source)
+	if err != nil {
+		return nil, err
+	}
+	destURL, err := parseURL(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &DoctorReport{}
+	var sourcePath, destPath *s3Path
+	if isS3URL(sourceURL) {
+		if sourcePath, err = urlToS3Path(sourceURL); err != nil {
+			return nil, err
+		}
+		sourcePath.source = true
+	}
+	if isS3URL(destURL) {
+		if destPath, err = urlToS3Path(destURL); err != nil {
+			return nil, err
+		}
+	}
+	if sourcePath == nil && destPath == nil {
+		return nil, errors.New("local to local sync is not supported")
+	}
+
+	if sourcePath != nil {
+		m.checkClockSkew(ctx, r, sourcePath)
+	} else {
+		m.checkClockSkew(ctx, r, destPath)
+	}
+
+	if sourcePath != nil {
+		m.checkBucket(ctx, r, sourcePath, false)
+		m.checkS3Read(ctx, r, sourcePath)
+	} else {
+		checkLocalRead(r, source)
+	}
+	if destPath != nil {
+		m.checkBucket(ctx, r, destPath, true)
+		m.checkS3Write(ctx, r, destPath)
+	} else {
+		m.checkLocalWrite(r, dest)
+	}
+
+	m.checkFilters(ctx, r, sourceURL, source)
+
+	return r, nil
+}
+
+func (m *Manager) checkClockSkew(ctx context.Context, r *DoctorReport, path *s3Path) {
+	const name = "clock skew"
+	req, _ := m.client(ctx, path).HeadBucketRequest(&s3.HeadBucketInput{Bucket: aws.String(path.bucket)})
+	req.SetContext(ctx)
+	t0 := time.Now()
+	err := req.Send()
+	if req.HTTPResponse == nil || req.HTTPResponse.Header.Get("Date") == "" {
+		r.add(name, path.bucket, CheckSkipped, "server time is not available: %v", err)
+		return
+	}
+	serverTime, perr := http.ParseTime(req.HTTPResponse.Header.Get("Date"))
+	if perr != nil {
+		r.add(name, path.bucket, CheckSkipped, "failed to parse server time: %v", perr)
+		return
+	}
+	// Date header has the precision of a second.
+	skew := t0.Add(time.Since(t0) / 2).Sub(serverTime).Truncate(time.Second)
+	switch {
+	case skew > maxSignatureSkew || skew < -maxSignatureSkew:
+		r.add(name, path.bucket, CheckError, "local clock is off by %v: requests will be rejected, synchronize the clock", skew)
+	case skew > maxClockSkew || skew < -maxClockSkew:
+		r.add(name, path.bucket, CheckWarning, "local clock is off by %v: modification time comparison is unreliable, synchronize the clock or use WithSizeOnly or WithChecksum", skew)
+	default:
+		r.add(name, path.bucket, CheckOK, "local clock is off by %v", skew)
+	}
+}
+
+func (m *Manager) checkBucket(ctx context.Context, r *DoctorReport, path *s3Path, isDest bool) {
+	ver, err := m.client(ctx, path).GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(path.bucket),
+	})
+	switch {
+	case err != nil:
+		r.add("versioning", path.bucket, CheckWarning, "failed to get versioning configuration: %v", err)
+	case aws.StringValue(ver.Status) == s3.BucketVersioningStatusEnabled && isDest && m.del:
+		r.add("versioning", path.bucket, CheckWarning, "versioning is enabled: deleted and overwritten objects remain as noncurrent versions and cost storage, consider a lifecycle rule")
+	case aws.StringValue(ver.Status) == s3.BucketVersioningStatusEnabled:
+		r.add("versioning", path.bucket, CheckOK, "versioning is enabled")
+	default:
+		r.add("versioning", path.bucket, CheckOK, "versioning is not enabled")
+	}
+
+	enc, err := m.client(ctx, path).GetBucketEncryptionWithContext(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(path.bucket),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ServerSideEncryptionConfigurationNotFoundError" {
+			r.add("encryption", path.bucket, CheckWarning, "default encryption is not configured")
+			return
+		}
+		r.add("encryption", path.bucket, CheckWarning, "failed to get encryption configuration: %v", err)
+		return
+	}
+	var algos []string
+	if enc.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range enc.ServerSideEncryptionConfiguration.Rules {
+			if rule.ApplyServerSideEncryptionByDefault != nil {
+				algos = append(algos, aws.StringValue(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm))
+			}
+		}
+	}
+	r.add("encryption", path.bucket, CheckOK, "default encryption: %v", algos)
+}
+
+func (m *Manager) checkS3Read(ctx context.Context, r *DoctorReport, path *s3Path) {
+	list, err := m.client(ctx, path).ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(path.bucket),
+		Prefix:  aws.String(path.bucketPrefix),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		r.add("list permission", path.String(), CheckError, "failed to list objects: %v", err)
+		return
+	}
+	r.add("list permission", path.String(), CheckOK, "objects can be listed")
+
+	if len(list.Contents) == 0 {
+		r.add("read permission", path.String(), CheckSkipped, "no object to read")
+		return
+	}
+	key := aws.StringValue(list.Contents[0].Key)
+	t0 := time.Now()
+	obj, err := m.client(ctx, path).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket:               aws.String(path.bucket),
+		Key:                  aws.String(key),
+		Range:                aws.String(fmt.Sprintf("bytes=0-%d", doctorProbeSize-1)),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		r.add("read permission", path.String(), CheckError, "failed to get %s: %v", key, err)
+		return
+	}
+	n, err := io.Copy(ioutil.Discard, obj.Body)
+	obj.Body.Close()
+	if err != nil {
+		r.add("read permission", path.String(), CheckError, "failed to read %s: %v", key, err)
+		return
+	}
+	r.add("read permission", path.String(), CheckOK, "objects can be read")
+	r.add("download throughput", path.String(), CheckOK, "%s", throughput(n, time.Since(t0)))
+}
+
+func (m *Manager) checkS3Write(ctx context.Context, r *DoctorReport, path *s3Path) {
+	if m.dryrun || m.readOnly {
+		r.add("write permission", path.String(), CheckSkipped, "write probe is disabled in dry-run and read-only mode")
+		return
+	}
+	key := filepath.ToSlash(filepath.Join(path.bucketPrefix, fmt.Sprintf(".s3sync-doctor-%d", time.Now().UnixNano())))
+	t0 := time.Now()
+	_, err := m.destClient(ctx, path.bucket).PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(path.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(make([]byte, doctorProbeSize)),
+		ACL:                  m.acl,
+		ServerSideEncryption: m.sse,
+		SSEKMSKeyId:          m.sseKMSKeyID,
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		r.add("write permission", path.String(), CheckError, "failed to put probe object: %v", err)
+		return
+	}
+	r.add("write permission", path.String(), CheckOK, "objects can be written")
+	r.add("upload throughput", path.String(), CheckOK, "%s", throughput(doctorProbeSize, time.Since(t0)))
+
+	_, err = m.destClient(ctx, path.bucket).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(path.bucket),
+		Key:    aws.String(key),
+	})
+	switch {
+	case err != nil && m.del:
+		r.add("delete permission", path.String(), CheckError, "failed to delete probe object %s: %v", key, err)
+	case err != nil:
+		r.add("delete permission", path.String(), CheckWarning, "failed to delete probe object %s, remove it manually: %v", key, err)
+	default:
+		r.add("delete permission", path.String(), CheckOK, "objects can be deleted")
+	}
+}
+
+func checkLocalRead(r *DoctorReport, path string) {
+	if _, err := os.Stat(path); err != nil {
+		r.add("read permission", path, CheckError, "failed to stat: %v", err)
+		return
+	}
+	r.add("read permission", path, CheckOK, "path exists")
+}
+
+func (m *Manager) checkLocalWrite(r *DoctorReport, path string) {
+	if m.dryrun || m.readOnly {
+		r.add("write permission", path, CheckSkipped, "write probe is disabled in dry-run and read-only mode")
+		return
+	}
+	// Find the nearest existing directory since the destination is created on sync.
+	dir := path
+	for {
+		if stat, err := os.Stat(dir); err == nil {
+			if !stat.IsDir() {
+				dir = filepath.Dir(dir)
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	f, err := ioutil.TempFile(dir, ".s3sync-doctor-")
+	if err != nil {
+		r.add("write permission", path, CheckError, "failed to create a file in %s: %v", dir, err)
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
+	r.add("write permission", path, CheckOK, "files can be written")
+}
+
+func (m *Manager) checkFilters(ctx context.Context, r *DoctorReport, sourceURL *url.URL, source string) {
+	const name = "filters"
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var files chan *fileInfo
+	if isS3URL(sourceURL) {
+		sourcePath, _ := urlToS3Path(sourceURL)
+		sourcePath.source = true
+		files = m.listS3Files(ctx, sourcePath, nil)
+	} else {
+		files = listLocalFiles(ctx, source, nil)
+	}
+
+	var sampled, passed, rejected int
+	for fi := range m.mapDestKeys(ctx, files) {
+		if fi.err != nil || !m.included(fi.name) {
+			rejected++
+		} else {
+			passed++
+		}
+		if sampled++; sampled >= doctorSampleSize {
+			break
+		}
+	}
+	switch {
+	case sampled == 0:
+		r.add(name, source, CheckWarning, "source is empty: check the source path")
+	case passed == 0:
+		r.add(name, source, CheckWarning, "all of %d sampled source files are rejected: check the filter and key mapping options", sampled)
+	case rejected > 0:
+		r.add(name, source, CheckOK, "%d of %d sampled source files are accepted, and %d are rejected", passed, sampled, rejected)
+	default:
+		r.add(name, source, CheckOK, "%d sampled source files are accepted", sampled)
+	}
+}
+
+func throughput(n int64, d time.Duration) string {
+	if d <= 0 {
+		d = time.Nanosecond
+	}
+	return fmt.Sprintf("%d bytes in %v (%.2f MiB/s)", n, d, float64(n)/d.Seconds()/1024/1024)
+}
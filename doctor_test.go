@@ -0,0 +1,166 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyDoctorS3 struct {
+	s3iface.S3API
+	skew    time.Duration
+	objects map[string]int64
+}
+
+func (s *dummyDoctorS3) HeadBucketRequest(in *s3.HeadBucketInput) (*request.Request, *s3.HeadBucketOutput) {
+	out := &s3.HeadBucketOutput{}
+	req := request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil,
+		&request.Operation{Name: "HeadBucket"}, in, out)
+	req.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Date": []string{time.Now().Add(-s.skew).UTC().Format(http.TimeFormat)},
+			},
+		}
+	})
+	return req, out
+}
+
+func (s *dummyDoctorS3) GetBucketVersioningWithContext(aws.Context, *s3.GetBucketVersioningInput, ...request.Option) (*s3.GetBucketVersioningOutput, error) {
+	return &s3.GetBucketVersioningOutput{Status: aws.String(s3.BucketVersioningStatusEnabled)}, nil
+}
+
+func (s *dummyDoctorS3) GetBucketEncryptionWithContext(aws.Context, *s3.GetBucketEncryptionInput, ...request.Option) (*s3.GetBucketEncryptionOutput, error) {
+	return nil, awserr.New("ServerSideEncryptionConfigurationNotFoundError", "not found", nil)
+}
+
+func (s *dummyDoctorS3) ListObjectsV2WithContext(aws.Context, *s3.ListObjectsV2Input, ...request.Option) (*s3.ListObjectsV2Output, error) {
+	return &s3.ListObjectsV2Output{}, nil
+}
+
+func (s *dummyDoctorS3) PutObjectWithContext(ctx aws.Context, in *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	n, _ := in.Body.Seek(0, 2)
+	s.objects[*in.Key] = n
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (s *dummyDoctorS3) DeleteObjectWithContext(ctx aws.Context, in *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	delete(s.objects, *in.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestDoctor(t *testing.T) {
+	statuses := func(r *DoctorReport) map[string]CheckStatus {
+		ret := make(map[string]CheckStatus)
+		for _, c := range r.Checks {
+			ret[c.Name] = c.Status
+		}
+		return ret
+	}
+
+	t.Run("LocalToS3", func(t *testing.T) {
+		s := &dummyDoctorS3{skew: 5 * time.Minute, objects: make(map[string]int64)}
+		m := New(session.New(), WithDelete())
+		m.s3 = s
+
+		dir := t.TempDir()
+		if err := ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := m.Doctor(context.Background(), dir, "s3://bucket/dest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]CheckStatus{
+			"clock skew":        CheckWarning,
+			"versioning":        CheckWarning,
+			"encryption":        CheckWarning,
+			"read permission":   CheckOK,
+			"write permission":  CheckOK,
+			"upload throughput": CheckOK,
+			"delete permission": CheckOK,
+			"filters":           CheckOK,
+		}
+		got := statuses(r)
+		for name, status := range expected {
+			if got[name] != status {
+				t.Errorf("Expected %s to be %v, got %v", name, status, got[name])
+			}
+		}
+		if len(r.Problems()) != 3 {
+			t.Errorf("Expected 3 problems, got %v", r.Problems())
+		}
+		if len(s.objects) != 0 {
+			t.Errorf("Probe object must be removed, remains: %v", s.objects)
+		}
+	})
+	t.Run("S3ToLocalDryRun", func(t *testing.T) {
+		s := &dummyDoctorS3{objects: make(map[string]int64)}
+		m := New(session.New(), WithDryRun())
+		m.s3 = s
+
+		r, err := m.Doctor(context.Background(), "s3://bucket/src", t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]CheckStatus{
+			"clock skew":       CheckOK,
+			"versioning":       CheckOK,
+			"list permission":  CheckOK,
+			"read permission":  CheckSkipped,
+			"write permission": CheckSkipped,
+			"filters":          CheckWarning,
+		}
+		got := statuses(r)
+		for name, status := range expected {
+			if got[name] != status {
+				t.Errorf("Expected %s to be %v, got %v", name, status, got[name])
+			}
+		}
+	})
+	t.Run("PartiallyExcluded", func(t *testing.T) {
+		s := &dummyDoctorS3{objects: make(map[string]int64)}
+		m := New(session.New(), WithExclude("*.log"))
+		m.s3 = s
+
+		dir := t.TempDir()
+		for _, name := range []string{"a.txt", "b.log"} {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("test"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r, err := m.Doctor(context.Background(), dir, "s3://bucket/dest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := statuses(r)["filters"]; status != CheckOK {
+			t.Errorf("Expected the deliberate exclusion to be OK, got %v", status)
+		}
+	})
+}
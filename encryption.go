@@ -0,0 +1,249 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3crypto"
+)
+
+// aesGCMWrap is the wrap algorithm of the content encryption key encrypted by
+// the user supplied key, compatible with the Amazon S3 Encryption Client.
+const aesGCMWrap = "AES/GCM"
+
+// gcmTagSize is the size of the authentication tag appended to the encrypted contents.
+const gcmTagSize = 16
+
+var errNotEncrypted = errors.New("object is not encrypted by the client-side encryption")
+
+// clientSideEncryption encrypts the contents of the objects in the envelope
+// format of the Amazon S3 Encryption Client.
+type clientSideEncryption struct {
+	builder  s3crypto.ContentCipherBuilderWithContext
+	registry *s3crypto.CryptoRegistry
+	// invalidKey is true if the user supplied key is not an AES-256 key.
+	invalidKey bool
+}
+
+// newKeyEncryption returns the client-side encryption by the user supplied key.
+func newKeyEncryption(key []byte) *clientSideEncryption {
+	w := &aesGCMKeyWrap{key: key}
+	registry := s3crypto.NewCryptoRegistry()
+	registry.AddWrap(aesGCMWrap, func(env s3crypto.Envelope) (s3crypto.CipherDataDecrypter, error) {
+		return &aesGCMKeyWrap{key: key, cekAlgorithm: env.CEKAlg}, nil
+	})
+	s3crypto.RegisterAESGCMContentCipher(registry)
+	return &clientSideEncryption{
+		builder:    s3crypto.AESGCMContentCipherBuilderV2(w).(s3crypto.ContentCipherBuilderWithContext),
+		registry:   registry,
+		invalidKey: len(key) != 32,
+	}
+}
+
+// newKMSEncryption returns the client-side encryption by the KMS data keys.
+func newKMSEncryption(client kmsiface.KMSAPI, keyID string) *clientSideEncryption {
+	registry := s3crypto.NewCryptoRegistry()
+	s3crypto.RegisterKMSContextWrapWithCMK(registry, client, keyID)
+	s3crypto.RegisterAESGCMContentCipher(registry)
+	gen := s3crypto.NewKMSContextKeyGenerator(client, keyID, s3crypto.MaterialDescription{})
+	return &clientSideEncryption{
+		builder:  s3crypto.AESGCMContentCipherBuilderV2(gen).(s3crypto.ContentCipherBuilderWithContext),
+		registry: registry,
+	}
+}
+
+// encrypt returns the reader of the encrypted contents of r and
+// the metadata of the envelope to be stored with the object.
+func (e *clientSideEncryption) encrypt(ctx context.Context, r io.Reader, size int64) (io.Reader, map[string]*string, error) {
+	cc, err := e.builder.ContentCipherWithContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cd := cc.GetCipherData()
+	matdesc, err := json.Marshal(cd.MaterialDescription)
+	if err != nil {
+		return nil, nil, err
+	}
+	env := s3crypto.Envelope{
+		CipherKey:             base64.StdEncoding.EncodeToString(cd.EncryptedKey),
+		IV:                    base64.StdEncoding.EncodeToString(cd.IV),
+		MatDesc:               string(matdesc),
+		WrapAlg:               cd.WrapAlgorithm,
+		CEKAlg:                cd.CEKAlgorithm,
+		TagLen:                cd.TagLength,
+		UnencryptedContentLen: strconv.FormatInt(size, 10),
+	}
+	b, err := json.Marshal(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	var headers map[string]string
+	if err := json.Unmarshal(b, &headers); err != nil {
+		return nil, nil, err
+	}
+	metadata := make(map[string]*string, len(headers))
+	for k, v := range headers {
+		metadata[k] = aws.String(v)
+	}
+	encrypted, err := cc.EncryptContents(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encrypted, metadata, nil
+}
+
+// decrypt returns the reader of the decrypted contents of the object.
+func (e *clientSideEncryption) decrypt(ctx context.Context, out *s3.GetObjectOutput) (io.ReadCloser, error) {
+	headers := make(map[string]string, len(out.Metadata))
+	for k, v := range out.Metadata {
+		headers[strings.ToLower(k)] = aws.StringValue(v)
+	}
+	b, err := json.Marshal(headers)
+	if err != nil {
+		return nil, err
+	}
+	var env s3crypto.Envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return nil, err
+	}
+	if env.CipherKey == "" {
+		return nil, errNotEncrypted
+	}
+	wrap, ok := e.registry.GetWrap(env.WrapAlg)
+	if !ok {
+		return nil, errors.New("unsupported wrap algorithm " + env.WrapAlg)
+	}
+	cek, ok := e.registry.GetCEK(env.CEKAlg)
+	if !ok {
+		return nil, errors.New("unsupported content encryption algorithm " + env.CEKAlg)
+	}
+	decrypter, err := wrap(env)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(env.CipherKey)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := base64.StdEncoding.DecodeString(env.IV)
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	if d, ok := decrypter.(s3crypto.CipherDataDecrypterWithContext); ok {
+		key, err = d.DecryptKeyWithContext(ctx, encryptedKey)
+	} else {
+		key, err = decrypter.DecryptKey(encryptedKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cc, err := cek(s3crypto.CipherData{Key: key, IV: iv, CEKAlgorithm: env.CEKAlg})
+	if err != nil {
+		return nil, err
+	}
+	return cc.DecryptContents(out.Body)
+}
+
+// aesGCMKeyWrap encrypts the content encryption keys by the user supplied key
+// with AES-GCM. The wrapped key is the nonce followed by the encrypted key,
+// authenticated with the content encryption algorithm.
+type aesGCMKeyWrap struct {
+	key          []byte
+	cekAlgorithm string
+}
+
+func (w *aesGCMKeyWrap) GenerateCipherDataWithCEKAlg(ctx aws.Context, keySize, ivSize int, cekAlgorithm string) (s3crypto.CipherData, error) {
+	gcm, err := newGCM(w.key)
+	if err != nil {
+		return s3crypto.CipherData{}, err
+	}
+	buf := make([]byte, keySize+ivSize+gcm.NonceSize())
+	if _, err := rand.Read(buf); err != nil {
+		return s3crypto.CipherData{}, err
+	}
+	cek, iv, nonce := buf[:keySize], buf[keySize:keySize+ivSize], buf[keySize+ivSize:]
+	return s3crypto.CipherData{
+		Key:                 cek,
+		IV:                  iv,
+		WrapAlgorithm:       aesGCMWrap,
+		MaterialDescription: s3crypto.MaterialDescription{},
+		EncryptedKey:        gcm.Seal(append([]byte{}, nonce...), nonce, cek, []byte(cekAlgorithm)),
+	}, nil
+}
+
+func (w *aesGCMKeyWrap) DecryptKey(key []byte) ([]byte, error) {
+	gcm, err := newGCM(w.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) < gcm.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	return gcm.Open(nil, key[:gcm.NonceSize()], key[gcm.NonceSize():], []byte(w.cekAlgorithm))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getDecryptedObject downloads the object by a single request, since the contents
+// encrypted by AES-GCM can't be decrypted by ranges, and decrypts the contents.
+func (m *Manager) getDecryptedObject(ctx context.Context, w io.WriterAt, in *s3.GetObjectInput) (int64, error) {
+	out, err := m.regionalClient(ctx, m.sourceClient(), aws.StringValue(in.Bucket)).GetObjectWithContext(ctx, in)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Body.Close()
+	r, err := m.encryption.decrypt(ctx, out)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.Copy(&offsetWriter{w: w}, r)
+}
+
+// encryptedSizes returns the pair of the files with the size of the encrypted
+// object reduced by the authentication tag to be compared with the local file.
+func (m *Manager) encryptedSizes(src, dst *FileInfo) (*FileInfo, *FileInfo) {
+	if m.encryption == nil || src.remote == dst.remote {
+		return src, dst
+	}
+	trim := func(f *FileInfo) *FileInfo {
+		if !f.remote || f.Size < gcmTagSize {
+			return f
+		}
+		trimmed := *f
+		trimmed.Size -= gcmTagSize
+		return &trimmed
+	}
+	return trim(src), trim(dst)
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3crypto"
+)
+
+type dummyKMS struct {
+	kmsiface.KMSAPI
+}
+
+var dummyDataKey = bytes.Repeat([]byte{1}, 32)
+
+func (k *dummyKMS) GenerateDataKeyWithContext(ctx aws.Context, in *kms.GenerateDataKeyInput, opts ...request.Option) (*kms.GenerateDataKeyOutput, error) {
+	return &kms.GenerateDataKeyOutput{
+		Plaintext:      dummyDataKey,
+		CiphertextBlob: []byte("wrapped:" + aws.StringValue(in.KeyId)),
+	}, nil
+}
+
+func (k *dummyKMS) DecryptWithContext(ctx aws.Context, in *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	// The key ID is omitted when the wrap accepts any CMK.
+	if in.KeyId != nil && string(in.CiphertextBlob) != "wrapped:"+aws.StringValue(in.KeyId) ||
+		!bytes.HasPrefix(in.CiphertextBlob, []byte("wrapped:")) {
+		return nil, &kms.InvalidCiphertextException{}
+	}
+	return &kms.DecryptOutput{Plaintext: dummyDataKey}, nil
+}
+
+// encryptedObject returns the object encrypted by the client-side encryption
+// in the form of the GetObject response.
+func encryptedObject(t *testing.T, e *clientSideEncryption, contents string) *s3.GetObjectOutput {
+	t.Helper()
+	r, envelope, err := e.encrypt(context.Background(), strings.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != len(contents)+gcmTagSize {
+		t.Fatalf("Expected %d bytes of encrypted contents, got %d", len(contents)+gcmTagSize, len(b))
+	}
+	// The metadata keys are canonicalized in the response.
+	metadata := make(map[string]*string)
+	for k, v := range envelope {
+		metadata[http.CanonicalHeaderKey(k)] = v
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(b)), Metadata: metadata}
+}
+
+func TestClientSideEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte{2}, 32)
+	e := newKeyEncryption(key)
+	out := encryptedObject(t, e, "contents")
+	expected := map[string]string{
+		"X-Amz-Wrap-Alg":                   "AES/GCM",
+		"X-Amz-Cek-Alg":                    "AES/GCM/NoPadding",
+		"X-Amz-Tag-Len":                    "128",
+		"X-Amz-Unencrypted-Content-Length": "8",
+	}
+	for k, v := range expected {
+		if actual := aws.StringValue(out.Metadata[k]); actual != v {
+			t.Errorf("Expected %s to be %s, got %s", k, v, actual)
+		}
+	}
+	r, err := e.decrypt(context.Background(), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadAll(r); err != nil || string(b) != "contents" {
+		t.Errorf("Expected the decrypted contents, got %q (%v)", b, err)
+	}
+
+	t.Run("WrongKey", func(t *testing.T) {
+		out := encryptedObject(t, e, "contents")
+		if _, err := newKeyEncryption(bytes.Repeat([]byte{3}, 32)).decrypt(context.Background(), out); err == nil {
+			t.Error("Expected the key unwrap to fail")
+		}
+	})
+	t.Run("Tampered", func(t *testing.T) {
+		out := encryptedObject(t, e, "contents")
+		b, _ := ioutil.ReadAll(out.Body)
+		b[0] ^= 1
+		out.Body = ioutil.NopCloser(bytes.NewReader(b))
+		r, err := e.decrypt(context.Background(), out)
+		if err == nil {
+			_, err = ioutil.ReadAll(r)
+		}
+		if err == nil {
+			t.Error("Expected the authentication to fail")
+		}
+	})
+	t.Run("NotEncrypted", func(t *testing.T) {
+		out := &s3.GetObjectOutput{Body: ioutil.NopCloser(strings.NewReader("contents"))}
+		if _, err := e.decrypt(context.Background(), out); err != errNotEncrypted {
+			t.Errorf("Expected %v, got %v", errNotEncrypted, err)
+		}
+	})
+}
+
+// TestClientSideEncryption_KMS checks that the uploaded object can be decrypted
+// by the Amazon S3 Encryption Client of the SDK.
+func TestClientSideEncryption_KMS(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	if err := ioutil.WriteFile(filepath.Join(temp, "file"), []byte("secret contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var header http.Header
+	var body []byte
+	newClient := func(handler func(r *request.Request)) *s3.S3 {
+		c := s3.New(session.New(), aws.NewConfig().
+			WithRegion("us-east-1").
+			WithCredentials(credentials.AnonymousCredentials))
+		c.Handlers.Send.Clear()
+		c.Handlers.Send.PushBack(handler)
+		return c
+	}
+	m := New(session.New(), WithClientSideEncryptionKMS(&dummyKMS{}, "key-id"))
+	m.s3 = newClient(func(r *request.Request) {
+		b, err := ioutil.ReadAll(r.Params.(*s3.PutObjectInput).Body)
+		if err != nil {
+			r.Error = err
+			return
+		}
+		header, body = r.HTTPRequest.Header, b
+		r.HTTPResponse = &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+	})
+	file := &fileInfo{name: "file", path: filepath.Join(temp, "file"), size: 15, local: true}
+	if err := m.upload(context.Background(), file, temp, &s3Path{bucket: "bucket", bucketPrefix: "prefix"}); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(body, []byte("secret")) {
+		t.Fatal("Contents must be encrypted")
+	}
+
+	cr := s3crypto.NewCryptoRegistry()
+	if err := s3crypto.RegisterKMSContextWrapWithAnyCMK(cr, &dummyKMS{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s3crypto.RegisterAESGCMContentCipher(cr); err != nil {
+		t.Fatal(err)
+	}
+	getClient := newClient(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{StatusCode: http.StatusOK, Header: header, Body: ioutil.NopCloser(bytes.NewReader(body))}
+	})
+	client, err := s3crypto.NewDecryptionClientV2(session.New(), cr, func(o *s3crypto.DecryptionClientOptions) {
+		o.S3Client = getClient
+		o.LoadStrategy = s3crypto.HeaderV2LoadStrategy{}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := client.GetObject(&s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String("prefix/file")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadAll(out.Body); err != nil || string(b) != "secret contents" {
+		t.Errorf("Expected the decrypted contents, got %q (%v)", b, err)
+	}
+}
+
+func TestEncryptedSizes(t *testing.T) {
+	local := &FileInfo{Name: "a", Size: 100}
+	remote := &FileInfo{Name: "a", Size: 100 + gcmTagSize, remote: true}
+	m := New(session.New(), WithClientSideEncryptionKey(bytes.Repeat([]byte{2}, 32)))
+	if m.fileComparator().ShouldSync(local, remote) || m.fileComparator().ShouldSync(remote, local) {
+		t.Error("Expected the sizes without the authentication tags to be compared")
+	}
+	if !New(session.New()).fileComparator().ShouldSync(local, remote) {
+		t.Error("Expected the sizes to differ without the encryption")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// endpointClient returns the client derived from the base client to access the endpoint.
+// The base client is returned as is if it is not *s3.S3.
+func endpointClient(base s3iface.S3API, endpoint string, pathStyle bool) s3iface.S3API {
+	c, ok := base.(*s3.S3)
+	if !ok {
+		return base
+	}
+	sess, err := session.NewSession(c.Config.Copy(aws.NewConfig().
+		WithEndpoint(endpoint).
+		WithS3ForcePathStyle(pathStyle)))
+	if err != nil {
+		return base
+	}
+	ec := s3.New(sess)
+	// The custom handlers of the base client are kept.
+	ec.Handlers = c.Handlers.Copy()
+	return ec
+}
+
+// validateEndpoint returns an error if the endpoint is not an http or https url.
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s is not an http or https url", endpoint)
+	}
+	return nil
+}
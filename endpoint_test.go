@@ -0,0 +1,57 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestWithEndpoint(t *testing.T) {
+	testCases := map[string]struct {
+		pathStyle bool
+		expected  string
+	}{
+		"PathStyle":    {true, "http://localhost:9000/bucket"},
+		"VirtualHosts": {false, "http://bucket.localhost:9000/"},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sess := session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")}))
+			m := NewWithClients(s3.New(sess), s3.New(sess), WithEndpoint("http://localhost:9000", tc.pathStyle))
+
+			for _, c := range []interface{}{m.sourceS3, m.s3} {
+				req, _ := c.(*s3.S3).HeadBucketRequest(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+				if err := req.Build(); err != nil {
+					t.Fatal(err)
+				}
+				if u := req.HTTPRequest.URL.String(); u != tc.expected {
+					t.Errorf("Expected %s, got %s", tc.expected, u)
+				}
+			}
+		})
+	}
+}
+
+func TestWithEndpoint_CustomClient(t *testing.T) {
+	s := &dummyUnusedS3{}
+	m := NewWithClients(nil, s, WithEndpoint("http://localhost:9000", true))
+	if m.s3 != s {
+		t.Error("Expected the custom client to be used as is")
+	}
+}
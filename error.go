@@ -14,21 +14,101 @@
 package s3sync
 
 import (
+	"errors"
 	"strings"
 	"sync"
 )
 
+// FileError is the error of the operation of a file.
+// Use errors.As to get the failed file from the error returned by the sync,
+// and errors.Is or errors.As on the FileError to inspect the cause,
+// e.g. awserr.Error for the S3 errors and os.ErrPermission for the local files.
+type FileError struct {
+	// Op is the operation: "upload", "download", "copy" or "delete".
+	Op string
+	// Path is the local file path, or empty for the operations between the buckets.
+	Path string
+	// Key is the object key, or empty for the deletion of the local file.
+	Key string
+	Err error
+}
+
+func (e *FileError) Error() string {
+	name := e.Path
+	switch {
+	case name == "":
+		name = e.Key
+	case e.Key != "":
+		name += " (" + e.Key + ")"
+	}
+	return e.Op + " " + name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// wrapFileError wraps the error of the file operation by FileError.
+// It is intended to be deferred.
+func wrapFileError(err *error, op, path, key string) {
+	if *err != nil {
+		*err = &FileError{Op: op, Path: path, Key: key, Err: *err}
+	}
+}
+
 type multiErr struct {
 	mu  sync.Mutex
 	err []error
+	// limit is the error limit of the sync by the error policy, or nil if unlimited.
+	limit *errorLimit
 }
 
 func (e *multiErr) Append(err error) {
+	var abort bool
+	if e.limit != nil {
+		var keep bool
+		if keep, abort = e.limit.add(); !keep {
+			return
+		}
+	}
 	e.mu.Lock()
 	e.err = append(e.err, err)
+	if abort {
+		e.err = append(e.err, ErrTooManyErrors)
+	}
 	e.mu.Unlock()
 }
 
+// Unwrap returns the errors to be inspected by errors.Is and errors.As.
+func (e *multiErr) Unwrap() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]error{}, e.err...)
+}
+
+// Is reports whether any of the errors matches the target.
+// errors.Is follows Unwrap() []error only since Go 1.20.
+func (e *multiErr) Is(target error) bool {
+	for _, err := range e.Unwrap() {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors matching the target.
+// errors.As follows Unwrap() []error only since Go 1.20.
+func (e *multiErr) As(target interface{}) bool {
+	for _, err := range e.Unwrap() {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *multiErr) Len() int {
 	e.mu.Lock()
 	defer e.mu.Unlock()
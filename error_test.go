@@ -15,6 +15,7 @@ package s3sync
 
 import (
 	"errors"
+	"os"
 	"testing"
 )
 
@@ -40,3 +41,43 @@ func TestMultiErr(t *testing.T) {
 		}
 	})
 }
+
+func TestFileError(t *testing.T) {
+	testCases := map[string]struct {
+		err      *FileError
+		expected string
+	}{
+		"Upload":      {&FileError{Op: "upload", Path: "dir/file", Key: "prefix/file", Err: os.ErrPermission}, "upload dir/file (prefix/file): permission denied"},
+		"Copy":        {&FileError{Op: "copy", Key: "prefix/file", Err: os.ErrPermission}, "copy prefix/file: permission denied"},
+		"DeleteLocal": {&FileError{Op: "delete", Path: "dir/file", Err: os.ErrPermission}, "delete dir/file: permission denied"},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if s := tc.err.Error(); s != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, s)
+			}
+		})
+	}
+
+	t.Run("Unwrap", func(t *testing.T) {
+		errs := &multiErr{}
+		errs.Append(errors.New("other"))
+		errs.Append(&FileError{Op: "download", Path: "dir/file", Key: "file", Err: os.ErrPermission})
+		if !errors.Is(errs, os.ErrPermission) {
+			t.Error("Cause of the file error must be found")
+		}
+		var fe *FileError
+		if !errors.As(errs, &fe) || fe.Path != "dir/file" {
+			t.Errorf("FileError must be found, got %v", fe)
+		}
+		// Is and As are used by errors.Is and errors.As before Go 1.20.
+		if !errs.Is(os.ErrPermission) || errs.Is(os.ErrNotExist) {
+			t.Error("Is must match only the cause of the file error")
+		}
+		fe = nil
+		if !errs.As(&fe) || fe.Path != "dir/file" {
+			t.Errorf("FileError must be found by As, got %v", fe)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrTooManyErrors is appended to the errors of the sync aborted by the error policy.
+var ErrTooManyErrors = errors.New("sync aborted by too many errors")
+
+// ErrorPolicy is the policy of the sync on the failures of the file operations.
+type ErrorPolicy struct {
+	maxErrors int
+}
+
+var (
+	// ContinueAll continues the sync on the failures and returns all of the errors.
+	// This is the default.
+	ContinueAll = ErrorPolicy{}
+	// FailFast aborts the sync on the first failure.
+	FailFast = MaxErrors(1)
+)
+
+// MaxErrors returns the policy to abort the sync after n failures.
+// MaxErrors(0) is the same as ContinueAll.
+func MaxErrors(n int) ErrorPolicy {
+	return ErrorPolicy{maxErrors: n}
+}
+
+type errorLimitKey struct{}
+
+// errorLimit counts the errors of a sync call and cancels the sync
+// when the number of the errors reaches the limit.
+type errorLimit struct {
+	max    int32
+	n      int32
+	cancel context.CancelFunc
+}
+
+// add counts an error, and returns whether the error should be kept
+// and whether the sync is aborted by the error.
+// The errors after the abort, which are mostly caused by the cancellation, are dropped.
+func (l *errorLimit) add() (keep, abort bool) {
+	n := atomic.AddInt32(&l.n, 1)
+	if n == l.max {
+		l.cancel()
+	}
+	return n <= l.max, n == l.max
+}
+
+// withErrorPolicy returns the context carrying the error limit of the sync call
+// which calls cancel to abort the sync.
+// The limit of the parent context is reused if exists.
+func (m *Manager) withErrorPolicy(ctx context.Context, cancel context.CancelFunc) context.Context {
+	if m.errorPolicy.maxErrors <= 0 {
+		return ctx
+	}
+	if _, ok := ctx.Value(errorLimitKey{}).(*errorLimit); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, errorLimitKey{}, &errorLimit{
+		max:    int32(m.errorPolicy.maxErrors),
+		cancel: cancel,
+	})
+}
+
+// newSyncErrors returns the errors of the sync limited by the error policy.
+func newSyncErrors(ctx context.Context) *multiErr {
+	l, _ := ctx.Value(errorLimitKey{}).(*errorLimit)
+	return &multiErr{limit: l}
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyFailingS3 struct {
+	dummyKeyRangeS3
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *dummyFailingS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return nil, errors.New("failed to get " + *in.Key)
+}
+
+func TestErrorPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		policy        ErrorPolicy
+		expectedCalls int
+		aborted       bool
+	}{
+		"ContinueAll":         {ContinueAll, 5, false},
+		"FailFast":            {FailFast, 1, true},
+		"MaxErrors":           {MaxErrors(3), 3, true},
+		"MaxErrorsNotReached": {MaxErrors(10), 5, false},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyFailingS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+				"prefix/a", "prefix/b", "prefix/c", "prefix/d", "prefix/e",
+			}}}
+			m := New(session.New(), WithParallel(1), WithErrorPolicy(tc.policy))
+			m.s3 = s
+
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			errs, ok := err.(*multiErr)
+			if !ok {
+				t.Fatalf("Expected multiErr, got %v", err)
+			}
+			if s.calls != tc.expectedCalls {
+				t.Errorf("Expected %d requests, got %d", tc.expectedCalls, s.calls)
+			}
+			expectedLen := tc.expectedCalls
+			if tc.aborted {
+				expectedLen++
+				if last := errs.err[len(errs.err)-1]; last != ErrTooManyErrors {
+					t.Errorf("Expected %v at last, got %v", ErrTooManyErrors, last)
+				}
+			}
+			if errs.Len() != expectedLen {
+				t.Errorf("Expected %d errors, got %d: %v", expectedLen, errs.Len(), errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// listObjectsPageSize is the number of the objects in a page of ListObjectsV2.
+const listObjectsPageSize = 1000
+
+// CostAssumptions is the prices and the performance assumed by EstimateCost.
+type CostAssumptions struct {
+	// PutRequestPrice is the price of a PUT, COPY, POST or LIST request.
+	PutRequestPrice float64
+	// GetRequestPrice is the price of a GET request.
+	GetRequestPrice float64
+	// TransferOutPricePerGB is the price of the data transferred out of S3 per GiB.
+	TransferOutPricePerGB float64
+	// Bandwidth is the total bandwidth of the uploads and downloads in bytes per second.
+	// WithBandwidthLimit is applied if it is lower.
+	Bandwidth int64
+	// RequestLatency is the time taken by a request regardless of the size.
+	RequestLatency time.Duration
+}
+
+// DefaultCostAssumptions is the assumptions of the S3 Standard prices
+// in us-east-1 and a 1 Gbps network.
+var DefaultCostAssumptions = CostAssumptions{
+	PutRequestPrice:       0.005 / 1000,
+	GetRequestPrice:       0.0004 / 1000,
+	TransferOutPricePerGB: 0.09,
+	Bandwidth:             125 * 1000 * 1000,
+	RequestLatency:        50 * time.Millisecond,
+}
+
+// CostEstimate is the estimate of a sync calculated by EstimateCost.
+type CostEstimate struct {
+	// PutRequests is the number of the PUT, COPY, POST and LIST requests.
+	PutRequests int64
+	// GetRequests is the number of the GET requests.
+	GetRequests int64
+	// DeleteRequests is the number of the DELETE requests, which are free.
+	DeleteRequests int64
+	// TransferredBytes is the number of the bytes uploaded and downloaded.
+	TransferredBytes int64
+	// RequestCost is the price of the requests.
+	RequestCost float64
+	// TransferCost is the price of the data transferred out of S3 by the downloads.
+	TransferCost float64
+	// Duration is the estimated wall-clock time of the sync.
+	Duration time.Duration
+}
+
+// TotalCost returns the sum of the request and transfer costs.
+func (e *CostEstimate) TotalCost() float64 {
+	return e.RequestCost + e.TransferCost
+}
+
+// EstimateCost estimates the requests, the cost and the time of the sync planned
+// by SyncWithResult in dry-run mode, with the part sizes, the parallelism and
+// the bandwidth limit of the Manager.
+// Comparing the estimates of the Managers with different options helps to choose
+// the strategy of a big sync.
+// The time is estimated from the bandwidth and the latency of the requests
+// run in parallel, and the server-side copies are assumed to be limited only by the latency.
+func (m *Manager) EstimateCost(plan *SyncResult, a CostAssumptions) *CostEstimate {
+	e := &CostEstimate{}
+	var downloaded int64
+	for _, f := range plan.Uploaded {
+		e.PutRequests += partRequests(f.Size, m.partSize(m.uploadPartSize, s3manager.DefaultUploadPartSize))
+		e.TransferredBytes += f.Size
+	}
+	for _, f := range plan.Downloaded {
+		e.GetRequests += ceilDiv(f.Size, m.partSize(m.downloadPartSize, s3manager.DefaultDownloadPartSize))
+		downloaded += f.Size
+	}
+	e.TransferredBytes += downloaded
+	for _, f := range plan.Copied {
+		if needsMultipartCopy(f.Size) {
+			e.PutRequests += ceilDiv(f.Size, m.partSizeForCopy(f.Size)) + 2
+		} else {
+			e.PutRequests++
+		}
+	}
+	switch {
+	case !isS3Path(plan.Dest):
+		// The local files are deleted without requests.
+	case isS3Path(plan.Source):
+		e.DeleteRequests = int64(len(plan.Deleted))
+	default:
+		// Batched by DeleteObjects.
+		e.DeleteRequests = ceilDiv(int64(len(plan.Deleted)), maxDeleteObjects)
+	}
+	listRequests := ceilDiv(plan.Statistics.ListedObjects, listObjectsPageSize)
+	e.PutRequests += listRequests
+
+	e.RequestCost = float64(e.PutRequests)*a.PutRequestPrice + float64(e.GetRequests)*a.GetRequestPrice
+	e.TransferCost = float64(downloaded) / (1 << 30) * a.TransferOutPricePerGB
+
+	bandwidth := a.Bandwidth
+	if m.bandwidth != nil && (bandwidth <= 0 || int64(m.bandwidth.rate) < bandwidth) {
+		bandwidth = int64(m.bandwidth.rate)
+	}
+	var transfer time.Duration
+	if bandwidth > 0 {
+		transfer = time.Duration(float64(e.TransferredBytes) / float64(bandwidth) * float64(time.Second))
+	}
+	parallel := int64(m.nJobs)
+	if parallel < 1 {
+		parallel = 1
+	}
+	// The listing is sequential while the file operations run in parallel.
+	fileRequests := e.PutRequests - listRequests + e.GetRequests + e.DeleteRequests
+	latency := a.RequestLatency * time.Duration(listRequests+ceilDiv(fileRequests, parallel))
+	if transfer > latency {
+		e.Duration = transfer
+	} else {
+		e.Duration = latency
+	}
+	return e
+}
+
+// partSize returns the part size of the transfer manager.
+func (m *Manager) partSize(size, defaultSize int64) int64 {
+	if size > 0 {
+		return size
+	}
+	return defaultSize
+}
+
+// partRequests returns the number of the requests to upload the object of the size
+// by s3manager.Uploader, which uses the multipart upload if it exceeds the part size.
+func partRequests(size, partSize int64) int64 {
+	if size <= partSize {
+		return 1
+	}
+	if min := ceilDiv(size, s3manager.MaxUploadParts); partSize < min {
+		partSize = min
+	}
+	// CreateMultipartUpload, UploadPart and CompleteMultipartUpload
+	return ceilDiv(size, partSize) + 2
+}
+
+// ceilDiv returns n / d rounded up, and at least 1 if n is positive.
+func ceilDiv(n, d int64) int64 {
+	return (n + d - 1) / d
+}
+
+// isS3Path returns true if the path of the sync is the s3 url.
+func isS3Path(path string) bool {
+	u, err := parseURL(path)
+	return err == nil && isS3URL(u)
+}
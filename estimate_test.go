@@ -0,0 +1,143 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func TestEstimateCost(t *testing.T) {
+	const mib = 1024 * 1024
+	a := CostAssumptions{
+		PutRequestPrice:       0.01,
+		GetRequestPrice:       0.001,
+		TransferOutPricePerGB: 1,
+		Bandwidth:             mib,
+		RequestLatency:        time.Second,
+	}
+	testCases := map[string]struct {
+		options  []Option
+		plan     *SyncResult
+		expected CostEstimate
+	}{
+		"Upload": {
+			options: []Option{WithParallel(2), WithUploadPartSize(s3manager.MinUploadPartSize)},
+			plan: &SyncResult{
+				Source:     "local/dir",
+				Dest:       "s3://bucket/prefix",
+				Uploaded:   []FileResult{{Size: 1}, {Size: 12 * mib}},
+				Deleted:    []FileResult{{Size: 1}, {Size: 1}, {Size: 1}},
+				Statistics: SyncStatistics{ListedObjects: 2500},
+			},
+			expected: CostEstimate{
+				PutRequests:      1 + 5 + 3,
+				DeleteRequests:   1,
+				TransferredBytes: 12*mib + 1,
+				RequestCost:      0.09,
+				Duration:         12*time.Second + time.Second/mib,
+			},
+		},
+		"Download": {
+			options: []Option{WithParallel(2)},
+			plan: &SyncResult{
+				Source:     "s3://bucket/prefix",
+				Dest:       "local/dir",
+				Downloaded: []FileResult{{Size: 1024 * mib}},
+				Deleted:    []FileResult{{Size: 1}},
+				Statistics: SyncStatistics{ListedObjects: 1},
+			},
+			expected: CostEstimate{
+				PutRequests:      1,
+				GetRequests:      205,
+				TransferredBytes: 1024 * mib,
+				RequestCost:      0.215,
+				TransferCost:     1,
+				Duration:         1024 * time.Second,
+			},
+		},
+		"Copy": {
+			options: []Option{WithBandwidthLimit(1)},
+			plan: &SyncResult{
+				Source:  "s3://bucket/prefix",
+				Dest:    "s3://bucket2/prefix",
+				Copied:  []FileResult{{Size: 1}, {Size: 6 * 1024 * mib}},
+				Deleted: []FileResult{{Size: 1}, {Size: 1}},
+			},
+			expected: CostEstimate{
+				PutRequests:    1 + 24 + 2,
+				DeleteRequests: 2,
+				RequestCost:    0.27,
+				Duration:       2 * time.Second,
+			},
+		},
+		"BandwidthLimit": {
+			options: []Option{WithBandwidthLimit(mib / 2)},
+			plan: &SyncResult{
+				Source:   "local/dir",
+				Dest:     "s3://bucket/prefix",
+				Uploaded: []FileResult{{Size: mib}},
+			},
+			expected: CostEstimate{
+				PutRequests:      1,
+				TransferredBytes: mib,
+				RequestCost:      0.01,
+				Duration:         2 * time.Second,
+			},
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			e := New(session.New(), tc.options...).EstimateCost(tc.plan, a)
+			if math.Abs(e.RequestCost-tc.expected.RequestCost) > 1e-9 || math.Abs(e.TransferCost-tc.expected.TransferCost) > 1e-9 {
+				t.Errorf("Expected costs %v and %v, got %v and %v",
+					tc.expected.RequestCost, tc.expected.TransferCost, e.RequestCost, e.TransferCost)
+			}
+			e.RequestCost, e.TransferCost = tc.expected.RequestCost, tc.expected.TransferCost
+			if *e != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, *e)
+			}
+		})
+	}
+}
+
+func TestEstimateCost_DryRunPlan(t *testing.T) {
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b", "prefix/c"}}}
+	m := New(session.New(), WithDryRun())
+	m.s3 = s
+
+	plan, err := m.SyncWithResult(context.Background(), "s3://bucket/prefix", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.downloaded) != 0 {
+		t.Fatalf("Expected no downloads in dry-run mode, got %v", s.downloaded)
+	}
+	if len(plan.Downloaded) != 3 {
+		t.Fatalf("Expected 3 planned downloads, got %v", plan.Downloaded)
+	}
+	e := m.EstimateCost(plan, DefaultCostAssumptions)
+	if e.GetRequests != 3 || e.PutRequests != 1 || e.TransferredBytes != 3 {
+		t.Errorf("Unexpected estimate %+v", *e)
+	}
+	if e.TotalCost() <= 0 || e.Duration <= 0 {
+		t.Errorf("Expected positive cost and duration, got %+v", *e)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// eventsBufferSize is the buffer size of the events channel.
+const eventsBufferSize = 1024
+
+// SyncEventType is the type of the SyncEvent.
+type SyncEventType int
+
+const (
+	// FileQueued is emitted when the file operation is queued to the workers.
+	FileQueued SyncEventType = iota
+	// FileUploaded is emitted when the local file is uploaded.
+	FileUploaded
+	// FileDownloaded is emitted when the object is downloaded.
+	FileDownloaded
+	// FileCopied is emitted when the object is copied to the destination bucket.
+	FileCopied
+	// FileDeleted is emitted when the file or object is deleted from the destination.
+	FileDeleted
+	// FileSkipped is emitted when the file is skipped as up-to-date.
+	FileSkipped
+	// FileFailed is emitted when the file operation is failed.
+	FileFailed
+	// FileCompared is emitted when the source file is compared with the destination
+	// by the hash workers of WithHashWorkers. Duration is the time taken by the comparison.
+	FileCompared
+	// RuntimeSampled is emitted periodically by WithRuntimeStats with Runtime.
+	RuntimeSampled
+)
+
+func (t SyncEventType) String() string {
+	switch t {
+	case FileQueued:
+		return "FileQueued"
+	case FileUploaded:
+		return "FileUploaded"
+	case FileDownloaded:
+		return "FileDownloaded"
+	case FileCopied:
+		return "FileCopied"
+	case FileDeleted:
+		return "FileDeleted"
+	case FileSkipped:
+		return "FileSkipped"
+	case FileFailed:
+		return "FileFailed"
+	case FileCompared:
+		return "FileCompared"
+	case RuntimeSampled:
+		return "RuntimeSampled"
+	}
+	return "SyncEventType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// SyncEvent is the event of the sync operation.
+type SyncEvent struct {
+	Type SyncEventType
+	// Path is the file path relative to the sync root.
+	Path string
+	Size int64
+	// Duration is the time taken by the file operation.
+	Duration time.Duration
+	Err      error
+	Time     time.Time
+	// Runtime is the runtime stats of RuntimeSampled events.
+	Runtime *RuntimeStats
+}
+
+// Events returns the channel which receives the events of the sync operations.
+// Events are emitted only after the first call of Events,
+// and the sync blocks until the events are received once the buffer is full.
+// The channel is never closed since the Manager can be reused.
+func (m *Manager) Events() <-chan SyncEvent {
+	m.eventsMu.Lock()
+	defer m.eventsMu.Unlock()
+	if m.events == nil {
+		m.events = make(chan SyncEvent, eventsBufferSize)
+	}
+	return m.events
+}
+
+func (m *Manager) emit(ctx context.Context, ev SyncEvent) {
+	switch ev.Type {
+	case FileSkipped:
+		m.updateStatistics(ctx, func(s *SyncStatistics) {
+			s.SkippedFiles++
+		})
+	case FileFailed:
+		m.updateStatistics(ctx, func(s *SyncStatistics) {
+			s.FailedFiles++
+		})
+	}
+	if c, ok := ctx.Value(resultCollectorKey{}).(*resultCollector); ok {
+		c.add(ev)
+	}
+	m.eventsMu.Lock()
+	ch := m.events
+	m.eventsMu.Unlock()
+	if ch == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	select {
+	case ch <- ev:
+	case <-ctx.Done():
+	}
+}
+
+// queued emits FileQueued, or FileFailed if the file has an error.
+func (m *Manager) queued(ctx context.Context, file *fileOp) {
+	if file.err != nil {
+		m.emit(ctx, SyncEvent{Type: FileFailed, Path: file.name, Err: file.err})
+		return
+	}
+	m.emit(ctx, SyncEvent{Type: FileQueued, Path: file.name, Size: file.size})
+}
+
+// emitDone emits the event of the finished file operation.
+// Nothing is emitted if the failed operation will be retried.
+// It is intended to be deferred.
+func (m *Manager) emitDone(ctx context.Context, typ SyncEventType, file *fileInfo, start time.Time, err *error) {
+	if m.willRetry(ctx, *err) {
+		return
+	}
+	ev := SyncEvent{
+		Type:     typ,
+		Path:     file.name,
+		Size:     file.size,
+		Duration: time.Since(start),
+	}
+	if *err != nil {
+		ev.Type = FileFailed
+		ev.Err = *err
+	} else {
+		m.itemize(typ, file)
+	}
+	m.emit(ctx, ev)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestEvents(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	defer os.RemoveAll(temp)
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	if err := ioutil.WriteFile(filepath.Join(temp, "foo"), []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	m := New(session.New())
+
+	// Events are not emitted before Events is called.
+	m.skipped(ctx)(&fileInfo{name: "bar", size: 3})
+
+	events := m.Events()
+	if len(events) != 0 {
+		t.Fatalf("Unexpected events: %d", len(events))
+	}
+
+	m.queued(ctx, &fileOp{fileInfo: &fileInfo{name: "foo", size: 3}})
+	m.skipped(ctx)(&fileInfo{name: "bar", size: 3})
+	if err := m.deleteLocal(ctx, &fileInfo{name: "foo", size: 3}, temp); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.deleteLocal(ctx, &fileInfo{name: "foo", size: 3}, temp); err == nil {
+		t.Fatal("Expected error on deleting non-existent file")
+	}
+
+	expected := []struct {
+		typ  SyncEventType
+		path string
+		err  bool
+	}{
+		{FileQueued, "foo", false},
+		{FileSkipped, "bar", false},
+		{FileDeleted, "foo", false},
+		{FileFailed, "foo", true},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for _, e := range expected {
+		ev := <-events
+		if ev.Type != e.typ || ev.Path != e.path || (ev.Err != nil) != e.err {
+			t.Errorf("Expected %v %s (error: %v), got %v %s (%v)", e.typ, e.path, e.err, ev.Type, ev.Path, ev.Err)
+		}
+		if ev.Time.IsZero() {
+			t.Error("Event time must be set")
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// filterRule is an include or exclude filter of the file names.
+type filterRule struct {
+	include bool
+	pattern *regexp.Regexp
+	// prefix is the pattern without the trailing "*", or nil if the glob doesn't end with "*".
+	// Any path under the directory matching the prefix matches the pattern.
+	prefix *regexp.Regexp
+}
+
+// newFilterRule returns the filter rule of the glob pattern.
+func newFilterRule(include bool, glob string) filterRule {
+	f := filterRule{include: include, pattern: globToRegexp(glob)}
+	if strings.HasSuffix(glob, "*") {
+		f.prefix = globToRegexp(strings.TrimRight(glob, "*"))
+	}
+	return f
+}
+
+// globToRegexp converts the glob pattern to the regexp compatible with aws-cli.
+// "*" and "**" match any sequence of characters including "/",
+// "?" matches any single character and "[...]" matches a character in the set.
+func globToRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+			for i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+			}
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end <= 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			set := glob[i+1 : i+1+end]
+			if strings.HasPrefix(set, "!") {
+				set = "^" + set[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(set, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// included returns whether the file name passes the include and exclude filters.
+// Filters are evaluated in order and the last matching one wins.
+// Files not matching any filter are included.
+// The manifest object and the files out of the key range are always excluded.
+func (m *Manager) included(name string) bool {
+	name = filepath.ToSlash(name)
+	if !m.inScope(name) {
+		return false
+	}
+	ret := true
+	for _, f := range m.filters {
+		if f.pattern.MatchString(name) {
+			ret = f.include
+		}
+	}
+	return ret
+}
+
+// inScope returns whether the file name is not the manifest object and in the key range.
+func (m *Manager) inScope(name string) bool {
+	name = filepath.ToSlash(name)
+	if m.manifestName != "" && name == m.manifestName {
+		return false
+	}
+	return m.inKeyRange(name)
+}
+
+// excludedDir returns whether all of the files under the directory are excluded
+// by the filters, i.e. an exclude filter matches all paths under the directory
+// and no include filter follows it.
+func (m *Manager) excludedDir(name string) bool {
+	name = filepath.ToSlash(name)
+	for i := len(m.filters) - 1; i >= 0; i-- {
+		f := m.filters[i]
+		if f.include {
+			return false
+		}
+		if f.prefix != nil && (f.prefix.MatchString(name) || f.prefix.MatchString(name+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneWalk returns the walk function which skips walking into the directories
+// excluded by the filters.
+func (m *Manager) pruneWalk(walk func(string, filepath.WalkFunc) error) func(string, filepath.WalkFunc) error {
+	if len(m.filters) == 0 {
+		return walk
+	}
+	return func(root string, fn filepath.WalkFunc) error {
+		return walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				if rel, err := filepath.Rel(root, path); err == nil && rel != "." && m.excludedDir(rel) {
+					return filepath.SkipDir
+				}
+			}
+			return fn(path, info, err)
+		})
+	}
+}
+
+// applyFilters returns a channel which receives the given file infos
+// passing the include and exclude filters.
+// It is applied to both of the source and destination listings so that
+// the excluded destination files are not deleted.
+// The nil channel of the destination known empty is returned as is.
+func (m *Manager) applyFilters(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	if files == nil || len(m.filters) == 0 && m.manifestName == "" && !m.hasKeyRange() {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			name := fi.name
+			if fi.originalName != "" {
+				// Destination object whose key is encoded by the NameCodec.
+				name = fi.originalName
+			}
+			if fi.err == nil && !m.included(name) {
+				continue
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// markNotReady returns a channel which receives the given source file infos
+// with the files not ready to be synced, already processed by the previous run
+// of SyncWithTimeBudget, or modified out of the time range of WithModifiedAfter
+// and WithModifiedBefore, marked as postponed.
+// Postponed files are not synced in this run but still prevent the deletion
+// of the corresponding destination files.
+func (m *Manager) markNotReady(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	budget := timeBudgetFrom(ctx)
+	if m.skipRecent <= 0 && budget == nil && m.modifiedAfter.IsZero() && m.modifiedBefore.IsZero() {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		now := time.Now()
+		for fi := range files {
+			switch {
+			case fi.err != nil:
+			case budget != nil && budget.done(fi.name):
+				fi.postponed = true
+			case now.Sub(fi.lastModified) < m.skipRecent:
+				println("Skipping recently modified", fi.name)
+				fi.postponed = true
+			case !m.modifiedAfter.IsZero() && !fi.lastModified.After(m.modifiedAfter),
+				!m.modifiedBefore.IsZero() && !fi.lastModified.Before(m.modifiedBefore):
+				fi.postponed = true
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// waitStable waits for the stability check delay and returns whether
+// the local file is unchanged from the listing.
+// It returns true if the stability check is disabled.
+func (m *Manager) waitStable(ctx context.Context, file *fileInfo, filename string) (bool, error) {
+	if m.stabilityDelay <= 0 || !file.local || file.symlink != "" {
+		return true, nil
+	}
+	t := time.NewTimer(m.stabilityDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return false, err
+	}
+	return stat.Size() == file.size && stat.ModTime().Equal(file.lastModified), nil
+}
+
+// applyDestFilters returns a channel which receives the given destination file infos
+// passing the filters like applyFilters.
+// If WithDeleteExcluded is set, the files excluded by the filters are passed
+// to be deleted, but the manifest object and the files out of the key range are not.
+func (m *Manager) applyDestFilters(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	if !m.deleteExcluded {
+		return m.applyFilters(ctx, files)
+	}
+	if files == nil || m.manifestName == "" && !m.hasKeyRange() {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil && !m.inScope(fi.name) {
+				continue
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// filterSizes returns a channel which receives the given file infos
+// of the sizes between WithMinSize and WithMaxSize.
+// It is applied to both of the source and destination files so that
+// the destination files out of the sizes are not deleted.
+func (m *Manager) filterSizes(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	if files == nil || m.minSize == 0 && m.maxSize == 0 {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil && (fi.size < m.minSize || m.maxSize > 0 && fi.size > m.maxSize) {
+				continue
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
@@ -0,0 +1,366 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func listFileInfos(files ...*fileInfo) chan *fileInfo {
+	c := make(chan *fileInfo, len(files))
+	for _, f := range files {
+		c <- f
+	}
+	close(c)
+	return c
+}
+
+func TestSkipRecentlyModified(t *testing.T) {
+	now := time.Now()
+	m := &Manager{}
+	WithSkipRecentlyModified(time.Minute)(m)
+
+	source := m.markNotReady(context.Background(), listFileInfos(
+		&fileInfo{name: "old", size: 1, lastModified: now.Add(-time.Hour)},
+		&fileInfo{name: "recent", size: 1, lastModified: now.Add(-time.Second)},
+		&fileInfo{name: "recent-new", size: 1, lastModified: now.Add(-time.Second)},
+	))
+	dest := listFileInfos(
+		&fileInfo{name: "old", size: 2, lastModified: now.Add(-time.Hour)},
+		&fileInfo{name: "recent", size: 2, lastModified: now.Add(-time.Hour)},
+	)
+
+	var skipped []string
+	ops := make(map[string]operation)
+	for op := range filterFilesForSync(source, dest, true, SizeOnlyComparator, func(f *fileInfo) {
+		skipped = append(skipped, f.name)
+	}) {
+		ops[op.name] = op.op
+	}
+	if len(ops) != 1 || ops["old"] != opUpdate {
+		t.Errorf("Unexpected operations %v", ops)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("Recently modified files must be skipped, skipped: %v", skipped)
+	}
+}
+
+func TestModifiedTimeRange(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		options []Option
+		ops     []string
+	}{
+		"After":  {[]Option{WithModifiedAfter(base)}, []string{"new", "newer"}},
+		"Before": {[]Option{WithModifiedBefore(base)}, []string{"old"}},
+		"Both":   {[]Option{WithModifiedAfter(base.Add(-time.Hour)), WithModifiedBefore(base.Add(time.Hour))}, []string{"edge", "new"}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := &Manager{}
+			for _, o := range tc.options {
+				o(m)
+			}
+			source := m.markNotReady(context.Background(), listFileInfos(
+				&fileInfo{name: "old", size: 1, lastModified: base.Add(-24 * time.Hour)},
+				&fileInfo{name: "edge", size: 1, lastModified: base},
+				&fileInfo{name: "new", size: 1, lastModified: base.Add(time.Second)},
+				&fileInfo{name: "newer", size: 1, lastModified: base.Add(24 * time.Hour)},
+			))
+			dest := listFileInfos(
+				&fileInfo{name: "old", size: 2},
+				&fileInfo{name: "edge", size: 2},
+			)
+
+			var ops []string
+			for op := range filterFilesForSync(source, dest, true, SizeOnlyComparator, nil) {
+				if op.op != opUpdate {
+					t.Errorf("Destination of the skipped file must not be deleted: %s", op.name)
+				}
+				ops = append(ops, op.name)
+			}
+			sort.Strings(ops)
+			if !reflect.DeepEqual(tc.ops, ops) {
+				t.Errorf("Expected %v, got %v", tc.ops, ops)
+			}
+		})
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	testCases := []struct {
+		glob    string
+		name    string
+		matched bool
+	}{
+		{"*", "foo/bar.txt", true},
+		{"*.txt", "bar.txt", true},
+		{"*.txt", "foo/bar.txt", true},
+		{"*.txt", "bar.txt.gz", false},
+		{"foo/*", "foo/bar/baz.txt", true},
+		{"foo/**", "foo/bar/baz.txt", true},
+		{"foo/*", "foobar/baz.txt", false},
+		{"ba?.txt", "bar.txt", true},
+		{"ba?.txt", "ba/.txt", true},
+		{"ba?.txt", "baar.txt", false},
+		{"ba[rz].txt", "baz.txt", true},
+		{"ba[!rz].txt", "baz.txt", false},
+		{"ba[!rz].txt", "bax.txt", true},
+		{"a+b(c).txt", "a+b(c).txt", true},
+		{"[unclosed", "[unclosed", true},
+		{"[]", "[]", true},
+	}
+	for _, tt := range testCases {
+		if matched := globToRegexp(tt.glob).MatchString(tt.name); matched != tt.matched {
+			t.Errorf("%s matching %s is expected to be %v, got %v", tt.glob, tt.name, tt.matched, matched)
+		}
+	}
+}
+
+func TestIncludeExclude(t *testing.T) {
+	m := &Manager{}
+	WithExclude("*")(m)
+	WithInclude("*.txt")(m)
+	WithExclude("tmp/*")(m)
+
+	files := m.applyFilters(context.Background(), listFileInfos(
+		&fileInfo{name: "foo.txt"},
+		&fileInfo{name: "foo.jpg"},
+		&fileInfo{name: "dir/bar.txt"},
+		&fileInfo{name: "tmp/baz.txt"},
+	))
+	var names []string
+	for fi := range files {
+		names = append(names, fi.name)
+	}
+	if len(names) != 2 || names[0] != "foo.txt" || names[1] != "dir/bar.txt" {
+		t.Errorf("Unexpected files: %v", names)
+	}
+}
+
+func TestSizeFilters(t *testing.T) {
+	testCases := map[string]struct {
+		options []Option
+		ops     []string
+	}{
+		"None":    {nil, []string{"delete big", "delete small", "update a", "update b", "update c"}},
+		"MinSize": {[]Option{WithMinSize(10)}, []string{"delete big", "update b", "update c"}},
+		"MaxSize": {[]Option{WithMaxSize(10)}, []string{"delete small", "update a", "update b"}},
+		"Both":    {[]Option{WithMinSize(10), WithMaxSize(10)}, []string{"update b"}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := &Manager{}
+			for _, o := range append(tc.options, WithDelete()) {
+				o(m)
+			}
+			source := listFileInfos(
+				&fileInfo{name: "a", size: 1},
+				&fileInfo{name: "b", size: 10},
+				&fileInfo{name: "c", size: 100},
+			)
+			dest := listFileInfos(
+				&fileInfo{name: "small", size: 1},
+				&fileInfo{name: "big", size: 100},
+			)
+			var ops []string
+			for op := range m.filterFiles(context.Background(), source, dest) {
+				if op.op == opDelete {
+					ops = append(ops, "delete "+op.name)
+				} else {
+					ops = append(ops, "update "+op.name)
+				}
+			}
+			sort.Strings(ops)
+			if !reflect.DeepEqual(tc.ops, ops) {
+				t.Errorf("Expected %v, got %v", tc.ops, ops)
+			}
+		})
+	}
+}
+
+func TestExcludedDir(t *testing.T) {
+	testCases := map[string]struct {
+		opts     []Option
+		expected map[string]bool
+	}{
+		"Prefix": {
+			[]Option{WithExclude("node_modules/*")},
+			map[string]bool{"node_modules": true, "src": false, "src/node_modules": false},
+		},
+		"AnyDepth": {
+			[]Option{WithExclude("*/node_modules/*", "*.git*")},
+			map[string]bool{"node_modules": false, "src/node_modules": true, "a/b/node_modules": true, ".git": true, "src": false},
+		},
+		"IncludedAfter": {
+			[]Option{WithExclude("node_modules/*"), WithInclude("*.txt")},
+			map[string]bool{"node_modules": false},
+		},
+		"IncludedBefore": {
+			[]Option{WithInclude("*.txt"), WithExclude("node_modules/*")},
+			map[string]bool{"node_modules": true},
+		},
+		"NotPrefix": {
+			[]Option{WithExclude("node_modules/*.txt")},
+			map[string]bool{"node_modules": false},
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m := &Manager{}
+			for _, o := range tt.opts {
+				o(m)
+			}
+			for dir, expected := range tt.expected {
+				if ret := m.excludedDir(dir); ret != expected {
+					t.Errorf("%s: expected %v, got %v", dir, expected, ret)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneWalk(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	for _, name := range []string{"src/a.js", "node_modules/pkg/index.js", "src/node_modules/b.js"} {
+		filename := filepath.Join(temp, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte("foo"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &Manager{}
+	WithExclude("node_modules/*")(m)
+	var visited []string
+	err = m.pruneWalk(filepath.Walk)(temp, func(path string, info os.FileInfo, err error) error {
+		rel, _ := filepath.Rel(temp, path)
+		visited = append(visited, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{".", "src", "src/a.js", "src/node_modules", "src/node_modules/b.js"}
+	if !reflect.DeepEqual(expected, visited) {
+		t.Errorf("Expected %v, got %v", expected, visited)
+	}
+}
+
+func TestWaitStable(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	defer os.RemoveAll(temp)
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	filename := filepath.Join(temp, "foo")
+	if err := ioutil.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := &fileInfo{name: "foo", size: stat.Size(), lastModified: stat.ModTime(), local: true}
+
+	m := &Manager{}
+	WithStabilityCheck(50 * time.Millisecond)(m)
+
+	if stable, err := m.waitStable(context.Background(), file, filename); err != nil || !stable {
+		t.Errorf("Unchanged file must be stable: %v, %v", stable, err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		f.Write([]byte("bar"))
+	}()
+	if stable, err := m.waitStable(context.Background(), file, filename); err != nil || stable {
+		t.Errorf("Appended file must be unstable: %v, %v", stable, err)
+	}
+}
+
+func TestDeleteExcluded(t *testing.T) {
+	testCases := map[string]struct {
+		options []Option
+		remains []string
+	}{
+		"Delete":         {[]Option{WithDelete()}, []string{".hidden", "a", "b.log", "dir/c.log"}},
+		"DeleteExcluded": {[]Option{WithDeleteExcluded()}, []string{"a"}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+			for _, name := range []string{".hidden", "a", "b.log", "dir/c.log", "stale"} {
+				filename := filepath.Join(temp, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(filename, []byte("a"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b.log"}}}
+			m := New(session.New(), append(tc.options, WithExclude("*.log"), WithExclude("dir/*"), WithIgnoreHidden())...)
+			m.s3 = s
+			if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+				t.Fatal(err)
+			}
+
+			var remains []string
+			if err := filepath.Walk(temp, func(path string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					rel, _ := filepath.Rel(temp, path)
+					remains = append(remains, filepath.ToSlash(rel))
+				}
+				return err
+			}); err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(remains)
+			if !reflect.DeepEqual(tc.remains, remains) {
+				t.Errorf("Expected %v to remain, got %v", tc.remains, remains)
+			}
+		})
+	}
+}
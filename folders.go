@@ -0,0 +1,214 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// The folders created by the S3 console are the empty objects with the keys ending
+// with "/". They are not synced as files, but as the directories: the folders in
+// the source bucket are created as the local directories, and the empty local
+// directories are created as the folders by WithPreserveEmptyDirs.
+
+type folderSetKey struct{}
+
+// folderSet is the set of the names of the folder objects listed by a sync call.
+type folderSet struct {
+	mu     sync.Mutex
+	source map[string]bool
+	dest   map[string]bool
+}
+
+// withFolders returns the context recording the folder objects listed by sendS3Objects.
+func withFolders(ctx context.Context) (context.Context, *folderSet) {
+	folders := &folderSet{source: make(map[string]bool), dest: make(map[string]bool)}
+	return context.WithValue(ctx, folderSetKey{}, folders), folders
+}
+
+// addFolder records the folder object of the name listed from the path.
+func addFolder(ctx context.Context, path *s3Path, name string) {
+	folders, ok := ctx.Value(folderSetKey{}).(*folderSet)
+	name = strings.TrimSuffix(filepath.ToSlash(name), "/")
+	if !ok || name == "." || name == "" {
+		return
+	}
+	folders.mu.Lock()
+	defer folders.mu.Unlock()
+	if path.source {
+		folders.source[name] = true
+	} else {
+		folders.dest[name] = true
+	}
+}
+
+// names returns the sorted names in the set.
+func (f *folderSet) names(set map[string]bool) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// createLocalFolders creates the folders of the source bucket as the local directories.
+// The folder conflicting with a local file is skipped.
+func (m *Manager) createLocalFolders(ctx context.Context, folders *folderSet, destPath string) error {
+	errs := newSyncErrors(ctx)
+	for _, name := range folders.names(folders.source) {
+		dir, ok := m.localFilename(destPath, filepath.FromSlash(name))
+		if !ok {
+			println("Skipping folder", name, "which can't be mapped to a local path")
+			continue
+		}
+		if stat, err := os.Stat(dir); err == nil {
+			if !stat.IsDir() {
+				println("Skipping folder", name, "conflicting with the file", dir)
+			}
+			continue
+		}
+		logOp(ctx, opAttrs(ctx, "mkdir", "", name, 0), "Creating directory", dir)
+		if m.isDryRun(ctx) {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			errs.Append(&FileError{Op: "mkdir", Path: dir, Err: err})
+		}
+	}
+	return errs.ErrOrNil()
+}
+
+// syncRemoteFolders creates the folder objects of the empty local directories,
+// and deletes the folder objects of the removed directories if WithDelete is set.
+func (m *Manager) syncRemoteFolders(ctx context.Context, folders *folderSet, sourcePath string, destPath *s3Path) error {
+	dirs, err := m.emptyLocalDirs(sourcePath)
+	if err != nil {
+		return err
+	}
+	errs := newSyncErrors(ctx)
+	exists := folders.names(folders.dest)
+	for _, name := range dirs {
+		if i := sort.SearchStrings(exists, name); i < len(exists) && exists[i] == name {
+			continue
+		}
+		if err := m.putFolder(ctx, destPath, name); err != nil {
+			errs.Append(err)
+		}
+	}
+	if !m.del {
+		return errs.ErrOrNil()
+	}
+	for _, name := range exists {
+		dir, ok := m.localFilename(sourcePath, filepath.FromSlash(name))
+		if !ok {
+			continue
+		}
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			continue
+		}
+		if err := m.deleteFolder(ctx, destPath, name); err != nil {
+			errs.Append(err)
+		}
+	}
+	return errs.ErrOrNil()
+}
+
+// putFolder puts the folder object of the name.
+func (m *Manager) putFolder(ctx context.Context, destPath *s3Path, name string) (err error) {
+	key := objectKey(destPath.bucketPrefix, name) + "/"
+	defer wrapFileError(&err, "mkdir", "", key)
+	if err := m.refuseIfReadOnly("creating", destPath.bucket+"/"+key); err != nil {
+		return err
+	}
+	logOp(ctx, opAttrs(ctx, "mkdir", destPath.bucket, key, 0), "Creating folder", key, "in bucket", destPath.bucket)
+	if m.isDryRun(ctx) {
+		return nil
+	}
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
+	_, err = m.destClient(ctx, destPath.bucket).PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(destPath.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(nil),
+		ACL:                  m.acl,
+		ServerSideEncryption: m.sse,
+		SSEKMSKeyId:          m.sseKMSKeyID,
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+		StorageClass:         m.storageClass,
+	}, m.putRateOption(destPath.bucket, key))
+	return err
+}
+
+// deleteFolder deletes the folder object of the name.
+func (m *Manager) deleteFolder(ctx context.Context, destPath *s3Path, name string) (err error) {
+	key := objectKey(destPath.bucketPrefix, name) + "/"
+	defer wrapFileError(&err, "delete", "", key)
+	if err := m.refuseIfReadOnly("deleting", destPath.bucket+"/"+key); err != nil {
+		return err
+	}
+	logOp(ctx, opAttrs(ctx, "delete", destPath.bucket, key, 0), "Deleting folder", key, "in bucket", destPath.bucket)
+	if m.isDryRun(ctx) {
+		return nil
+	}
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
+	_, err = m.destClient(ctx, destPath.bucket).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket:                    aws.String(destPath.bucket),
+		Key:                       aws.String(key),
+		BypassGovernanceRetention: m.bypassGovernance,
+	}, m.putRateOption(destPath.bucket, key))
+	return err
+}
+
+// emptyLocalDirs returns the slash separated names of the empty directories under the root.
+func (m *Manager) emptyLocalDirs(root string) ([]string, error) {
+	if stat, err := os.Stat(root); err != nil || !stat.IsDir() {
+		return nil, nil
+	}
+	var dirs []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() || path == root {
+			return err
+		}
+		entries, err := ioutil.ReadDir(path)
+		if err != nil || len(entries) > 0 {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if m.oddKeyPolicy == OddKeyEscape {
+			name = unescapeLocalName(name)
+		}
+		dirs = append(dirs, name)
+		return nil
+	})
+	return dirs, err
+}
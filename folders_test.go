@@ -0,0 +1,143 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyFolderS3 struct {
+	dummyBudgetS3
+	put     []string
+	deleted []string
+}
+
+func (s *dummyFolderS3) PutObjectWithContext(ctx aws.Context, in *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put = append(s.put, aws.StringValue(in.Key))
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (s *dummyFolderS3) DeleteObjectWithContext(ctx aws.Context, in *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, aws.StringValue(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestFolders_Download(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	if err := ioutil.WriteFile(filepath.Join(temp, "conflict"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &dummyFolderS3{dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+		"prefix/", "prefix/a", "prefix/conflict/", "prefix/dir/", "prefix/dir/b", "prefix/empty/", "prefix/empty/nested/",
+	}}}}
+	m := New(session.New())
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(s.downloaded)
+	if expected := []string{"prefix/a", "prefix/dir/b"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected %v to be downloaded, got %v", expected, s.downloaded)
+	}
+	for name, dir := range map[string]bool{
+		"a":            false,
+		"conflict":     false,
+		"dir":          true,
+		"dir/b":        false,
+		"empty":        true,
+		"empty/nested": true,
+	} {
+		stat, err := os.Stat(filepath.Join(temp, name))
+		if err != nil {
+			t.Errorf("Expected %s to exist: %v", name, err)
+			continue
+		}
+		if stat.IsDir() != dir {
+			t.Errorf("Expected %s to be a directory: %v", name, dir)
+		}
+	}
+}
+
+func TestFolders_Upload(t *testing.T) {
+	testCases := map[string]struct {
+		options []Option
+		put     []string
+		deleted []string
+	}{
+		"Default": {},
+		"Preserve": {
+			options: []Option{WithPreserveEmptyDirs()},
+			put:     []string{"prefix/empty/"},
+		},
+		"Delete": {
+			options: []Option{WithPreserveEmptyDirs(), WithDelete()},
+			put:     []string{"prefix/empty/"},
+			deleted: []string{"prefix/stale/"},
+		},
+		"DryRun": {
+			options: []Option{WithPreserveEmptyDirs(), WithDelete(), WithDryRun()},
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+			for _, dir := range []string{"empty", "nested/deep", "parent/child"} {
+				if err := os.MkdirAll(filepath.Join(temp, dir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := &dummyFolderS3{dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+				"prefix/nested/deep/", "prefix/parent/", "prefix/parent/child/", "prefix/stale/",
+			}}}}
+			m := New(session.New(), tc.options...)
+			m.s3 = s
+			if err := m.Sync(context.Background(), temp, "s3://bucket/prefix"); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.put, s.put) {
+				t.Errorf("Expected %v to be put, got %v", tc.put, s.put)
+			}
+			if !reflect.DeepEqual(tc.deleted, s.deleted) {
+				t.Errorf("Expected %v to be deleted, got %v", tc.deleted, s.deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// GlacierPolicy is the policy of the archived objects in GLACIER or DEEP_ARCHIVE
+// storage class, which can't be downloaded until restored.
+// The objects already restored are downloaded regardless of the policy.
+type GlacierPolicy struct {
+	skip    bool
+	restore bool
+	tier    string
+	days    int64
+}
+
+var (
+	// GlacierFail fails the download of the archived object by ErrArchived
+	// without requesting it. This is the default.
+	GlacierFail = GlacierPolicy{}
+	// GlacierSkip skips the archived objects with a warning.
+	GlacierSkip = GlacierPolicy{skip: true}
+)
+
+// GlacierRestoreAndWait returns the policy restoring the archived objects by RestoreObject
+// with the retrieval tier, e.g. s3.TierBulk, and the days to keep the restored copies.
+// The objects are downloaded after the other files are synced, when their restorations
+// complete. The sync waits for the restorations, which may take hours.
+func GlacierRestoreAndWait(tier string, days int64) GlacierPolicy {
+	return GlacierPolicy{restore: true, tier: tier, days: days}
+}
+
+// ErrArchived is returned by the download of the archived object with GlacierFail.
+var ErrArchived = errors.New("object is archived and not restored")
+
+// restorePollInterval is the interval to check the restorations of the archived objects.
+var restorePollInterval = time.Minute
+
+// isArchived returns whether the objects in the storage class must be restored to be downloaded.
+func isArchived(storageClass string) bool {
+	return storageClass == s3.ObjectStorageClassGlacier || storageClass == s3.ObjectStorageClassDeepArchive
+}
+
+// archiveState is the state of the archived object to be downloaded.
+type archiveState int
+
+const (
+	archiveReady archiveState = iota
+	archiveSkipped
+	archiveRestoring
+)
+
+// checkArchived returns whether the object can be downloaded now, or skipped or being
+// restored by the policy. The restoration is requested unless it is in progress.
+func (m *Manager) checkArchived(ctx context.Context, file *fileInfo, sourcePath *s3Path) (state archiveState, err error) {
+	if !isArchived(file.storageClass) {
+		return archiveReady, nil
+	}
+	key := sourceObjectKey(file, sourcePath)
+	defer wrapFileError(&err, "download", "", key)
+
+	restored, ongoing, err := m.restoreState(ctx, sourcePath.bucket, key)
+	switch {
+	case err != nil:
+		return archiveReady, err
+	case restored:
+		return archiveReady, nil
+	case m.glacierPolicy.skip:
+		println("Skipping", file.name, "archived in", file.storageClass)
+		return archiveSkipped, nil
+	case !m.glacierPolicy.restore:
+		return archiveReady, fmt.Errorf("%w: %s", ErrArchived, file.storageClass)
+	case ongoing:
+		return archiveRestoring, nil
+	}
+	logOp(ctx, opAttrs(ctx, "restore", sourcePath.bucket, key, file.size), "Restoring", file.name, "archived in", file.storageClass)
+	_, err = m.regionalClient(ctx, m.sourceClient(), sourcePath.bucket).RestoreObjectWithContext(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String(sourcePath.bucket),
+		Key:    aws.String(key),
+		RestoreRequest: &s3.RestoreRequest{
+			Days:                 aws.Int64(m.glacierPolicy.days),
+			GlacierJobParameters: &s3.GlacierJobParameters{Tier: aws.String(m.glacierPolicy.tier)},
+		},
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "RestoreAlreadyInProgress" {
+		err = nil
+	}
+	if err != nil {
+		return archiveReady, err
+	}
+	return archiveRestoring, nil
+}
+
+// restoreState returns whether the archived object is restored, or its restoration is in progress.
+func (m *Manager) restoreState(ctx context.Context, bucket, key string) (restored, ongoing bool, err error) {
+	head, err := m.regionalClient(ctx, m.sourceClient(), bucket).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return false, false, err
+	}
+	// e.g. ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"
+	restore := aws.StringValue(head.Restore)
+	return strings.Contains(restore, `ongoing-request="false"`), strings.Contains(restore, `ongoing-request="true"`), nil
+}
+
+// restoringFiles is the list of the archived files waiting for the restorations.
+type restoringFiles struct {
+	mu    sync.Mutex
+	files []*fileOp
+}
+
+func (r *restoringFiles) add(file *fileOp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files = append(r.files, file)
+}
+
+// waitRestored polls the restorations of the archived files, and dispatches each file
+// when its restoration completes. It returns when all of them are dispatched.
+func (m *Manager) waitRestored(ctx context.Context, r *restoringFiles, sourcePath *s3Path, dispatch func(*fileOp), errs *multiErr) {
+	r.mu.Lock()
+	pending := r.files
+	r.files = nil
+	r.mu.Unlock()
+	for len(pending) > 0 {
+		println("Waiting for", len(pending), "archived objects to be restored")
+		select {
+		case <-time.After(restorePollInterval):
+		case <-ctx.Done():
+			errs.Append(ctx.Err())
+			return
+		}
+		var next []*fileOp
+		for _, file := range pending {
+			restored, _, err := m.restoreState(ctx, sourcePath.bucket, sourceObjectKey(file.fileInfo, sourcePath))
+			switch {
+			case err != nil:
+				errs.Append(&FileError{Op: "download", Key: sourceObjectKey(file.fileInfo, sourcePath), Err: err})
+			case restored:
+				file.restored = true
+				dispatch(file)
+			default:
+				next = append(next, file)
+			}
+		}
+		pending = next
+	}
+}
+
+// sourceObjectKey returns the key of the source object of the file.
+func sourceObjectKey(file *fileInfo, sourcePath *s3Path) string {
+	if file.key != "" {
+		return file.key
+	}
+	if file.singleFile {
+		return file.name
+	}
+	return objectKey(sourcePath.bucketPrefix, file.name)
+}
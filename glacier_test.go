@@ -0,0 +1,161 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyGlacierS3 struct {
+	dummyBudgetS3
+	archived map[string]bool
+	// restored is the map of the keys to the number of HeadObject calls until the restoration completes.
+	restored map[string]int
+	requests []*s3.RestoreObjectInput
+}
+
+func (s *dummyGlacierS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	out, err := s.dummyBudgetS3.ListObjectsV2WithContext(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range out.Contents {
+		if s.archived[*o.Key] {
+			o.StorageClass = aws.String(s3.ObjectStorageClassGlacier)
+		}
+	}
+	return out, nil
+}
+
+func (s *dummyGlacierS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := &s3.HeadObjectOutput{StorageClass: aws.String(s3.StorageClassGlacier)}
+	n, ok := s.restored[*in.Key]
+	switch {
+	case !ok:
+	case n > 0:
+		s.restored[*in.Key] = n - 1
+		out.Restore = aws.String(`ongoing-request="true"`)
+	default:
+		out.Restore = aws.String(`ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`)
+	}
+	return out, nil
+}
+
+func (s *dummyGlacierS3) RestoreObjectWithContext(ctx aws.Context, in *s3.RestoreObjectInput, opts ...request.Option) (*s3.RestoreObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, in)
+	s.restored[*in.Key] = 2
+	return &s3.RestoreObjectOutput{}, nil
+}
+
+func TestGlacierPolicy(t *testing.T) {
+	defer func(d time.Duration) { restorePollInterval = d }(restorePollInterval)
+	restorePollInterval = time.Millisecond
+
+	testCases := map[string]struct {
+		policy     GlacierPolicy
+		restored   map[string]int
+		err        error
+		downloaded []string
+		requested  []string
+	}{
+		"Fail": {
+			policy:     GlacierFail,
+			err:        ErrArchived,
+			downloaded: []string{"prefix/a"},
+		},
+		"Skip": {
+			policy:     GlacierSkip,
+			downloaded: []string{"prefix/a"},
+		},
+		"Restore": {
+			policy:     GlacierRestoreAndWait(s3.TierBulk, 1),
+			downloaded: []string{"prefix/a", "prefix/b", "prefix/c"},
+			requested:  []string{"prefix/b", "prefix/c"},
+		},
+		"Ongoing": {
+			policy:     GlacierRestoreAndWait(s3.TierBulk, 1),
+			restored:   map[string]int{"prefix/b": 1},
+			downloaded: []string{"prefix/a", "prefix/b", "prefix/c"},
+			requested:  []string{"prefix/c"},
+		},
+		"Restored": {
+			policy:     GlacierFail,
+			restored:   map[string]int{"prefix/b": 0, "prefix/c": 0},
+			downloaded: []string{"prefix/a", "prefix/b", "prefix/c"},
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyGlacierS3{
+				dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+					"prefix/a", "prefix/b", "prefix/c",
+				}}},
+				archived: map[string]bool{"prefix/b": true, "prefix/c": true},
+				restored: map[string]int{},
+			}
+			for k, v := range tc.restored {
+				s.restored[k] = v
+			}
+			m := New(session.New(), WithGlacierPolicy(tc.policy))
+			m.s3 = s
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+			var fe *FileError
+			if tc.err != nil && (!errors.As(err, &fe) || fe.Op != "download") {
+				t.Errorf("Expected FileError of download, got: %v", err)
+			}
+
+			sort.Strings(s.downloaded)
+			if !reflect.DeepEqual(tc.downloaded, s.downloaded) {
+				t.Errorf("Expected %v to be downloaded, got %v", tc.downloaded, s.downloaded)
+			}
+			var requested []string
+			for _, r := range s.requests {
+				requested = append(requested, *r.Key)
+				if *r.RestoreRequest.Days != 1 || *r.RestoreRequest.GlacierJobParameters.Tier != s3.TierBulk {
+					t.Errorf("Unexpected restore request: %v", r)
+				}
+			}
+			sort.Strings(requested)
+			if !reflect.DeepEqual(tc.requested, requested) {
+				t.Errorf("Expected restorations of %v, got %v", tc.requested, requested)
+			}
+		})
+	}
+}
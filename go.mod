@@ -4,10 +4,12 @@ go 1.18
 
 require (
 	github.com/aws/aws-sdk-go v1.55.5
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/gabriel-vasile/mimetype v1.4.5
 )
 
 require (
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	golang.org/x/net v0.27.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
 )
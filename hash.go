@@ -0,0 +1,82 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"time"
+)
+
+// syncAll is the comparator deferring the comparisons to the hash workers.
+var syncAll = ComparatorFunc(func(src, dst *FileInfo) bool { return true })
+
+// compareFiles compares the source files with the overwritten destination files by
+// the hash workers of WithHashWorkers, separated from the transfer workers, since
+// the comparators hashing the local files are bound by the disk and CPU.
+// It returns the channel which receives the file operations necessary to sync.
+// The other operations are passed through. The order of the operations is preserved
+// for SyncWithTimeBudget, which checkpoints the last source name received.
+// onSkip is called for each source file skipped as up-to-date.
+func (m *Manager) compareFiles(ctx context.Context, ops chan *fileOp, cmp Comparator, onSkip func(*fileInfo)) chan *fileOp {
+	type job struct {
+		file   *fileOp
+		result chan *fileOp
+	}
+	jobs := make(chan job)
+	// pending receives the jobs in the order of the operations, and bounds
+	// the comparisons done ahead of the slowest one.
+	pending := make(chan job, 2*m.hashWorkers)
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for file := range ops {
+			j := job{file: file, result: make(chan *fileOp, 1)}
+			pending <- j
+			jobs <- j
+		}
+	}()
+
+	for i := 0; i < m.hashWorkers; i++ {
+		go func() {
+			for j := range jobs {
+				file := j.file
+				if file.err != nil || file.op == opDelete || file.overwritten == nil {
+					j.result <- file
+					continue
+				}
+				start := time.Now()
+				shouldSync := cmp.ShouldSync(file.export(), file.overwritten.export())
+				m.emit(ctx, SyncEvent{Type: FileCompared, Path: file.name, Size: file.size, Duration: time.Since(start)})
+				if !shouldSync {
+					file.overwritten = nil
+					onSkip(file.fileInfo)
+					file = nil
+				}
+				j.result <- file
+			}
+		}()
+	}
+
+	c := make(chan *fileOp)
+	go func() {
+		defer close(c)
+		for j := range pending {
+			if file := <-j.result; file != nil {
+				c <- file
+			}
+		}
+	}()
+	return c
+}
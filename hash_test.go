@@ -0,0 +1,117 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestCompareFiles(t *testing.T) {
+	const workers = 3
+	// The comparisons block until all of the workers compare the files concurrently.
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	barrier := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(barrier)
+	}()
+	cmp := ComparatorFunc(func(src, dst *FileInfo) bool {
+		wg.Done()
+		select {
+		case <-barrier:
+		case <-time.After(5 * time.Second):
+			t.Error("Files are not compared concurrently")
+		}
+		return src.Size != dst.Size
+	})
+
+	ops := make(chan *fileOp, 10)
+	ops <- &fileOp{fileInfo: &fileInfo{name: "new"}}
+	ops <- &fileOp{fileInfo: &fileInfo{err: errors.New("error")}}
+	ops <- &fileOp{fileInfo: &fileInfo{name: "deleted"}, op: opDelete}
+	ops <- &fileOp{fileInfo: &fileInfo{name: "same1", size: 1, overwritten: &fileInfo{size: 1}}}
+	ops <- &fileOp{fileInfo: &fileInfo{name: "same2", size: 1, overwritten: &fileInfo{size: 1}}}
+	ops <- &fileOp{fileInfo: &fileInfo{name: "changed", size: 1, overwritten: &fileInfo{size: 2}}}
+	close(ops)
+
+	m := New(session.New(), WithHashWorkers(workers))
+	var mu sync.Mutex
+	var skipped []string
+	var synced []string
+	for file := range m.compareFiles(context.Background(), ops, cmp, func(file *fileInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		skipped = append(skipped, file.name)
+	}) {
+		synced = append(synced, file.name)
+	}
+	sort.Strings(skipped)
+	if expected := []string{"same1", "same2"}; !reflect.DeepEqual(expected, skipped) {
+		t.Errorf("Expected the skipped files %v, got %v", expected, skipped)
+	}
+	// The order of the operations is preserved.
+	if expected := []string{"new", "", "deleted", "changed"}; !reflect.DeepEqual(expected, synced) {
+		t.Errorf("Expected the synced files %v, got %v", expected, synced)
+	}
+}
+
+func TestHashWorkers(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	for _, name := range []string{"a", "b"} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte("a"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b", "prefix/c"}}}
+	m := New(session.New(), WithHashWorkers(2), WithComparator(ComparatorFunc(func(src, dst *FileInfo) bool {
+		return src.Name != "a"
+	})))
+	m.s3 = s
+	events := m.Events()
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(s.downloaded)
+	if len(s.downloaded) != 2 || s.downloaded[0] != "prefix/b" || s.downloaded[1] != "prefix/c" {
+		t.Errorf("Expected the changed and new files to be downloaded, got %v", s.downloaded)
+	}
+	var compared []string
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == FileCompared {
+			compared = append(compared, ev.Path)
+		}
+	}
+	sort.Strings(compared)
+	if len(compared) != 2 || compared[0] != "a" || compared[1] != "b" {
+		t.Errorf("Expected the existing files to be compared, got %v", compared)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// IgnoreFileName is the name of the ignore file read from the source root by WithIgnoreFile.
+const IgnoreFileName = ".s3ignore"
+
+// ignoreRule is a pattern of the ignore file in gitignore syntax.
+type ignoreRule struct {
+	negate  bool
+	dirOnly bool
+	pattern *regexp.Regexp
+}
+
+type ignoreRulesKey struct{}
+
+// parseIgnoreRules parses the ignore file in gitignore syntax.
+func parseIgnoreRules(r io.Reader) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if strings.HasSuffix(line, `\`) {
+			// Escaped trailing space
+			line += " "
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// The pattern containing "/" except at the end is relative to the root.
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		expr := ignoreGlobToRegexp(line)
+		if !anchored {
+			expr = "(.*/)?" + expr
+		}
+		rule.pattern = regexp.MustCompile("^" + expr + "$")
+		rules = append(rules, rule)
+	}
+	return rules, s.Err()
+}
+
+// ignoreGlobToRegexp converts the gitignore glob to the regexp.
+// "*" and "?" don't match "/", and "**" matches any directories.
+func ignoreGlobToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end <= 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			set := glob[i+1 : i+1+end]
+			if strings.HasPrefix(set, "!") {
+				set = "^" + set[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(set, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// matchIgnoreRules returns whether the slash separated path is ignored by the rules.
+// The last matching rule wins.
+func matchIgnoreRules(rules []ignoreRule, name string, dir bool) bool {
+	ignored := false
+	for _, r := range rules {
+		if (!r.dirOnly || dir) && r.pattern.MatchString(name) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// isHidden returns whether any segment of the slash separated path starts with ".".
+func isHidden(name string) bool {
+	for _, seg := range strings.Split(name, "/") {
+		if strings.HasPrefix(seg, ".") && seg != "." && seg != ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored returns whether the file of the name relative to the root is ignored
+// by WithIgnoreHidden or the ignore file.
+// The file under the ignored directory is ignored like gitignore.
+func (m *Manager) ignored(ctx context.Context, name string, dir bool) bool {
+	name = filepath.ToSlash(name)
+	if name == "." {
+		return false
+	}
+	if m.ignoreHidden && isHidden(name) {
+		return true
+	}
+	rules, _ := ctx.Value(ignoreRulesKey{}).([]ignoreRule)
+	if len(rules) == 0 {
+		return false
+	}
+	segs := strings.Split(name, "/")
+	for i := 1; i < len(segs); i++ {
+		if matchIgnoreRules(rules, strings.Join(segs[:i], "/"), true) {
+			return true
+		}
+	}
+	return matchIgnoreRules(rules, name, dir)
+}
+
+// ignoreWalk returns the walk function which skips the ignored files and directories.
+func (m *Manager) ignoreWalk(ctx context.Context, walk func(string, filepath.WalkFunc) error) func(string, filepath.WalkFunc) error {
+	if _, ok := ctx.Value(ignoreRulesKey{}).([]ignoreRule); !ok && !m.ignoreHidden {
+		return walk
+	}
+	return func(root string, fn filepath.WalkFunc) error {
+		return walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil {
+				if rel, err := filepath.Rel(root, path); err == nil && m.ignored(ctx, rel, info.IsDir()) {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+			}
+			return fn(path, info, err)
+		})
+	}
+}
+
+// loadIgnoreFile returns the context with the rules of the ignore file in the source root
+// if WithIgnoreFile is set. The missing ignore file is ignored.
+func (m *Manager) loadIgnoreFile(ctx context.Context, source string, sourceURL *url.URL) (context.Context, error) {
+	if !m.ignoreFile {
+		return ctx, nil
+	}
+	var r io.ReadCloser
+	if isS3URL(sourceURL) {
+		path, err := urlToS3Path(sourceURL)
+		if err != nil {
+			return ctx, err
+		}
+		key := objectKey(path.bucketPrefix, IgnoreFileName)
+		out, err := m.regionalClient(ctx, m.sourceClient(), path.bucket).GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket:               aws.String(path.bucket),
+			Key:                  aws.String(key),
+			SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+			SSECustomerKey:       m.sseCustomerKey,
+		})
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return ctx, nil
+		}
+		if err != nil {
+			return ctx, err
+		}
+		r = out.Body
+	} else {
+		if stat, err := os.Stat(source); err != nil || !stat.IsDir() {
+			// The single file or the missing directory is synced as is.
+			return ctx, nil
+		}
+		f, err := os.Open(filepath.Join(source, IgnoreFileName))
+		if os.IsNotExist(err) {
+			return ctx, nil
+		}
+		if err != nil {
+			return ctx, err
+		}
+		r = f
+	}
+	defer r.Close()
+	rules, err := parseIgnoreRules(r)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, ignoreRulesKey{}, rules), nil
+}
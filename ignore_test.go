@@ -0,0 +1,164 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules, err := parseIgnoreRules(strings.NewReader(strings.Join([]string{
+		"# comment",
+		"",
+		"*.log",
+		"!keep.log",
+		"build/",
+		"/root.txt",
+		"docs/*.md",
+		"**/cache/**",
+		`\#hash`,
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := map[string]struct {
+		name    string
+		dir     bool
+		ignored bool
+	}{
+		"Log":          {"a.log", false, true},
+		"NestedLog":    {"src/a.log", false, true},
+		"Negated":      {"keep.log", false, false},
+		"NestedNegate": {"src/keep.log", false, false},
+		"Dir":          {"build", true, true},
+		"DirOnly":      {"build", false, false},
+		"Anchored":     {"root.txt", false, true},
+		"NotAnchored":  {"src/root.txt", false, false},
+		"Middle":       {"docs/a.md", false, true},
+		"MiddleNested": {"docs/api/a.md", false, false},
+		"DoubleStar":   {"a/cache/b", false, true},
+		"Escaped":      {"#hash", false, true},
+		"Other":        {"src/main.go", false, false},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if ret := matchIgnoreRules(rules, tc.name, tc.dir); ret != tc.ignored {
+				t.Errorf("Expected %s ignored: %v, got: %v", tc.name, tc.ignored, ret)
+			}
+		})
+	}
+}
+
+func TestIgnore_Local(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	for name, contents := range map[string]string{
+		".hidden":      "",
+		".git/config":  "",
+		".s3ignore":    "build/\n*.log\n!keep.log\n",
+		"a.txt":        "",
+		"build/out.o":  "",
+		"keep.log":     "",
+		"src/main.go":  "",
+		"src/tmp.log":  "",
+		"src/.env":     "",
+		"src/build.go": "",
+	} {
+		filename := filepath.Join(temp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := map[string]struct {
+		options  []Option
+		expected []string
+	}{
+		"Default": {
+			expected: []string{".git/config", ".hidden", ".s3ignore", "a.txt", "build/out.o", "keep.log", "src/.env", "src/build.go", "src/main.go", "src/tmp.log"},
+		},
+		"Hidden": {
+			options:  []Option{WithIgnoreHidden()},
+			expected: []string{"a.txt", "build/out.o", "keep.log", "src/build.go", "src/main.go", "src/tmp.log"},
+		},
+		"IgnoreFile": {
+			options:  []Option{WithIgnoreFile()},
+			expected: []string{".git/config", ".hidden", ".s3ignore", "a.txt", "keep.log", "src/.env", "src/build.go", "src/main.go"},
+		},
+		"Both": {
+			options:  []Option{WithIgnoreHidden(), WithIgnoreFile()},
+			expected: []string{"a.txt", "keep.log", "src/build.go", "src/main.go"},
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), tc.options...)
+			ctx, err := m.loadIgnoreFile(context.Background(), temp, &url.URL{Path: temp})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for fi := range m.listLocalFiles(ctx, temp, nil) {
+				if fi.err != nil {
+					t.Fatal(fi.err)
+				}
+				names = append(names, filepath.ToSlash(fi.name))
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(tc.expected, names) {
+				t.Errorf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestIgnore_S3(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	// Contents of the ignore file is "a".
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+		"prefix/.hidden", "prefix/a", "prefix/b", "prefix/c/a", "prefix/c/d",
+	}}}
+	m := New(session.New(), WithIgnoreHidden(), WithIgnoreFile())
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(s.downloaded)
+	if expected := []string{"prefix/.s3ignore", "prefix/b", "prefix/c/d"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected %v to be downloaded, got %v", expected, s.downloaded)
+	}
+}
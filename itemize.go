@@ -0,0 +1,76 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+)
+
+// itemizer writes the rsync style itemized changes of the file operations.
+type itemizer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// itemizedChange returns the line of the file operation in the format of
+// the itemized changes of rsync, e.g. ">f+++++++++ path" for a new file,
+// "<f.st...... path" for an updated file and "*deleting   path" for a deletion.
+// The uploads are marked as sent ("<"), and the downloads and the copies
+// are marked as received (">").
+func itemizedChange(typ SyncEventType, file *fileInfo) string {
+	name := filepath.ToSlash(file.name)
+	var update byte
+	switch typ {
+	case FileDeleted:
+		return "*deleting   " + name
+	case FileUploaded:
+		update = '<'
+	case FileDownloaded, FileCopied:
+		update = '>'
+	default:
+		return ""
+	}
+	fileType := byte('f')
+	if file.symlink != "" {
+		fileType = 'L'
+	}
+	attrs := []byte("+++++++++")
+	if old := file.overwritten; old != nil {
+		attrs = []byte(".........")
+		if old.size != file.size {
+			attrs[1] = 's'
+		}
+		if !old.lastModified.Equal(file.lastModified) {
+			attrs[2] = 't'
+		}
+	}
+	return fmt.Sprintf("%c%c%s %s", update, fileType, attrs, name)
+}
+
+// itemize writes the itemized change of the file operation if enabled.
+func (m *Manager) itemize(typ SyncEventType, file *fileInfo) {
+	if m.itemizer == nil {
+		return
+	}
+	line := itemizedChange(typ, file)
+	if line == "" {
+		return
+	}
+	m.itemizer.mu.Lock()
+	defer m.itemizer.mu.Unlock()
+	fmt.Fprintln(m.itemizer.w, line)
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestItemizedChange(t *testing.T) {
+	t0 := time.Now()
+	t1 := t0.Add(time.Second)
+	testCases := map[string]struct {
+		typ      SyncEventType
+		file     *fileInfo
+		expected string
+	}{
+		"NewUpload":   {FileUploaded, &fileInfo{name: "dir/a", size: 1}, "<f+++++++++ dir/a"},
+		"NewDownload": {FileDownloaded, &fileInfo{name: "a", size: 1}, ">f+++++++++ a"},
+		"Copy":        {FileCopied, &fileInfo{name: "a", size: 1}, ">f+++++++++ a"},
+		"Symlink":     {FileUploaded, &fileInfo{name: "link", symlink: "target"}, "<L+++++++++ link"},
+		"SizeChanged": {FileUploaded, &fileInfo{name: "a", size: 2, lastModified: t0, overwritten: &fileInfo{size: 1, lastModified: t0}}, "<f.s....... a"},
+		"TimeChanged": {FileDownloaded, &fileInfo{name: "a", size: 1, lastModified: t1, overwritten: &fileInfo{size: 1, lastModified: t0}}, ">f..t...... a"},
+		"BothChanged": {FileDownloaded, &fileInfo{name: "a", size: 2, lastModified: t1, overwritten: &fileInfo{size: 1, lastModified: t0}}, ">f.st...... a"},
+		"Deleted":     {FileDeleted, &fileInfo{name: "a"}, "*deleting   a"},
+		"NotItemized": {FileSkipped, &fileInfo{name: "a"}, ""},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if line := itemizedChange(tc.typ, tc.file); line != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, line)
+			}
+		})
+	}
+}
+
+func TestWithItemizedOutput(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	for name, data := range map[string]string{"b": "bb", "stale": "stale"} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte(data), 0644); err != nil {
+			t.Fatal("Failed to write", err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	m := New(session.New(), WithDelete(), WithDryRun(), WithItemizedOutput(buf))
+	m.s3 = &dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	sort.Strings(lines)
+	expected := []string{"*deleting   stale", ">f+++++++++ a", ">f.st...... b"}
+	if !reflect.DeepEqual(expected, lines) {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// KeySanitizer sanitizes the destination keys for the downstream systems.
+type KeySanitizer struct {
+	// Lowercase converts the keys to lower case.
+	Lowercase bool
+	// InvalidChars are the characters replaced by Replacement.
+	InvalidChars string
+	// Replacement is the string to replace InvalidChars.
+	Replacement string
+	// EscapeControl percent-encodes the control characters.
+	EscapeControl bool
+}
+
+// Sanitize returns the sanitized key.
+func (s KeySanitizer) Sanitize(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		switch {
+		case s.EscapeControl && unicode.IsControl(r):
+			for _, c := range []byte(string(r)) {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		case strings.ContainsRune(s.InvalidChars, r):
+			b.WriteString(s.Replacement)
+		case s.Lowercase:
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// slashKeyMapper returns the key mapper calling the mapper with the slash separated name.
+func slashKeyMapper(mapper func(string) string) func(string) string {
+	return func(name string) string {
+		return mapper(filepath.ToSlash(name))
+	}
+}
+
+// destKeyName returns the name of the file on the destination.
+func (f *fileInfo) destKeyName() string {
+	if f.destName != "" {
+		return f.destName
+	}
+	return f.name
+}
+
+// mapDestKeys returns a channel which receives the given file infos with the names
+// on the destination mapped by the key mappers.
+// If multiple files are mapped to the same name, the error is sent instead of
+// the later one.
+func (m *Manager) mapDestKeys(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	if len(m.keyMappers) == 0 {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		mapped := make(map[string]string)
+		for fi := range files {
+			if fi.err == nil {
+				name := fi.name
+				for _, mapper := range m.keyMappers {
+					name = mapper(name)
+				}
+				if name == "" {
+					err := fmt.Errorf("%s is mapped to the empty key", fi.name)
+					println("Skipping", fi.name+":", err)
+					fi = &fileInfo{err: err}
+				} else if orig, ok := mapped[name]; ok {
+					err := fmt.Errorf("key collision: %s and %s are mapped to %s", orig, fi.name, name)
+					println("Skipping", fi.name+":", err)
+					fi = &fileInfo{err: err}
+				} else {
+					mapped[name] = fi.name
+					fi.destName = name
+				}
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
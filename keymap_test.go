@@ -0,0 +1,120 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeySanitizer(t *testing.T) {
+	testCases := map[string]struct {
+		sanitizer KeySanitizer
+		input     string
+		expected  string
+	}{
+		"NoOp": {
+			sanitizer: KeySanitizer{},
+			input:     "Foo/Bar baz.txt",
+			expected:  "Foo/Bar baz.txt",
+		},
+		"Lowercase": {
+			sanitizer: KeySanitizer{Lowercase: true},
+			input:     "Foo/Bar.TXT",
+			expected:  "foo/bar.txt",
+		},
+		"Replace": {
+			sanitizer: KeySanitizer{InvalidChars: " :", Replacement: "_"},
+			input:     "foo bar:baz",
+			expected:  "foo_bar_baz",
+		},
+		"EscapeControl": {
+			sanitizer: KeySanitizer{EscapeControl: true},
+			input:     "foo\tbar\x7f",
+			expected:  "foo%09bar%7F",
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if ret := tt.sanitizer.Sanitize(tt.input); ret != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, ret)
+			}
+		})
+	}
+}
+
+func TestMapDestKeys(t *testing.T) {
+	m := New(getSession(), WithKeySanitizer(KeySanitizer{Lowercase: true}))
+
+	files := make(chan *fileInfo, 3)
+	files <- &fileInfo{name: "Foo.txt"}
+	files <- &fileInfo{name: "bar.txt"}
+	files <- &fileInfo{name: "foo.TXT"}
+	close(files)
+
+	var mapped []string
+	var errs []error
+	for fi := range m.mapDestKeys(context.Background(), files) {
+		if fi.err != nil {
+			errs = append(errs, fi.err)
+			continue
+		}
+		mapped = append(mapped, fi.name+">"+fi.destKeyName())
+	}
+	if len(mapped) != 2 || mapped[0] != "Foo.txt>foo.txt" || mapped[1] != "bar.txt>bar.txt" {
+		t.Errorf("Unexpected mapping %v", mapped)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Collision must be reported, got %v", errs)
+	}
+}
+
+func TestKeyMapper(t *testing.T) {
+	m := New(getSession(),
+		WithKeyMapper(func(name string) string { return "dt=2024-01-01/" + name }),
+		WithKeySanitizer(KeySanitizer{Lowercase: true}),
+		WithKeyMapper(func(name string) string {
+			if strings.HasSuffix(name, ".tmp") {
+				return ""
+			}
+			return name
+		}),
+	)
+
+	files := make(chan *fileInfo, 3)
+	files <- &fileInfo{name: filepath.Join("Dir", "Foo.txt")}
+	files <- &fileInfo{name: "bar.tmp"}
+	files <- &fileInfo{name: "baz.txt"}
+	close(files)
+
+	var mapped []string
+	var errs []error
+	for fi := range m.mapDestKeys(context.Background(), files) {
+		if fi.err != nil {
+			errs = append(errs, fi.err)
+			continue
+		}
+		mapped = append(mapped, fi.destKeyName())
+	}
+	if expected := []string{"dt=2024-01-01/dir/foo.txt", "dt=2024-01-01/baz.txt"}; !reflect.DeepEqual(expected, mapped) {
+		t.Errorf("Expected %v, got %v", expected, mapped)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Empty key must be reported, got %v", errs)
+	}
+}
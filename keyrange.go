@@ -0,0 +1,44 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// hasKeyRange returns whether the key range is set.
+func (m *Manager) hasKeyRange() bool {
+	return m.keyRangeStart != "" || m.keyRangeEnd != ""
+}
+
+// inKeyRange returns whether the slash separated name is in the key range.
+func (m *Manager) inKeyRange(name string) bool {
+	return (m.keyRangeStart == "" || name > m.keyRangeStart) &&
+		!m.afterKeyRange(name)
+}
+
+// afterKeyRange returns whether the slash separated name is after the end of the key range.
+func (m *Manager) afterKeyRange(name string) bool {
+	return m.keyRangeEnd != "" && name > m.keyRangeEnd
+}
+
+// startAfter returns the StartAfter parameter of the listing under the given path.
+func (m *Manager) startAfter(path *s3Path) *string {
+	if m.keyRangeStart == "" {
+		return nil
+	}
+	return aws.String(filepath.ToSlash(filepath.Join(path.bucketPrefix, m.keyRangeStart)))
+}
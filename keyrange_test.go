@@ -0,0 +1,116 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyKeyRangeS3 struct {
+	s3iface.S3API
+	keys       []string
+	mu         sync.Mutex
+	startAfter []string
+}
+
+func (s *dummyKeyRangeS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	s.mu.Lock()
+	s.startAfter = append(s.startAfter, aws.StringValue(in.StartAfter))
+	s.mu.Unlock()
+	out := &s3.ListObjectsV2Output{}
+	start := 0
+	if in.ContinuationToken != nil {
+		start = len(*in.ContinuationToken)
+	}
+	for i := start; i < len(s.keys); i++ {
+		if s.keys[i] <= aws.StringValue(in.StartAfter) {
+			continue
+		}
+		if len(out.Contents) == 2 {
+			// Each page contains two objects and the token encodes the offset.
+			out.NextContinuationToken = aws.String(string(make([]byte, i)))
+			break
+		}
+		out.Contents = append(out.Contents, &s3.Object{
+			Key:          aws.String(s.keys[i]),
+			Size:         aws.Int64(1),
+			LastModified: aws.Time(time.Time{}),
+		})
+	}
+	return out, nil
+}
+
+func TestKeyRange(t *testing.T) {
+	s := &dummyKeyRangeS3{keys: []string{
+		"prefix/a", "prefix/b", "prefix/c", "prefix/c/d", "prefix/d", "prefix/e", "prefix/f", "prefix/g",
+	}}
+	m := New(session.New(), WithKeyRange("b", "d"))
+	m.s3 = s
+
+	var names []string
+	listed := m.applyFilters(context.Background(), m.listS3Files(context.Background(), &s3Path{bucket: "bucket", bucketPrefix: "prefix"}, nil))
+	for fi := range listed {
+		if fi.err != nil {
+			t.Fatal(fi.err)
+		}
+		names = append(names, fi.name)
+	}
+	sort.Strings(names)
+	if expected := []string{"c", "c/d", "d"}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	// The listing must start after the start key and terminate at the end key.
+	if expected := []string{"prefix/b", "prefix/b"}; !reflect.DeepEqual(expected, s.startAfter) {
+		t.Errorf("Expected requests starting after %v, got %v", expected, s.startAfter)
+	}
+}
+
+func TestKeyRange_Included(t *testing.T) {
+	m := &Manager{}
+	WithKeyRange("", "m")(m)
+	for name, expected := range map[string]bool{"a": true, "m": true, "m/a": false, "z": false} {
+		if ret := m.included(name); ret != expected {
+			t.Errorf("%s: expected %v, got %v", name, expected, ret)
+		}
+	}
+}
+
+func TestListS3Files_SpecialCharacters(t *testing.T) {
+	s := &dummyKeyRangeS3{keys: []string{"prefix/a+b c", "prefix/#日本/100%"}}
+	m := New(session.New())
+	m.s3 = s
+
+	var names []string
+	for fi := range m.listS3Files(context.Background(), &s3Path{bucket: "bucket", bucketPrefix: "prefix"}, nil) {
+		if fi.err != nil {
+			t.Fatal(fi.err)
+		}
+		names = append(names, fi.name)
+	}
+	if expected := []string{"a+b c", filepath.FromSlash("#日本/100%")}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+// workerPool is the queue of the sync jobs run by the workers.
+// If the large file lane is enabled, the jobs of the large files are run by
+// the dedicated workers so that they never occupy all of the workers.
+type workerPool struct {
+	jobs      chan func()
+	large     chan func()
+	threshold int64
+}
+
+// run queues the job processing the file of the given size.
+// It blocks until a worker receives the job, except that the jobs of the large
+// files are queued without blocking the small files listed after them.
+func (p *workerPool) run(size int64, job func()) {
+	if p.large != nil && size >= p.threshold {
+		p.large <- job
+		return
+	}
+	p.jobs <- job
+}
+
+// close stops receiving the jobs.
+func (p *workerPool) close() {
+	close(p.jobs)
+	if p.large != nil {
+		close(p.large)
+	}
+}
+
+// queueJobs forwards the jobs from in to out, queuing them while out is busy.
+// out is closed after all jobs are forwarded.
+func queueJobs(in, out chan func()) {
+	defer close(out)
+	var queue []func()
+	for in != nil || len(queue) > 0 {
+		var send chan func()
+		var next func()
+		if len(queue) > 0 {
+			send, next = out, queue[0]
+		}
+		select {
+		case job, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			queue = append(queue, job)
+		case send <- next:
+			queue[0] = nil
+			queue = queue[1:]
+		}
+	}
+}
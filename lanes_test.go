@@ -0,0 +1,67 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestLargeFileLane(t *testing.T) {
+	m := New(session.New(), WithParallel(3), WithLargeFileLane(100, 1))
+
+	var running, maxRunning int32
+	release := make(chan struct{})
+	largeJob := func() {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		<-release
+	}
+
+	workers, stopWorkers := m.startWorkers()
+	var small sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		workers.run(100, largeJob)
+		small.Add(1)
+		workers.run(99, small.Done)
+	}
+
+	// The small files must be processed while the large files occupy the lane.
+	done := make(chan struct{})
+	go func() {
+		small.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Small files are blocked by the large files")
+	}
+	close(release)
+	stopWorkers()
+
+	if maxRunning != 1 {
+		t.Errorf("Expected the large files to be processed by 1 worker, got %d", maxRunning)
+	}
+}
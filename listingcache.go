@@ -0,0 +1,193 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// listingCacheFile is the content of the listing cache file written by the dry-run.
+type listingCacheFile struct {
+	Created time.Time `json:"created"`
+	Source  string    `json:"source"`
+	Dest    string    `json:"dest"`
+	// Listings is the listed objects keyed by the S3 path and the patterns.
+	Listings map[string][]cachedObject `json:"listings"`
+}
+
+// cachedObject is the object in the cached listing.
+type cachedObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	ETag         string    `json:"etag"`
+	StorageClass string    `json:"storageClass,omitempty"`
+}
+
+// newCachedObject returns the cached object of the listed object.
+func newCachedObject(o *s3.Object) cachedObject {
+	return cachedObject{
+		Key:          aws.StringValue(o.Key),
+		Size:         aws.Int64Value(o.Size),
+		LastModified: aws.TimeValue(o.LastModified),
+		ETag:         aws.StringValue(o.ETag),
+		StorageClass: aws.StringValue(o.StorageClass),
+	}
+}
+
+// object returns the listed object of the cached object.
+func (o cachedObject) object() *s3.Object {
+	return &s3.Object{
+		Key:          aws.String(o.Key),
+		Size:         aws.Int64(o.Size),
+		LastModified: aws.Time(o.LastModified),
+		ETag:         aws.String(o.ETag),
+		StorageClass: aws.String(o.StorageClass),
+	}
+}
+
+// equal returns whether the cached objects are the same.
+func (o cachedObject) equal(other cachedObject) bool {
+	return o.Key == other.Key && o.Size == other.Size && o.ETag == other.ETag &&
+		o.StorageClass == other.StorageClass && o.LastModified.Equal(other.LastModified)
+}
+
+type listingCacheKey struct{}
+
+// listingCacheRun is the listing cache used by a sync call.
+// The dry-run records the listings, and the real run replays them.
+type listingCacheRun struct {
+	mu       sync.Mutex
+	record   bool
+	listings map[string][]cachedObject
+}
+
+// listingCacheEntry returns the key of the listing in the cache.
+func listingCacheEntry(path *s3Path, patterns []*regexp.Regexp) string {
+	key := path.String()
+	for _, p := range patterns {
+		key += "\n" + p.String()
+	}
+	return key
+}
+
+// useListingCache returns the context recording or replaying the listings of the sync
+// if the listing cache is enabled, and the function to be called with the error of the sync
+// when the sync finishes.
+// The dry-run writes the listings to the cache file on success.
+// The real run replays the listings in the cache file written within the TTL
+// for the same source and destination, and removes the cache file since the listings
+// are outdated by the sync.
+func (m *Manager) useListingCache(ctx context.Context, source, dest string) (context.Context, func(*error)) {
+	if m.listingCachePath == "" {
+		return ctx, func(*error) {}
+	}
+	if m.isDryRun(ctx) {
+		run := &listingCacheRun{record: true, listings: make(map[string][]cachedObject)}
+		return context.WithValue(ctx, listingCacheKey{}, run), func(err *error) {
+			if *err != nil {
+				return
+			}
+			run.mu.Lock()
+			defer run.mu.Unlock()
+			b, werr := json.Marshal(&listingCacheFile{
+				Created:  time.Now().UTC(),
+				Source:   source,
+				Dest:     dest,
+				Listings: run.listings,
+			})
+			if werr == nil {
+				werr = writeStateFile(m.listingCachePath, b)
+			}
+			if werr != nil {
+				*err = werr
+			}
+		}
+	}
+
+	done := func(*error) {
+		if err := os.Remove(m.listingCachePath); err != nil && !os.IsNotExist(err) {
+			println("Failed to remove the listing cache", err.Error())
+		}
+	}
+	b, err := ioutil.ReadFile(m.listingCachePath)
+	if os.IsNotExist(err) {
+		return ctx, done
+	}
+	var cache listingCacheFile
+	if err == nil {
+		err = json.Unmarshal(b, &cache)
+	}
+	switch {
+	case err != nil:
+		println("Ignoring the listing cache", err.Error())
+	case cache.Source != source || cache.Dest != dest:
+		println("Ignoring the listing cache of the different sync", cache.Source, "to", cache.Dest)
+	case time.Since(cache.Created) > m.listingCacheTTL:
+		println("Ignoring the listing cache expired", cache.Created.String())
+	default:
+		run := &listingCacheRun{listings: cache.Listings}
+		return context.WithValue(ctx, listingCacheKey{}, run), done
+	}
+	return ctx, done
+}
+
+// cachedListing returns the objects in the cached listing to be replayed.
+// Each cached listing is replayed only once, so that the listings after the sync,
+// e.g. for the manifest, are not served from the cache.
+func (m *Manager) cachedListing(ctx context.Context, path *s3Path, patterns []*regexp.Regexp) ([]*s3.Object, bool) {
+	run, ok := ctx.Value(listingCacheKey{}).(*listingCacheRun)
+	if !ok || run.record {
+		return nil, false
+	}
+	key := listingCacheEntry(path, patterns)
+	run.mu.Lock()
+	cached, ok := run.listings[key]
+	delete(run.listings, key)
+	run.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	objects := make([]*s3.Object, len(cached))
+	for i, o := range cached {
+		objects[i] = o.object()
+	}
+	return objects, true
+}
+
+// recordListing records the page of the listing to be written to the cache by the dry-run.
+func (m *Manager) recordListing(ctx context.Context, path *s3Path, patterns []*regexp.Regexp, first bool, objects []*s3.Object) {
+	run, ok := ctx.Value(listingCacheKey{}).(*listingCacheRun)
+	if !ok || !run.record {
+		return
+	}
+	key := listingCacheEntry(path, patterns)
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if first {
+		run.listings[key] = []cachedObject{}
+	}
+	for _, o := range objects {
+		run.listings[key] = append(run.listings[key], newCachedObject(o))
+	}
+}
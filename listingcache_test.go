@@ -0,0 +1,121 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestListingCache(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	cachePath := filepath.Join(temp, "cache.json")
+	dest := filepath.Join(temp, "dest")
+
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{
+		"prefix/a", "prefix/b", "prefix/c",
+	}}}
+	m := New(session.New(), WithDryRun(), WithListingCache(cachePath, time.Minute))
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", dest); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.downloaded) != 0 {
+		t.Fatalf("Dry-run must not download, got %v", s.downloaded)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("Listing cache must be written: %v", err)
+	}
+
+	// Objects added after the dry-run are not synced by the cached listing.
+	s.keys = append(s.keys, "prefix/d")
+	s.startAfter = nil
+	m = New(session.New(), WithListingCache(cachePath, time.Minute))
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", dest); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.startAfter) != 0 {
+		t.Errorf("Objects must not be listed again, listed %d times", len(s.startAfter))
+	}
+	sort.Strings(s.downloaded)
+	if expected := []string{"prefix/a", "prefix/b", "prefix/c"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected to download %v, got %v", expected, s.downloaded)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("Listing cache must be removed by the real run: %v", err)
+	}
+
+	// Without the cache, the objects are listed.
+	s.downloaded = nil
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", dest); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"prefix/d"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected to download %v, got %v", expected, s.downloaded)
+	}
+}
+
+func TestListingCache_Ignored(t *testing.T) {
+	testCases := map[string]struct {
+		ttl    time.Duration
+		source string
+	}{
+		"Expired":         {ttl: time.Nanosecond, source: "s3://bucket/prefix"},
+		"DifferentSource": {ttl: time.Minute, source: "s3://bucket/other"},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+			cachePath := filepath.Join(temp, "cache.json")
+			dest := filepath.Join(temp, "dest")
+
+			s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}}
+			m := New(session.New(), WithDryRun(), WithListingCache(cachePath, tc.ttl))
+			m.s3 = s
+			if err := m.Sync(context.Background(), tc.source, dest); err != nil {
+				t.Fatal(err)
+			}
+
+			s.keys = append(s.keys, "prefix/b")
+			m = New(session.New(), WithListingCache(cachePath, tc.ttl))
+			m.s3 = s
+			time.Sleep(time.Millisecond)
+			if err := m.Sync(context.Background(), "s3://bucket/prefix", dest); err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(s.downloaded)
+			if expected := []string{"prefix/a", "prefix/b"}; !reflect.DeepEqual(expected, s.downloaded) {
+				t.Errorf("Expected to download %v, got %v", expected, s.downloaded)
+			}
+		})
+	}
+}
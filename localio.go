@@ -0,0 +1,107 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io"
+)
+
+// localIOLimiter limits the number of the simultaneous reads and writes
+// of the local files shared by the transfer workers.
+// Each read or write call holds the limiter, so the transfers are still
+// run in parallel while the disk serves a limited number of requests.
+type localIOLimiter chan struct{}
+
+func newLocalIOLimiter(n int) localIOLimiter {
+	return make(localIOLimiter, n)
+}
+
+// acquire blocks until a slot of the local I/O is available.
+func (l localIOLimiter) acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l localIOLimiter) release() {
+	<-l
+}
+
+// limitLocalReader returns the local file reader limited by the local I/O concurrency.
+func (m *Manager) limitLocalReader(ctx context.Context, r io.Reader) io.Reader {
+	if m.localIO == nil {
+		return r
+	}
+	lr := &localIOReader{ctx: ctx, r: r, l: m.localIO}
+	if _, ok := r.(readSeekerAt); ok {
+		return &localIOReadSeekerAt{lr}
+	}
+	return lr
+}
+
+// limitLocalWriterAt returns the local file writer limited by the local I/O concurrency.
+func (m *Manager) limitLocalWriterAt(ctx context.Context, w io.WriterAt) io.WriterAt {
+	if m.localIO == nil {
+		return w
+	}
+	return &localIOWriterAt{ctx: ctx, w: w, l: m.localIO}
+}
+
+type localIOReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   localIOLimiter
+}
+
+func (r *localIOReader) Read(b []byte) (int, error) {
+	if err := r.l.acquire(r.ctx); err != nil {
+		return 0, err
+	}
+	defer r.l.release()
+	return r.r.Read(b)
+}
+
+type localIOReadSeekerAt struct {
+	*localIOReader
+}
+
+func (r *localIOReadSeekerAt) Seek(offset int64, whence int) (int64, error) {
+	return r.r.(io.Seeker).Seek(offset, whence)
+}
+
+func (r *localIOReadSeekerAt) ReadAt(b []byte, off int64) (int, error) {
+	if err := r.l.acquire(r.ctx); err != nil {
+		return 0, err
+	}
+	defer r.l.release()
+	return r.r.(io.ReaderAt).ReadAt(b, off)
+}
+
+type localIOWriterAt struct {
+	ctx context.Context
+	w   io.WriterAt
+	l   localIOLimiter
+}
+
+func (w *localIOWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	if err := w.l.acquire(w.ctx); err != nil {
+		return 0, err
+	}
+	defer w.l.release()
+	return w.w.WriteAt(b, off)
+}
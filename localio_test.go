@@ -0,0 +1,92 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type concurrencyWriterAt struct {
+	running, max int32
+}
+
+func (w *concurrencyWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	n := atomic.AddInt32(&w.running, 1)
+	defer atomic.AddInt32(&w.running, -1)
+	for {
+		max := atomic.LoadInt32(&w.max)
+		if n <= max || atomic.CompareAndSwapInt32(&w.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return len(b), nil
+}
+
+func TestLocalIOConcurrency(t *testing.T) {
+	m := &Manager{}
+	WithLocalIOConcurrency(2)(m)
+
+	cw := &concurrencyWriterAt{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := m.limitLocalWriterAt(context.Background(), cw)
+			if _, err := w.WriteAt([]byte("a"), 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if cw.max != 2 {
+		t.Errorf("Expected 2 concurrent writes, got %d", cw.max)
+	}
+
+	if _, ok := m.limitLocalReader(context.Background(), bytes.NewReader(nil)).(io.ReaderAt); !ok {
+		t.Error("ReaderAt interface must be kept")
+	}
+}
+
+func TestLocalIOConcurrency_Cancel(t *testing.T) {
+	m := &Manager{}
+	WithLocalIOConcurrency(1)(m)
+	if err := m.localIO.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer m.localIO.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r := m.limitLocalReader(ctx, bytes.NewReader([]byte("a")))
+	if _, err := r.Read(make([]byte, 1)); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestLocalIOConcurrency_Disabled(t *testing.T) {
+	m := &Manager{}
+	WithLocalIOConcurrency(0)(m)
+	r := bytes.NewReader(nil)
+	if m.limitLocalReader(context.Background(), r) != r {
+		t.Error("Reader must not be wrapped without the limit")
+	}
+}
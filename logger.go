@@ -12,7 +12,13 @@
 // limitations under the License.
 package s3sync
 
-import "log"
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
 
 // LoggerIF is the logger interface which this library requires.
 type LoggerIF interface {
@@ -24,6 +30,16 @@ type LoggerIF interface {
 	Logf(format string, v ...interface{})
 }
 
+// StructuredLoggerIF is the optional logger interface to receive the log
+// entries of the file operations with structured attributes
+// such as op, bucket, key, size, duration and attempt.
+type StructuredLoggerIF interface {
+	// LogAttrs inserts a log entry with the attributes given as
+	// alternating keys and values in the manner of log/slog.
+	// err is non-nil if the entry reports a failure.
+	LogAttrs(ctx context.Context, msg string, err error, args ...interface{})
+}
+
 // Logger is the logger instance.
 var logger LoggerIF
 
@@ -39,3 +55,55 @@ func println(v ...interface{}) {
 	}
 	logger.Log(v...)
 }
+
+type attemptKey struct{}
+
+// withAttempt returns the context holding the attempt number of the operation.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFromContext returns the attempt number of the operation.
+func attemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+// opAttrs returns the structured attributes of the file operation.
+func opAttrs(ctx context.Context, op, bucket, key string, size int64) []interface{} {
+	return []interface{}{
+		"op", op,
+		"bucket", bucket,
+		"key", key,
+		"size", size,
+		"attempt", attemptFromContext(ctx),
+	}
+}
+
+// logOp inserts a log entry of the file operation.
+// v is handled in the manner of fmt.Println, and attrs are passed to
+// the logger only if it implements StructuredLoggerIF.
+func logOp(ctx context.Context, attrs []interface{}, v ...interface{}) {
+	if l, ok := logger.(StructuredLoggerIF); ok {
+		l.LogAttrs(ctx, strings.TrimSuffix(fmt.Sprintln(v...), "\n"), nil, attrs...)
+		return
+	}
+	println(v...)
+}
+
+// logOpDone inserts a log entry of the finished file operation with its duration.
+// It is only logged to the logger implementing StructuredLoggerIF.
+func logOpDone(ctx context.Context, attrs []interface{}, start time.Time, err *error) {
+	l, ok := logger.(StructuredLoggerIF)
+	if !ok {
+		return
+	}
+	attrs = append(attrs, "duration", time.Since(start))
+	if *err != nil {
+		l.LogAttrs(ctx, "Failed", *err, attrs...)
+		return
+	}
+	l.LogAttrs(ctx, "Done", nil, attrs...)
+}
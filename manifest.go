@@ -0,0 +1,290 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DefaultManifestName is the default name of the manifest object.
+const DefaultManifestName = ".s3sync-manifest.json"
+
+var errVerifyNotS3 = errors.New("destination of the manifest verification must be s3 url")
+
+// ErrManifestSignature is returned if the signature of the manifest is invalid.
+var ErrManifestSignature = errors.New("invalid manifest signature")
+
+// Manifest is the index of the objects under the destination prefix
+// written after the local to S3 sync.
+type Manifest struct {
+	Generated time.Time       `json:"generated"`
+	Files     []ManifestEntry `json:"files"`
+	// HMAC is the hex encoded HMAC-SHA256 of the manifest with empty HMAC field.
+	HMAC string `json:"hmac,omitempty"`
+}
+
+// ManifestEntry is the entry of the object in the Manifest.
+type ManifestEntry struct {
+	// Key is the object key relative to the destination prefix.
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	ETag         string    `json:"etag"`
+}
+
+// writeManifest lists the objects under the destination prefix and writes the manifest object.
+func (m *Manager) writeManifest(ctx context.Context, destPath *s3Path) error {
+	if m.isDryRun(ctx) || m.readOnly {
+		return nil
+	}
+	manifest := &Manifest{Generated: time.Now().UTC(), Files: []ManifestEntry{}}
+	for fi := range m.applyFilters(ctx, m.listS3Files(ctx, destPath, nil)) {
+		if fi.err != nil {
+			return fi.err
+		}
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Key:          filepath.ToSlash(fi.name),
+			Size:         fi.size,
+			LastModified: fi.lastModified.UTC(),
+			ETag:         fi.etag,
+		})
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Key < manifest.Files[j].Key
+	})
+
+	if m.manifestHMACKey != nil {
+		mac, err := manifest.calcHMAC(m.manifestHMACKey)
+		if err != nil {
+			return err
+		}
+		manifest.HMAC = mac
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	key := filepath.ToSlash(filepath.Join(destPath.bucketPrefix, m.manifestName))
+	println("Writing manifest", key, "in bucket", destPath.bucket)
+	_, err = m.destClient(ctx, destPath.bucket).PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(destPath.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(b),
+		ContentType:          aws.String("application/json"),
+		ACL:                  m.acl,
+		ServerSideEncryption: m.sse,
+		SSEKMSKeyId:          m.sseKMSKeyID,
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	return err
+}
+
+// calcHMAC returns the hex encoded HMAC-SHA256 of the manifest with empty HMAC field.
+func (m Manifest) calcHMAC(key []byte) (string, error) {
+	m.HMAC = ""
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ManifestVerifier verifies the signature of the manifest.
+type ManifestVerifier interface {
+	// Verify returns ErrManifestSignature if the signature is invalid.
+	// The signature is empty if not detached.
+	Verify(manifest, signature []byte) error
+}
+
+// ManifestVerifierFunc is the function implementing ManifestVerifier.
+type ManifestVerifierFunc func(manifest, signature []byte) error
+
+// Verify implements ManifestVerifier.
+func (f ManifestVerifierFunc) Verify(manifest, signature []byte) error {
+	return f(manifest, signature)
+}
+
+// HMACManifestVerifier returns the verifier of the HMAC-SHA256 embedded in the manifest,
+// which is written by WithManifestHMACKey.
+// The detached signature is ignored.
+func HMACManifestVerifier(key []byte) ManifestVerifier {
+	return ManifestVerifierFunc(func(b, _ []byte) error {
+		var manifest Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return err
+		}
+		expected, err := manifest.calcHMAC(key)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal([]byte(expected), []byte(manifest.HMAC)) {
+			return ErrManifestSignature
+		}
+		return nil
+	})
+}
+
+// Ed25519ManifestVerifier returns the verifier of the detached Ed25519 signature
+// of the manifest bytes.
+func Ed25519ManifestVerifier(publicKey ed25519.PublicKey) ManifestVerifier {
+	return ManifestVerifierFunc(func(b, signature []byte) error {
+		if !ed25519.Verify(publicKey, b, signature) {
+			return ErrManifestSignature
+		}
+		return nil
+	})
+}
+
+// ManifestDiff is the difference between the manifest and the objects.
+type ManifestDiff struct {
+	// Added is the keys not in the manifest.
+	Added []string
+	// Deleted is the keys in the manifest but missing.
+	Deleted []string
+	// Modified is the keys with the different size or ETag.
+	Modified []string
+}
+
+// Empty returns whether no difference is found.
+func (d *ManifestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Deleted) == 0 && len(d.Modified) == 0
+}
+
+// diffManifestEntries returns the difference from the old entries to the new entries.
+func diffManifestEntries(old, new []ManifestEntry) *ManifestDiff {
+	diff := &ManifestDiff{}
+	olds := make(map[string]ManifestEntry, len(old))
+	for _, e := range old {
+		olds[e.Key] = e
+	}
+	for _, e := range new {
+		o, ok := olds[e.Key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, e.Key)
+		case o.Size != e.Size || o.ETag != e.ETag:
+			diff.Modified = append(diff.Modified, e.Key)
+		}
+		delete(olds, e.Key)
+	}
+	for key := range olds {
+		diff.Deleted = append(diff.Deleted, key)
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Deleted)
+	sort.Strings(diff.Modified)
+	return diff
+}
+
+// ManifestChanges is the changes of the dataset between the manifests of two runs.
+type ManifestChanges struct {
+	ManifestDiff
+	// AddedBytes is the total size of the added files.
+	AddedBytes int64
+	// DeletedBytes is the total size of the deleted files.
+	DeletedBytes int64
+	// Growth is the difference of the total size from the old manifest to the new one.
+	// It is negative if the dataset shrinks.
+	Growth int64
+	// OldFiles and NewFiles are the number of the files in each manifest.
+	OldFiles int
+	NewFiles int
+}
+
+// DiffManifests compares the manifests saved from two runs of the sync, e.g. the scheduled
+// syncs of a dataset, and reports the new, modified and deleted files and the growth.
+func DiffManifests(old, new *Manifest) *ManifestChanges {
+	c := &ManifestChanges{
+		ManifestDiff: *diffManifestEntries(old.Files, new.Files),
+		OldFiles:     len(old.Files),
+		NewFiles:     len(new.Files),
+	}
+	added := make(map[string]bool, len(c.Added))
+	for _, key := range c.Added {
+		added[key] = true
+	}
+	deleted := make(map[string]bool, len(c.Deleted))
+	for _, key := range c.Deleted {
+		deleted[key] = true
+	}
+	for _, e := range old.Files {
+		c.Growth -= e.Size
+		if deleted[e.Key] {
+			c.DeletedBytes += e.Size
+		}
+	}
+	for _, e := range new.Files {
+		c.Growth += e.Size
+		if added[e.Key] {
+			c.AddedBytes += e.Size
+		}
+	}
+	return c
+}
+
+// VerifyManifest verifies the objects under the destination prefix against
+// the signed manifest, and reports the additions, deletions and modifications.
+// The signature of the manifest is verified by the verifier before listing the objects.
+// Only read APIs are called.
+func (m *Manager) VerifyManifest(ctx context.Context, dest string, manifest, signature []byte, verifier ManifestVerifier) (*ManifestDiff, error) {
+	destURL, err := parseURL(dest)
+	if err != nil {
+		return nil, err
+	}
+	if !isS3URL(destURL) {
+		return nil, errVerifyNotS3
+	}
+	destPath, err := urlToS3Path(destURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Verify(manifest, signature); err != nil {
+		return nil, err
+	}
+	var expected Manifest
+	if err := json.Unmarshal(manifest, &expected); err != nil {
+		return nil, err
+	}
+
+	var actual []ManifestEntry
+	for fi := range m.applyFilters(ctx, m.listS3Files(ctx, destPath, nil)) {
+		if fi.err != nil {
+			return nil, fi.err
+		}
+		actual = append(actual, ManifestEntry{
+			Key:          filepath.ToSlash(fi.name),
+			Size:         fi.size,
+			LastModified: fi.lastModified.UTC(),
+			ETag:         fi.etag,
+		})
+	}
+	return diffManifestEntries(expected.Files, actual), nil
+}
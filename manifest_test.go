@@ -0,0 +1,190 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyManifestS3 struct {
+	s3iface.S3API
+	objects []*s3.Object
+	put     map[string][]byte
+}
+
+func (s *dummyManifestS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	return &s3.ListObjectsV2Output{Contents: s.objects}, nil
+}
+
+func (s *dummyManifestS3) PutObjectWithContext(ctx aws.Context, in *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	b, err := ioutil.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.put[*in.Key] = b
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestWriteManifest(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &dummyManifestS3{
+		objects: []*s3.Object{
+			{Key: aws.String("prefix/foo/bar"), Size: aws.Int64(3), LastModified: aws.Time(t0), ETag: aws.String(`"etag2"`)},
+			{Key: aws.String("prefix/baz"), Size: aws.Int64(5), LastModified: aws.Time(t0), ETag: aws.String(`"etag1"`)},
+			{Key: aws.String("prefix/" + DefaultManifestName), Size: aws.Int64(100), LastModified: aws.Time(t0)},
+		},
+		put: make(map[string][]byte),
+	}
+	m := New(session.New(), WithManifest(""))
+	m.s3 = s
+
+	if err := m.writeManifest(context.Background(), &s3Path{bucket: "bucket", bucketPrefix: "prefix"}); err != nil {
+		t.Fatal(err)
+	}
+	b, ok := s.put["prefix/"+DefaultManifestName]
+	if !ok {
+		t.Fatalf("Manifest is not written: %v", s.put)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	expected := []ManifestEntry{
+		{Key: "baz", Size: 5, LastModified: t0, ETag: `"etag1"`},
+		{Key: "foo/bar", Size: 3, LastModified: t0, ETag: `"etag2"`},
+	}
+	if len(manifest.Files) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, manifest.Files)
+	}
+	for i := range expected {
+		if manifest.Files[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], manifest.Files[i])
+		}
+	}
+}
+
+func TestVerifyManifest(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	key := []byte("secret")
+	s := &dummyManifestS3{
+		objects: []*s3.Object{
+			{Key: aws.String("prefix/foo"), Size: aws.Int64(3), LastModified: aws.Time(t0), ETag: aws.String(`"foo"`)},
+			{Key: aws.String("prefix/bar"), Size: aws.Int64(3), LastModified: aws.Time(t0), ETag: aws.String(`"bar"`)},
+		},
+		put: make(map[string][]byte),
+	}
+	m := New(session.New(), WithManifest(""), WithManifestHMACKey(key))
+	m.s3 = s
+	dest := &s3Path{bucket: "bucket", bucketPrefix: "prefix"}
+	if err := m.writeManifest(context.Background(), dest); err != nil {
+		t.Fatal(err)
+	}
+	manifest := s.put["prefix/"+DefaultManifestName]
+
+	t.Run("Unchanged", func(t *testing.T) {
+		diff, err := m.VerifyManifest(context.Background(), "s3://bucket/prefix", manifest, nil, HMACManifestVerifier(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !diff.Empty() {
+			t.Errorf("Unexpected difference: %v", diff)
+		}
+	})
+	t.Run("Tampered", func(t *testing.T) {
+		s.objects = []*s3.Object{
+			{Key: aws.String("prefix/foo"), Size: aws.Int64(4), LastModified: aws.Time(t0), ETag: aws.String(`"foo2"`)},
+			{Key: aws.String("prefix/baz"), Size: aws.Int64(3), LastModified: aws.Time(t0), ETag: aws.String(`"baz"`)},
+		}
+		diff, err := m.VerifyManifest(context.Background(), "s3://bucket/prefix", manifest, nil, HMACManifestVerifier(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(diff.Added) != 1 || diff.Added[0] != "baz" ||
+			len(diff.Deleted) != 1 || diff.Deleted[0] != "bar" ||
+			len(diff.Modified) != 1 || diff.Modified[0] != "foo" {
+			t.Errorf("Unexpected difference: %v", diff)
+		}
+	})
+	t.Run("InvalidHMAC", func(t *testing.T) {
+		_, err := m.VerifyManifest(context.Background(), "s3://bucket/prefix", manifest, nil, HMACManifestVerifier([]byte("wrong")))
+		if err != ErrManifestSignature {
+			t.Errorf("Expected %v, got %v", ErrManifestSignature, err)
+		}
+	})
+	t.Run("Ed25519", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature := ed25519.Sign(priv, manifest)
+		if err := Ed25519ManifestVerifier(pub).Verify(manifest, signature); err != nil {
+			t.Errorf("Expected valid signature, got %v", err)
+		}
+		tampered := append([]byte{}, manifest...)
+		tampered[len(tampered)-2] = 'x'
+		if err := Ed25519ManifestVerifier(pub).Verify(tampered, signature); err != ErrManifestSignature {
+			t.Errorf("Expected %v, got %v", ErrManifestSignature, err)
+		}
+	})
+	t.Run("NotS3", func(t *testing.T) {
+		_, err := m.VerifyManifest(context.Background(), "local/path", manifest, nil, HMACManifestVerifier(key))
+		if err != errVerifyNotS3 {
+			t.Errorf("Expected %v, got %v", errVerifyNotS3, err)
+		}
+	})
+}
+
+func TestDiffManifests(t *testing.T) {
+	old := &Manifest{Files: []ManifestEntry{
+		{Key: "bar", Size: 10, ETag: "bar"},
+		{Key: "foo", Size: 3, ETag: "foo"},
+	}}
+	new := &Manifest{Files: []ManifestEntry{
+		{Key: "baz", Size: 5, ETag: "baz"},
+		{Key: "foo", Size: 30, ETag: "foo2"},
+		{Key: "qux", Size: 7, ETag: "qux"},
+	}}
+	c := DiffManifests(old, new)
+
+	expected := &ManifestChanges{
+		ManifestDiff: ManifestDiff{
+			Added:    []string{"baz", "qux"},
+			Deleted:  []string{"bar"},
+			Modified: []string{"foo"},
+		},
+		AddedBytes:   12,
+		DeletedBytes: 10,
+		Growth:       29,
+		OldFiles:     2,
+		NewFiles:     3,
+	}
+	if !reflect.DeepEqual(expected, c) {
+		t.Errorf("Expected %+v, got %+v", expected, c)
+	}
+	if c := DiffManifests(new, new); !c.Empty() || c.Growth != 0 {
+		t.Errorf("Expected no change, got %+v", c)
+	}
+}
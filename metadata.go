@@ -0,0 +1,85 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// hasMetadataOptions returns whether any of the metadata options is set.
+func (m *Manager) hasMetadataOptions() bool {
+	return len(m.metadata) > 0 || m.cacheControl != nil || m.contentEncoding != nil
+}
+
+// userMetadata returns the user metadata to be set to the uploading object.
+func (m *Manager) userMetadata() map[string]*string {
+	if len(m.metadata) == 0 {
+		return nil
+	}
+	md := make(map[string]*string, len(m.metadata))
+	for k, v := range m.metadata {
+		md[k] = aws.String(v)
+	}
+	return md
+}
+
+// replaceCopyMetadata sets the metadata of the source object overwritten by
+// the metadata options to the copy input.
+// Since CopyObject can't partially update the metadata, all of the source metadata
+// are read by HeadObject and copied with REPLACE directive.
+// The version of the source object is tracked if WithVersionTracking is given.
+func (m *Manager) replaceCopyMetadata(ctx context.Context, in *s3.CopyObjectInput, sourceBucket, sourceKey string) error {
+	head, err := m.regionalClient(ctx, m.sourceClient(), sourceBucket).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(sourceBucket),
+		Key:                  aws.String(sourceKey),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return err
+	}
+	md := head.Metadata
+	if md == nil {
+		md = make(map[string]*string)
+	}
+	for k, v := range m.userMetadata() {
+		md[k] = v
+	}
+	in.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+	in.Metadata = md
+	if m.versionTracking {
+		trackVersion(in, head)
+	}
+	in.CacheControl = head.CacheControl
+	in.ContentDisposition = head.ContentDisposition
+	in.ContentEncoding = head.ContentEncoding
+	in.ContentLanguage = head.ContentLanguage
+	in.ContentType = head.ContentType
+	if head.Expires != nil {
+		if t, err := http.ParseTime(*head.Expires); err == nil {
+			in.Expires = &t
+		}
+	}
+	if m.cacheControl != nil {
+		in.CacheControl = m.cacheControl
+	}
+	if m.contentEncoding != nil {
+		in.ContentEncoding = m.contentEncoding
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyMetadataS3 struct {
+	s3iface.S3API
+	head   *s3.HeadObjectOutput
+	copied *s3.CopyObjectInput
+}
+
+func (s *dummyMetadataS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	return s.head, nil
+}
+
+func (s *dummyMetadataS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	s.copied = in
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func TestCopyMetadata(t *testing.T) {
+	head := &s3.HeadObjectOutput{
+		Metadata: map[string]*string{
+			"Foo": aws.String("source"),
+			"Bar": aws.String("source"),
+		},
+		CacheControl: aws.String("no-cache"),
+		ContentType:  aws.String("text/plain"),
+	}
+	copyFile := func(t *testing.T, opts ...Option) *s3.CopyObjectInput {
+		s := &dummyMetadataS3{head: head}
+		m := New(session.New(), opts...)
+		m.s3 = s
+		err := m.copyS3ToS3(context.Background(),
+			&fileInfo{name: "foo", size: 3},
+			&s3Path{bucket: "src"},
+			&s3Path{bucket: "dst"},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s.copied
+	}
+
+	t.Run("Copy", func(t *testing.T) {
+		in := copyFile(t)
+		if in.MetadataDirective != nil || in.Metadata != nil {
+			t.Errorf("Metadata must be copied by S3, got %v %v", in.MetadataDirective, in.Metadata)
+		}
+	})
+	t.Run("Replace", func(t *testing.T) {
+		in := copyFile(t,
+			WithMetadata(map[string]string{"Foo": "option"}),
+			WithContentEncoding("gzip"),
+		)
+		if aws.StringValue(in.MetadataDirective) != s3.MetadataDirectiveReplace {
+			t.Errorf("Expected REPLACE directive, got %v", in.MetadataDirective)
+		}
+		if v := aws.StringValue(in.Metadata["Foo"]); v != "option" {
+			t.Errorf("Expected overwritten metadata, got %s", v)
+		}
+		if v := aws.StringValue(in.Metadata["Bar"]); v != "source" {
+			t.Errorf("Expected source metadata, got %s", v)
+		}
+		if v := aws.StringValue(in.CacheControl); v != "no-cache" {
+			t.Errorf("Expected source Cache-Control, got %s", v)
+		}
+		if v := aws.StringValue(in.ContentEncoding); v != "gzip" {
+			t.Errorf("Expected Content-Encoding from the option, got %s", v)
+		}
+		if v := aws.StringValue(in.ContentType); v != "text/plain" {
+			t.Errorf("Expected source Content-Type, got %s", v)
+		}
+	})
+}
@@ -0,0 +1,484 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var errMigrateNotS3 = errors.New("source and destination of the migration must be s3 url")
+
+// MigrateOption is a functional option type of Migrate.
+type MigrateOption func(*migrateConfig)
+
+type migrateConfig struct {
+	stateFile string
+}
+
+// WithMigrationStateFile persists the progress of the migration to the given local file.
+// If the file exists, the migration resumes skipping the objects already migrated.
+func WithMigrationStateFile(path string) MigrateOption {
+	return func(c *migrateConfig) {
+		c.stateFile = path
+	}
+}
+
+// MigrationReport is the reconciliation report of the migration.
+type MigrationReport struct {
+	// SourceObjects is the number of the objects listed on the source.
+	SourceObjects int64
+	// Copied is the number of the objects copied and verified.
+	Copied int64
+	// Skipped is the number of the objects already migrated.
+	Skipped int64
+	// Failed is the names of the objects failed to be copied or verified.
+	Failed []string
+	// Missing is the names of the source objects not found on the destination
+	// after the migration.
+	Missing []string
+	// Mismatched is the names of the source objects having different size on
+	// the destination after the migration.
+	Mismatched []string
+}
+
+// Migrate copies all objects under the source s3 url to the destination s3 url
+// for one-time bucket migration.
+// Source listing is sharded by the first level prefixes and run in parallel,
+// and filtered by the filters, sizes and modified times of the Manager like Sync.
+// Each copied object is verified, and the destination is reconciled with the
+// source after copying.
+func (m *Manager) Migrate(ctx context.Context, source, dest string, opts ...MigrateOption) (report *MigrationReport, err error) {
+	ctx, done := m.trackCompletion(ctx, source, dest)
+	defer done(&err)
+
+	c := &migrateConfig{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	sourcePath, err := parseS3URL(source)
+	if err != nil {
+		return nil, err
+	}
+	sourcePath.source = true
+	destPath, err := parseS3URL(dest)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkPreset(); err != nil {
+		return nil, err
+	}
+	if err := m.checkDirection(true, true); err != nil {
+		return nil, err
+	}
+	if err := m.checkBackup(true); err != nil {
+		return nil, err
+	}
+
+	state, err := openMigrationState(c.stateFile)
+	if err != nil {
+		return nil, err
+	}
+	defer state.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = m.withErrorPolicy(ctx, cancel)
+	ctx = context.WithValue(ctx, migrationKey{}, true)
+
+	m.progress.reset(m.expectedFiles, m.expectedBytes)
+
+	workers, stopWorkers := m.startWorkers()
+	defer stopWorkers()
+
+	report = &MigrationReport{}
+	var mu sync.Mutex
+	sourceFiles := make(map[string]*fileInfo)
+	listed := make(chan *fileInfo)
+	go func() {
+		defer close(listed)
+		for fi := range m.filterSizes(ctx, m.mapDestKeys(ctx, m.progress.trackListing(ctx, m.markNotReady(ctx, m.applyFilters(ctx, m.listS3FilesSharded(ctx, sourcePath)))))) {
+			if fi.err == nil && !fi.postponed {
+				mu.Lock()
+				sourceFiles[fi.name] = fi
+				report.SourceObjects++
+				mu.Unlock()
+				if state.IsDone(fi.name) {
+					m.skipped(ctx)(fi)
+					continue
+				}
+			}
+			select {
+			case listed <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	errs := newSyncErrors(ctx)
+	listFailed := false
+	var diff chan *fileOp
+	if destFiles := m.filterSizes(ctx, m.applyDestFilters(ctx, m.listDestS3Files(ctx, destPath, nil))); destFiles != nil {
+		diff = filterFilesForSync(listed, destFiles, false, m.fileComparator(), m.skipped(ctx))
+	} else {
+		diff = seedFilesForSync(listed, m.skipped(ctx))
+	}
+	for file := range diff {
+		if file.err != nil {
+			errs.Append(file.err)
+			listFailed = true
+			continue
+		}
+		if file.op != opUpdate {
+			continue
+		}
+		m.queued(ctx, file)
+		wg.Add(1)
+		file := file
+		workers.run(file.size, func() {
+			defer wg.Done()
+			defer m.progress.processed(file.size)
+			err := m.retry(ctx, func(ctx context.Context) error {
+				return m.copyS3ToS3(ctx, file.fileInfo, sourcePath, destPath)
+			})
+			if err == nil && !m.dryrun {
+				err = m.verifyCopy(ctx, file.fileInfo, sourcePath, destPath)
+			}
+			if err == nil && !m.dryrun {
+				err = state.MarkDone(file.name)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs.Append(err)
+				report.Failed = append(report.Failed, file.name)
+				return
+			}
+			report.Copied++
+		})
+	}
+	wg.Wait()
+
+	report.Skipped = report.SourceObjects - report.Copied - int64(len(report.Failed))
+	sort.Strings(report.Failed)
+
+	if listFailed {
+		return report, errs
+	}
+	if !m.dryrun {
+		if err := m.reconcile(ctx, report, sourceFiles, destPath); err != nil {
+			errs.Append(err)
+		}
+	}
+	return report, errs.ErrOrNil()
+}
+
+func parseS3URL(s string) (*s3Path, error) {
+	u, err := parseURL(s)
+	if err != nil {
+		return nil, err
+	}
+	if !isS3URL(u) {
+		return nil, errMigrateNotS3
+	}
+	return urlToS3Path(u)
+}
+
+// listS3FilesSharded returns a channel which receives the file infos under the given s3Path.
+// The objects under each first level prefix are listed in parallel.
+// The listing is not sharded with WithKeyRange, WithMaxDepth or the ignore rules,
+// since they apply to the names relative to the root, not to the shards.
+func (m *Manager) listS3FilesSharded(ctx context.Context, path *s3Path) chan *fileInfo {
+	root := *path
+	if root.bucketPrefix != "" && !strings.HasSuffix(root.bucketPrefix, "/") {
+		root.bucketPrefix += "/"
+	}
+	_, hasRules := ctx.Value(ignoreRulesKey{}).([]ignoreRule)
+	if m.keyRangeStart != "" || m.keyRangeEnd != "" || m.maxDepth > 0 || m.ignoreHidden || hasRules {
+		return m.listS3Files(ctx, &root, nil)
+	}
+
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+
+		var shards []string
+		var token *string
+		for {
+			list, err := m.client(ctx, &root).ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+				Bucket:            aws.String(root.bucket),
+				Prefix:            aws.String(root.bucketPrefix),
+				Delimiter:         aws.String("/"),
+				ContinuationToken: token,
+			})
+			if err != nil {
+				sendErrorInfoToChannel(ctx, c, err)
+				return
+			}
+			for _, prefix := range list.CommonPrefixes {
+				shards = append(shards, aws.StringValue(prefix.Prefix))
+			}
+			for _, object := range list.Contents {
+				key := aws.StringValue(object.Key)
+				if strings.HasSuffix(key, "/") {
+					// Skip directory like object
+					continue
+				}
+				fi := &fileInfo{
+					name:         strings.TrimPrefix(key, root.bucketPrefix),
+					path:         key,
+					size:         aws.Int64Value(object.Size),
+					lastModified: aws.TimeValue(object.LastModified),
+					etag:         aws.StringValue(object.ETag),
+				}
+				select {
+				case c <- fi:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if token = list.NextContinuationToken; token == nil {
+				break
+			}
+		}
+
+		var wg sync.WaitGroup
+		chShard := make(chan string)
+		for i := 0; i < m.nJobs; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for shard := range chShard {
+					relPrefix := strings.TrimPrefix(shard, root.bucketPrefix)
+					for fi := range m.listS3Files(ctx, &s3Path{bucket: root.bucket, bucketPrefix: shard, source: root.source}, nil) {
+						if fi.err == nil {
+							fi.name = relPrefix + fi.name
+						}
+						select {
+						case c <- fi:
+						case <-ctx.Done():
+						}
+					}
+				}
+			}()
+		}
+		for _, shard := range shards {
+			select {
+			case chShard <- shard:
+			case <-ctx.Done():
+			}
+		}
+		close(chShard)
+		wg.Wait()
+	}()
+
+	return c
+}
+
+// migrationKey is the context key of Migrate, which copies the objects
+// with the SHA-256 checksum to verify them.
+type migrationKey struct{}
+
+// copyChecksumAlgorithm returns the checksum algorithm of the copied objects.
+func (m *Manager) copyChecksumAlgorithm(ctx context.Context) *string {
+	if _, ok := ctx.Value(migrationKey{}).(bool); ok {
+		return aws.String(s3.ChecksumAlgorithmSha256)
+	}
+	return m.checksumAlgorithm()
+}
+
+// verifyCopy checks that the copied object has the same size and contents as the source.
+// ETag is compared if the source is not a multipart object and the copy is not encrypted
+// by SSE-KMS or SSE-C, since otherwise the ETag is not the MD5 checksum of the contents.
+// If not, the checksum of the copy is compared with the one stored on the source,
+// or calculated from the contents of the source in the same parts as the copy.
+func (m *Manager) verifyCopy(ctx context.Context, file *fileInfo, sourcePath, destPath *s3Path) error {
+	key := objectKey(destPath.bucketPrefix, file.destKeyName())
+	head, err := m.destClient(ctx, destPath.bucket).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(destPath.bucket),
+		Key:                  aws.String(key),
+		ChecksumMode:         aws.String(s3.ChecksumModeEnabled),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return err
+	}
+	if size := aws.Int64Value(head.ContentLength); size != file.size {
+		return fmt.Errorf("verification failed for %s: size %d differs from source %d", key, size, file.size)
+	}
+	if file.etag != "" && !isMultipartETag(file.etag) && !m.sseChangesETag() {
+		if etag := aws.StringValue(head.ETag); etag != file.etag {
+			return fmt.Errorf("verification failed for %s: ETag %s differs from source %s", key, etag, file.etag)
+		}
+		return nil
+	}
+
+	source := m.objectFile(ctx, sourcePath, objectKey(sourcePath.bucketPrefix, file.name), file)
+	algorithm, sum := "", ""
+	sums := headChecksums(head)
+	for _, a := range checksumAlgorithms {
+		if s, ok := sums[a]; ok {
+			algorithm, sum = a, s
+			break
+		}
+	}
+	if sum == "" {
+		// The storage doesn't store the checksum of the copy.
+		algorithm = s3.ChecksumAlgorithmSha256
+		if sum, err = m.objectFile(ctx, destPath, key, file).calcChecksum(algorithm); err != nil {
+			return err
+		}
+	}
+	if !isMultipartETag(sum) {
+		if stored, err := source.checksums(); err == nil && stored[algorithm] == sum {
+			return nil
+		}
+	}
+	same, err := matchChecksum(source, algorithm, sum, m.partSizeForCopy(file.size))
+	switch {
+	case err == errPartsMismatch:
+		return fmt.Errorf("verification failed for %s: %s checksum %s has parts different from the copy", key, algorithm, sum)
+	case err != nil:
+		return err
+	case !same:
+		return fmt.Errorf("verification failed for %s: %s checksum %s differs from source", key, algorithm, sum)
+	}
+	return nil
+}
+
+// objectFile returns the FileInfo of the object to calculate its checksums
+// from the contents or to get the stored ones.
+func (m *Manager) objectFile(ctx context.Context, path *s3Path, key string, file *fileInfo) *FileInfo {
+	return &FileInfo{
+		Name:      file.name,
+		Path:      key,
+		Size:      file.size,
+		checksums: m.objectChecksums(ctx, path, key),
+		open: func() (io.ReadCloser, error) {
+			out, err := m.client(ctx, path).GetObjectWithContext(ctx, &s3.GetObjectInput{
+				Bucket:               aws.String(path.bucket),
+				Key:                  aws.String(key),
+				SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+				SSECustomerKey:       m.sseCustomerKey,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return out.Body, nil
+		},
+		remote: true,
+	}
+}
+
+// reconcile lists the destination and reports the source objects
+// missing or having different size on the destination.
+func (m *Manager) reconcile(ctx context.Context, report *MigrationReport, sourceFiles map[string]*fileInfo, destPath *s3Path) error {
+	destFiles, err := fileInfoChanToMap(m.listS3Files(ctx, destPath, nil))
+	if err != nil {
+		return err
+	}
+	for name, sourceInfo := range sourceFiles {
+		destInfo, ok := destFiles[sourceInfo.destKeyName()]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, name)
+		case destInfo.size != sourceInfo.size:
+			report.Mismatched = append(report.Mismatched, name)
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Mismatched)
+	return nil
+}
+
+// migrationState is the progress of the migration persisted to the local file.
+// Each line of the file is a JSON string of the migrated object name.
+type migrationState struct {
+	mu   sync.Mutex
+	done map[string]bool
+	f    *os.File
+}
+
+// openMigrationState loads the state from the file and opens it to append the progress.
+// Empty path returns the state which is not persisted.
+func openMigrationState(path string) (*migrationState, error) {
+	s := &migrationState{done: make(map[string]bool)}
+	if path == "" {
+		return s, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var name string
+		if err := json.Unmarshal(scanner.Bytes(), &name); err != nil {
+			// Ignore the line partially written on crash.
+			continue
+		}
+		s.done[name] = true
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.f = f
+	return s, nil
+}
+
+func (s *migrationState) IsDone(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done[name]
+}
+
+func (s *migrationState) MarkDone(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done[name] = true
+	if s.f == nil {
+		return nil
+	}
+	b, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(append(b, '\n'))
+	return err
+}
+
+func (s *migrationState) Close() error {
+	if s.f == nil {
+		return nil
+	}
+	return s.f.Close()
+}
@@ -0,0 +1,314 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+func TestMigrationState(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	stateFile := filepath.Join(temp, "state")
+
+	s, err := openMigrationState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo", "bar/baz", "with\nnewline"} {
+		if err := s.MarkDone(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate the line partially written on crash.
+	f, err := os.OpenFile(stateFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(`"partial`)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s, err = openMigrationState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	expected := map[string]bool{"foo": true, "bar/baz": true, "with\nnewline": true}
+	if !reflect.DeepEqual(expected, s.done) {
+		t.Fatalf("Expected %v, got %v", expected, s.done)
+	}
+}
+
+func TestMigrateNotS3(t *testing.T) {
+	_, err := New(getSession()).Migrate(context.Background(), "local/dir", "s3://bucket")
+	if err != errMigrateNotS3 {
+		t.Fatalf("Expected %v, got %v", errMigrateNotS3, err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	stateFile := filepath.Join(temp, "state")
+
+	// The dummy s3 bucket has following files.
+	//
+	// s3://s3-source/
+	// ├── README.md
+	// ├── bar
+	// │   └── baz
+	// │       └── README.md
+	// └── foo
+	//     └── README.md
+	report, err := New(getSession()).Migrate(
+		context.Background(), "s3://s3-source", "s3://s3-migration-destination",
+		WithMigrationStateFile(stateFile),
+	)
+	if err != nil {
+		t.Fatal("Migrate should be successful", err)
+	}
+	expected := &MigrationReport{SourceObjects: 3, Copied: 3}
+	if !reflect.DeepEqual(expected, report) {
+		t.Fatalf("Expected %+v, got %+v", expected, report)
+	}
+
+	objs := listObjectsSorted(t, "s3-migration-destination")
+	if len(objs) != 3 ||
+		objs[0].path != "README.md" ||
+		objs[1].path != "bar/baz/README.md" ||
+		objs[2].path != "foo/README.md" {
+		t.Fatal("Unexpected keys", objs)
+	}
+
+	// Resume from the state.
+	report, err = New(getSession()).Migrate(
+		context.Background(), "s3://s3-source", "s3://s3-migration-destination",
+		WithMigrationStateFile(stateFile),
+	)
+	if err != nil {
+		t.Fatal("Migrate should be successful", err)
+	}
+	expected = &MigrationReport{SourceObjects: 3, Skipped: 3}
+	if !reflect.DeepEqual(expected, report) {
+		t.Fatalf("Expected %+v, got %+v", expected, report)
+	}
+}
+
+func TestListS3FilesSharded_RootFilters(t *testing.T) {
+	keys := []string{
+		"prefix/.git/c", "prefix/a/x", "prefix/b/.hidden", "prefix/b/y", "prefix/c/d/e", "prefix/z",
+	}
+	testCases := map[string]struct {
+		options  []Option
+		expected []string
+	}{
+		"Sharded":      {nil, []string{".git/c", "a/x", "b/.hidden", "b/y", "c/d/e", "z"}},
+		"KeyRange":     {[]Option{WithKeyRange("b", "c")}, []string{"b/.hidden", "b/y"}},
+		"MaxDepth":     {[]Option{WithMaxDepth(1)}, []string{"z"}},
+		"IgnoreHidden": {[]Option{WithIgnoreHidden()}, []string{"a/x", "b/y", "c/d/e", "z"}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), tc.options...)
+			if m.keyRangeStart != "" {
+				// dummyDelimiterS3 doesn't support StartAfter.
+				m.s3 = &dummyKeyRangeS3{keys: keys}
+			} else {
+				m.s3 = &dummyDelimiterS3{dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: keys}}}
+			}
+			var names []string
+			for fi := range m.listS3FilesSharded(context.Background(), &s3Path{bucket: "bucket", bucketPrefix: "prefix", source: true}) {
+				if fi.err != nil {
+					t.Fatal(fi.err)
+				}
+				names = append(names, filepath.ToSlash(fi.name))
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(tc.expected, names) {
+				t.Errorf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestMigrate_Filters(t *testing.T) {
+	keys := []string{"prefix/a/x", "prefix/b/y", "prefix/c.log"}
+	testCases := map[string]struct {
+		options  []Option
+		expected int64
+	}{
+		"NoFilter":      {nil, 3},
+		"Exclude":       {[]Option{WithExclude("*.log")}, 2},
+		"MinSize":       {[]Option{WithMinSize(2)}, 0},
+		"ModifiedAfter": {[]Option{WithModifiedAfter(time.Now())}, 0},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), append(tc.options, WithDryRun())...)
+			m.s3 = &dummyDelimiterS3{dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: keys}}}
+			report, err := m.Migrate(context.Background(), "s3://source/prefix", "s3://dest/prefix")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.SourceObjects != tc.expected {
+				t.Errorf("Expected %d source objects, got %d", tc.expected, report.SourceObjects)
+			}
+		})
+	}
+}
+
+type dummyVerifyCopyS3 struct {
+	s3iface.S3API
+	heads    map[string]*s3.HeadObjectOutput
+	contents map[string]string
+}
+
+func (s *dummyVerifyCopyS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	if aws.StringValue(in.ChecksumMode) != s3.ChecksumModeEnabled {
+		return nil, errors.New("checksum mode must be enabled")
+	}
+	if head, ok := s.heads[aws.StringValue(in.Bucket)]; ok {
+		return head, nil
+	}
+	return &s3.HeadObjectOutput{ContentLength: aws.Int64(1)}, nil
+}
+
+func (s *dummyVerifyCopyS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	body, ok := s.contents[aws.StringValue(in.Bucket)]
+	if !ok {
+		return nil, errors.New("unexpected read")
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestVerifyCopy(t *testing.T) {
+	partSum := sha256.Sum256([]byte("a"))
+	sum := sha256.Sum256(partSum[:])
+	partChecksum := base64.StdEncoding.EncodeToString(sum[:]) + "-1"
+
+	testCases := map[string]struct {
+		etag     string
+		sse      bool
+		heads    map[string]*s3.HeadObjectOutput
+		contents map[string]string
+		err      bool
+	}{
+		"ETag": {
+			etag:  md5ETag("a"),
+			heads: map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(1), ETag: aws.String(md5ETag("a"))}},
+		},
+		"ETagMismatch": {
+			etag:  md5ETag("a"),
+			heads: map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(1), ETag: aws.String(md5ETag("b"))}},
+			err:   true,
+		},
+		"SizeMismatch": {
+			etag:  md5ETag("a"),
+			heads: map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(2), ETag: aws.String(md5ETag("a"))}},
+			err:   true,
+		},
+		"StoredChecksum": {
+			etag: `"etag-2"`,
+			heads: map[string]*s3.HeadObjectOutput{
+				"source": {ContentLength: aws.Int64(1), ChecksumSHA256: aws.String(sha256Checksum("a"))},
+				"dest":   {ContentLength: aws.Int64(1), ChecksumSHA256: aws.String(sha256Checksum("a"))},
+			},
+		},
+		"Multipart": {
+			etag:     `"etag-2"`,
+			heads:    map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(1), ChecksumSHA256: aws.String(sha256Checksum("a"))}},
+			contents: map[string]string{"source": "a"},
+		},
+		"MultipartMismatch": {
+			etag:     `"etag-2"`,
+			heads:    map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(1), ChecksumSHA256: aws.String(sha256Checksum("b"))}},
+			contents: map[string]string{"source": "a"},
+			err:      true,
+		},
+		"PartChecksum": {
+			etag:     `"etag-2"`,
+			heads:    map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(1), ChecksumSHA256: aws.String(partChecksum)}},
+			contents: map[string]string{"source": "a"},
+		},
+		"SSEKMS": {
+			etag:     md5ETag("a"),
+			sse:      true,
+			heads:    map[string]*s3.HeadObjectOutput{"dest": {ContentLength: aws.Int64(1), ETag: aws.String(md5ETag("a")), ChecksumSHA256: aws.String(sha256Checksum("b"))}},
+			contents: map[string]string{"source": "a"},
+			err:      true,
+		},
+		"NoChecksum": {
+			etag:     `"etag-2"`,
+			contents: map[string]string{"source": "a", "dest": "a"},
+		},
+		"NoChecksumMismatch": {
+			etag:     `"etag-2"`,
+			contents: map[string]string{"source": "a", "dest": "b"},
+			err:      true,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var options []Option
+			if tc.sse {
+				options = append(options, WithSSE(s3.ServerSideEncryptionAwsKms))
+			}
+			m := New(session.New(), options...)
+			m.s3 = &dummyVerifyCopyS3{heads: tc.heads, contents: tc.contents}
+			err := m.verifyCopy(context.Background(),
+				&fileInfo{name: "a", size: 1, etag: tc.etag},
+				&s3Path{bucket: "source", bucketPrefix: "prefix", source: true},
+				&s3Path{bucket: "dest", bucketPrefix: "prefix"},
+			)
+			if tc.err != (err != nil) {
+				t.Errorf("Expected error: %v, got: %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var errCleanupNotS3 = errors.New("target of the incomplete upload cleanup must be s3 url")
+
+// CleanupIncompleteUploads aborts the multipart uploads under the given s3 url
+// initiated before olderThan ago, and returns the number of the aborted uploads.
+// Parts of the incomplete uploads left by crashed runs are charged as storage
+// but invisible in the object listing.
+func (m *Manager) CleanupIncompleteUploads(ctx context.Context, s3url string, olderThan time.Duration) (int, error) {
+	u, err := parseURL(s3url)
+	if err != nil {
+		return 0, err
+	}
+	if !isS3URL(u) {
+		return 0, errCleanupNotS3
+	}
+	path, err := urlToS3Path(u)
+	if err != nil {
+		return 0, err
+	}
+	return m.cleanupIncompleteUploads(ctx, path, olderThan)
+}
+
+// cleanupBeforeSync aborts the stale uploads under the destination if enabled.
+func (m *Manager) cleanupBeforeSync(ctx context.Context, destPath *s3Path) error {
+	if m.cleanupOlderThan <= 0 || m.readOnly {
+		return nil
+	}
+	_, err := m.cleanupIncompleteUploads(ctx, destPath, m.cleanupOlderThan)
+	return err
+}
+
+func (m *Manager) cleanupIncompleteUploads(ctx context.Context, path *s3Path, olderThan time.Duration) (int, error) {
+	if err := m.refuseIfReadOnly("aborting uploads under", path.String()); err != nil {
+		return 0, err
+	}
+	deadline := time.Now().Add(-olderThan)
+	var n int
+	var keyMarker, uploadIDMarker *string
+	for {
+		list, err := m.destClient(ctx, path.bucket).ListMultipartUploadsWithContext(ctx, &s3.ListMultipartUploadsInput{
+			Bucket:         aws.String(path.bucket),
+			Prefix:         aws.String(path.bucketPrefix),
+			KeyMarker:      keyMarker,
+			UploadIdMarker: uploadIDMarker,
+		})
+		if err != nil {
+			return n, err
+		}
+		for _, upload := range list.Uploads {
+			if !aws.TimeValue(upload.Initiated).Before(deadline) {
+				continue
+			}
+			println("Aborting incomplete upload of", aws.StringValue(upload.Key), "initiated at", aws.TimeValue(upload.Initiated).String())
+			if m.isDryRun(ctx) {
+				continue
+			}
+			if _, err := m.destClient(ctx, path.bucket).AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(path.bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			}); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if !aws.BoolValue(list.IsTruncated) {
+			return n, nil
+		}
+		keyMarker, uploadIDMarker = list.NextKeyMarker, list.NextUploadIdMarker
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyMultipartS3 struct {
+	s3iface.S3API
+	pages   [][]*s3.MultipartUpload
+	aborted []string
+}
+
+func (s *dummyMultipartS3) ListMultipartUploadsWithContext(ctx aws.Context, in *s3.ListMultipartUploadsInput, opts ...request.Option) (*s3.ListMultipartUploadsOutput, error) {
+	page := 0
+	if in.KeyMarker != nil {
+		page = 1
+	}
+	return &s3.ListMultipartUploadsOutput{
+		Uploads:            s.pages[page],
+		IsTruncated:        aws.Bool(page+1 < len(s.pages)),
+		NextKeyMarker:      aws.String("marker"),
+		NextUploadIdMarker: aws.String("marker"),
+	}, nil
+}
+
+func (s *dummyMultipartS3) AbortMultipartUploadWithContext(ctx aws.Context, in *s3.AbortMultipartUploadInput, opts ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	s.aborted = append(s.aborted, *in.UploadId)
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (s *dummyMultipartS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	return &s3.ListObjectsV2Output{}, nil
+}
+
+func newDummyMultipartS3() *dummyMultipartS3 {
+	old := aws.Time(time.Now().Add(-48 * time.Hour))
+	recent := aws.Time(time.Now())
+	return &dummyMultipartS3{pages: [][]*s3.MultipartUpload{
+		{
+			{Key: aws.String("prefix/a"), UploadId: aws.String("old1"), Initiated: old},
+			{Key: aws.String("prefix/b"), UploadId: aws.String("recent"), Initiated: recent},
+		},
+		{
+			{Key: aws.String("prefix/c"), UploadId: aws.String("old2"), Initiated: old},
+		},
+	}}
+}
+
+func TestCleanupIncompleteUploads(t *testing.T) {
+	t.Run("Cleanup", func(t *testing.T) {
+		s := newDummyMultipartS3()
+		m := New(session.New())
+		m.s3 = s
+		n, err := m.CleanupIncompleteUploads(context.Background(), "s3://bucket/prefix", 24*time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"old1", "old2"}; n != 2 || !reflect.DeepEqual(expected, s.aborted) {
+			t.Errorf("Expected %v to be aborted, got %d %v", expected, n, s.aborted)
+		}
+	})
+	t.Run("DryRun", func(t *testing.T) {
+		s := newDummyMultipartS3()
+		m := New(session.New(), WithDryRun())
+		m.s3 = s
+		if _, err := m.CleanupIncompleteUploads(context.Background(), "s3://bucket/prefix", 24*time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		if len(s.aborted) != 0 {
+			t.Errorf("Uploads must not be aborted in dry run, got %v", s.aborted)
+		}
+	})
+	t.Run("NotS3", func(t *testing.T) {
+		if _, err := New(session.New()).CleanupIncompleteUploads(context.Background(), "local/path", time.Hour); err != errCleanupNotS3 {
+			t.Errorf("Expected %v, got %v", errCleanupNotS3, err)
+		}
+	})
+	t.Run("BeforeSync", func(t *testing.T) {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+
+		s := newDummyMultipartS3()
+		m := New(session.New(), WithIncompleteUploadCleanup(24*time.Hour))
+		m.s3 = s
+		if err := m.Sync(context.Background(), temp, "s3://bucket/prefix"); err != nil {
+			t.Fatal(err)
+		}
+		if len(s.aborted) != 2 {
+			t.Errorf("Expected 2 uploads to be aborted, got %v", s.aborted)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// NameCodec encodes the names of the local files to the object keys, e.g. to hash or
+// encrypt the paths. The names and keys are slash separated and relative to the
+// local directory and the bucket prefix.
+type NameCodec interface {
+	// Encode returns the object key of the name.
+	Encode(name string) (string, error)
+	// Decode returns the name of the object key. It may return an error if the key
+	// can't be decoded, e.g. hashed, and the name is recovered from the metadata.
+	Decode(key string) (string, error)
+}
+
+// metadataOriginalName is the metadata key of the original name of the object
+// whose key can't be decoded by the NameCodec.
+const metadataOriginalName = "original-name"
+
+// encodeDestNames returns a channel which receives the given file infos with the names
+// on the destination encoded by the NameCodec.
+func (m *Manager) encodeDestNames(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	if m.nameCodec == nil {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil && !fi.singleFile {
+				name := filepath.ToSlash(fi.destKeyName())
+				key, err := m.nameCodec.Encode(name)
+				if err == nil && key == "" {
+					err = fmt.Errorf("%s is encoded to the empty key", name)
+				}
+				if err != nil {
+					fi = &fileInfo{err: &FileError{Op: "upload", Path: fi.path, Err: err}}
+				} else {
+					fi.destName = filepath.FromSlash(key)
+					fi.originalName = name
+				}
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// decodeSourceNames returns a channel which receives the given file infos of the objects
+// with the names decoded by the NameCodec. The name is read from the metadata of
+// the object if the NameCodec can't decode the key.
+func (m *Manager) decodeSourceNames(ctx context.Context, files chan *fileInfo, path *s3Path) chan *fileInfo {
+	if m.nameCodec == nil {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil && !fi.singleFile {
+				key := sourceObjectKey(fi, path)
+				if name, err := m.decodeName(ctx, path, key, filepath.ToSlash(fi.name)); err != nil {
+					fi = &fileInfo{err: &FileError{Op: "download", Key: key, Err: err}}
+				} else {
+					fi.key = key
+					fi.name = filepath.FromSlash(name)
+				}
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// decodeDestNames returns a channel which receives the given file infos of the destination
+// objects with the original names decoded by the NameCodec, to be matched by the filters.
+func (m *Manager) decodeDestNames(ctx context.Context, files chan *fileInfo, path *s3Path) chan *fileInfo {
+	if m.nameCodec == nil || len(m.filters) == 0 || files == nil {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil && !fi.singleFile {
+				key := sourceObjectKey(fi, path)
+				if name, err := m.decodeName(ctx, path, key, filepath.ToSlash(fi.name)); err != nil {
+					fi = &fileInfo{err: &FileError{Op: "list", Key: key, Err: err}}
+				} else {
+					fi.originalName = name
+				}
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// nameMetadata returns the metadata value of the original name of the file,
+// or nil if the NameCodec can decode the key.
+func (m *Manager) nameMetadata(file *fileInfo) *string {
+	if file.originalName == "" {
+		return nil
+	}
+	if name, err := m.nameCodec.Decode(filepath.ToSlash(file.destKeyName())); err == nil && name == file.originalName {
+		return nil
+	}
+	return aws.String(url.PathEscape(file.originalName))
+}
+
+// decodeName returns the name of the object, decoded from the relative key or read from the metadata.
+func (m *Manager) decodeName(ctx context.Context, path *s3Path, key, rel string) (string, error) {
+	name, err := m.nameCodec.Decode(rel)
+	if err == nil {
+		return name, nil
+	}
+	head, herr := m.client(ctx, path).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(path.bucket),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if herr != nil {
+		return "", herr
+	}
+	v, ok := metadataValue(head.Metadata, metadataOriginalName)
+	if !ok {
+		return "", fmt.Errorf("can't decode the name: %w", err)
+	}
+	return url.PathUnescape(v)
+}
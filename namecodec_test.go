@@ -0,0 +1,232 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// hexNameCodec encodes each segment of the name to hex.
+type hexNameCodec struct{}
+
+func (hexNameCodec) Encode(name string) (string, error) {
+	segs := strings.Split(name, "/")
+	for i, s := range segs {
+		segs[i] = hex.EncodeToString([]byte(s))
+	}
+	return strings.Join(segs, "/"), nil
+}
+
+func (hexNameCodec) Decode(key string) (string, error) {
+	segs := strings.Split(key, "/")
+	for i, s := range segs {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return "", err
+		}
+		segs[i] = string(b)
+	}
+	return strings.Join(segs, "/"), nil
+}
+
+// hashNameCodec hashes the name, which can't be decoded.
+type hashNameCodec struct{}
+
+func (hashNameCodec) Encode(name string) (string, error) {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (hashNameCodec) Decode(key string) (string, error) {
+	return "", errors.New("hashed")
+}
+
+func TestNameCodec_Upload(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	for _, name := range []string{"a.txt", "dir/b c.txt"} {
+		filename := filepath.Join(temp, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hashed := func(name string) string {
+		key, _ := hashNameCodec{}.Encode(name)
+		return "prefix/" + key
+	}
+	testCases := map[string]struct {
+		codec    NameCodec
+		expected map[string]string
+	}{
+		"Hex": {
+			codec: hexNameCodec{},
+			expected: map[string]string{
+				"prefix/612e747874":            "",
+				"prefix/646972/6220632e747874": "",
+			},
+		},
+		"Hash": {
+			codec: hashNameCodec{},
+			expected: map[string]string{
+				hashed("a.txt"):       "a.txt",
+				hashed("dir/b c.txt"): "dir%2Fb%20c.txt",
+			},
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			put := make(map[string]string)
+			c := s3.New(session.New(), aws.NewConfig().
+				WithRegion("us-east-1").
+				WithCredentials(credentials.AnonymousCredentials))
+			c.Handlers.Send.Clear()
+			c.Handlers.Unmarshal.Clear()
+			c.Handlers.Send.PushBack(func(r *request.Request) {
+				if in, ok := r.Params.(*s3.PutObjectInput); ok {
+					mu.Lock()
+					v, _ := metadataValue(in.Metadata, metadataOriginalName)
+					put[*in.Key] = v
+					mu.Unlock()
+				}
+				r.HTTPResponse = &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}
+			})
+
+			m := New(session.New(), WithNameCodec(tc.codec))
+			m.s3 = c
+			if err := m.Sync(context.Background(), temp, "s3://bucket/prefix"); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.expected, put) {
+				t.Errorf("Expected %v to be put, got %v", tc.expected, put)
+			}
+		})
+	}
+}
+
+type dummyCodecS3 struct {
+	dummyBudgetS3
+	metadata map[string]string
+}
+
+func (s *dummyCodecS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	out := &s3.HeadObjectOutput{Metadata: map[string]*string{}}
+	if v, ok := s.metadata[*in.Key]; ok {
+		out.Metadata["Original-Name"] = aws.String(v)
+	}
+	return out, nil
+}
+
+func TestNameCodec_Download(t *testing.T) {
+	hashed := func(name string) string {
+		key, _ := hashNameCodec{}.Encode(name)
+		return "prefix/" + key
+	}
+	testCases := map[string]struct {
+		codec    NameCodec
+		keys     []string
+		metadata map[string]string
+		files    []string
+		err      bool
+	}{
+		"Hex": {
+			codec: hexNameCodec{},
+			keys:  []string{"prefix/612e747874", "prefix/646972/6220632e747874"},
+			files: []string{"a.txt", "dir/b c.txt"},
+		},
+		"Hash": {
+			codec: hashNameCodec{},
+			keys:  []string{hashed("a.txt"), hashed("dir/b c.txt")},
+			metadata: map[string]string{
+				hashed("a.txt"):       "a.txt",
+				hashed("dir/b c.txt"): "dir%2Fb%20c.txt",
+			},
+			files: []string{"a.txt", "dir/b c.txt"},
+		},
+		"NoMetadata": {
+			codec:    hashNameCodec{},
+			keys:     []string{hashed("a.txt"), hashed("dir/b c.txt")},
+			metadata: map[string]string{hashed("a.txt"): "a.txt"},
+			files:    []string{"a.txt"},
+			err:      true,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			keys := append([]string(nil), tc.keys...)
+			sort.Strings(keys)
+			s := &dummyCodecS3{
+				dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: keys}},
+				metadata:      tc.metadata,
+			}
+			m := New(session.New(), WithNameCodec(tc.codec))
+			m.s3 = s
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			if tc.err != (err != nil) {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+
+			var files []string
+			if err := filepath.Walk(temp, func(path string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					rel, _ := filepath.Rel(temp, path)
+					files = append(files, filepath.ToSlash(rel))
+				}
+				return err
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.files, files) {
+				t.Errorf("Expected %v, got %v", tc.files, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+// Notifier is the interface to publish the summary of each sync.
+// The context is the one of the sync.
+type Notifier interface {
+	Notify(context.Context, SyncSummary) error
+}
+
+// SyncSummary is the summary of a sync published by Notifier.
+type SyncSummary struct {
+	Source          string  `json:"source"`
+	Dest            string  `json:"dest"`
+	Files           int64   `json:"files"`
+	Bytes           int64   `json:"bytes"`
+	DeletedFiles    int64   `json:"deletedFiles"`
+	SkippedFiles    int64   `json:"skippedFiles"`
+	Failures        int     `json:"failures"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// Summary returns the summary of the sync result.
+func (r SyncResult) Summary() SyncSummary {
+	s := SyncSummary{
+		Source:          r.Source,
+		Dest:            r.Dest,
+		Files:           r.Statistics.Files,
+		Bytes:           r.Statistics.Bytes,
+		DeletedFiles:    r.Statistics.DeletedFiles,
+		SkippedFiles:    r.Statistics.SkippedFiles,
+		DurationSeconds: r.EndTime.Sub(r.StartTime).Seconds(),
+	}
+	if r.Err != nil {
+		s.Error = r.Err.Error()
+		// The sync failed without a file failure, e.g. on the listing.
+		if s.Failures = int(r.Statistics.FailedFiles); s.Failures == 0 {
+			s.Failures = 1
+		}
+	}
+	return s
+}
+
+// SNSNotifier publishes the sync summary to the SNS topic as a JSON message.
+type SNSNotifier struct {
+	sns      snsiface.SNSAPI
+	topicARN string
+}
+
+// NewSNSNotifier returns a new SNSNotifier.
+func NewSNSNotifier(client snsiface.SNSAPI, topicARN string) *SNSNotifier {
+	return &SNSNotifier{
+		sns:      client,
+		topicARN: topicARN,
+	}
+}
+
+// maxSNSSubject is the maximum length of the SNS message subject.
+const maxSNSSubject = 100
+
+// Notify implements Notifier.
+func (n *SNSNotifier) Notify(ctx context.Context, s SyncSummary) error {
+	msg, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = n.sns.PublishWithContext(ctx, &sns.PublishInput{
+		TopicArn: aws.String(n.topicARN),
+		Subject:  aws.String(snsSubject("s3sync: " + s.Source + " to " + s.Dest)),
+		Message:  aws.String(string(msg)),
+	})
+	return err
+}
+
+// snsSubject returns the subject accepted by SNS, which must be printable ASCII
+// characters up to maxSNSSubject. The other characters are replaced by "?",
+// and the long subject is truncated with "...".
+func snsSubject(subject string) string {
+	b := []byte(subject)
+	for i, c := range b {
+		if c < ' ' || c > '~' {
+			b[i] = '?'
+		}
+	}
+	if len(b) > maxSNSSubject {
+		b = append(b[:maxSNSSubject-3], "..."...)
+	}
+	return string(b)
+}
+
+// CloudWatchNotifier publishes the sync summary to CloudWatch as the custom metrics:
+// TransferredBytes, TransferredFiles, DeletedFiles, SkippedFiles, Failures and Duration.
+type CloudWatchNotifier struct {
+	cw         cloudwatchiface.CloudWatchAPI
+	namespace  string
+	dimensions []*cloudwatch.Dimension
+}
+
+// NewCloudWatchNotifier returns a new CloudWatchNotifier publishing the metrics
+// to the given namespace with the given dimensions.
+func NewCloudWatchNotifier(client cloudwatchiface.CloudWatchAPI, namespace string, dimensions map[string]string) *CloudWatchNotifier {
+	n := &CloudWatchNotifier{
+		cw:        client,
+		namespace: namespace,
+	}
+	for k, v := range dimensions {
+		n.dimensions = append(n.dimensions, &cloudwatch.Dimension{
+			Name:  aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	return n
+}
+
+// Notify implements Notifier.
+func (n *CloudWatchNotifier) Notify(ctx context.Context, s SyncSummary) error {
+	now := time.Now()
+	datum := func(name string, value float64, unit string) *cloudwatch.MetricDatum {
+		return &cloudwatch.MetricDatum{
+			MetricName: aws.String(name),
+			Dimensions: n.dimensions,
+			Timestamp:  aws.Time(now),
+			Value:      aws.Float64(value),
+			Unit:       aws.String(unit),
+		}
+	}
+	_, err := n.cw.PutMetricDataWithContext(ctx, &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(n.namespace),
+		MetricData: []*cloudwatch.MetricDatum{
+			datum("TransferredBytes", float64(s.Bytes), cloudwatch.StandardUnitBytes),
+			datum("TransferredFiles", float64(s.Files), cloudwatch.StandardUnitCount),
+			datum("DeletedFiles", float64(s.DeletedFiles), cloudwatch.StandardUnitCount),
+			datum("SkippedFiles", float64(s.SkippedFiles), cloudwatch.StandardUnitCount),
+			datum("Failures", float64(s.Failures), cloudwatch.StandardUnitCount),
+			datum("Duration", s.DurationSeconds, cloudwatch.StandardUnitSeconds),
+		},
+	})
+	return err
+}
+
+// DefaultWebhookTimeout is the default timeout of the webhook request.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts the sync summary to the HTTP endpoint as a JSON body.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a new WebhookNotifier.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: DefaultWebhookTimeout},
+	}
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, s SyncSummary) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", res.Status)
+	}
+	return nil
+}
+
+// DefaultNotifyTimeout is the timeout of the notifications of a sync result.
+const DefaultNotifyTimeout = 30 * time.Second
+
+// detachedContext is the context keeping the values of the parent without its
+// cancellation and deadline.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c detachedContext) Done() <-chan struct{}             { return nil }
+func (c detachedContext) Err() error                        { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+// notify publishes the summary of the sync result to all notifiers.
+// Errors are only logged since the sync itself has already finished.
+// The notifiers are not canceled with the sync, so that the canceled and
+// timed-out syncs are also notified, and are given DefaultNotifyTimeout instead.
+func (m *Manager) notify(ctx context.Context, r SyncResult) {
+	ctx, cancel := context.WithTimeout(detachedContext{parent: ctx}, DefaultNotifyTimeout)
+	defer cancel()
+	s := r.Summary()
+	for _, n := range m.notifiers {
+		if err := n.Notify(ctx, s); err != nil {
+			println("Failed to notify:", err)
+		}
+	}
+}
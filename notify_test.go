@@ -0,0 +1,214 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+type dummySNS struct {
+	snsiface.SNSAPI
+	inputs []*sns.PublishInput
+}
+
+func (s *dummySNS) PublishWithContext(ctx aws.Context, in *sns.PublishInput, opts ...request.Option) (*sns.PublishOutput, error) {
+	s.inputs = append(s.inputs, in)
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSyncResult_Summary(t *testing.T) {
+	t0 := time.Now()
+	errs := &multiErr{}
+	errs.Append(errors.New("error1"))
+	errs.Append(errors.New("error2"))
+
+	s := SyncResult{
+		Source:     "s3://bucket",
+		Dest:       "dest",
+		Statistics: SyncStatistics{Bytes: 10, Files: 2, DeletedFiles: 1, SkippedFiles: 3, FailedFiles: 2},
+		StartTime:  t0,
+		EndTime:    t0.Add(2 * time.Second),
+		Err:        fmt.Errorf("wrapped: %w", errs),
+	}.Summary()
+
+	expected := SyncSummary{
+		Source:          "s3://bucket",
+		Dest:            "dest",
+		Files:           2,
+		Bytes:           10,
+		DeletedFiles:    1,
+		SkippedFiles:    3,
+		Failures:        2,
+		DurationSeconds: 2,
+		Error:           "wrapped: error1\nerror2",
+	}
+	if s != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, s)
+	}
+
+	// The sync failed without a file failure.
+	if s := (SyncResult{Err: errors.New("listing")}).Summary(); s.Failures != 1 {
+		t.Errorf("Expected 1 failure, got %d", s.Failures)
+	}
+}
+
+func TestSNSNotifier(t *testing.T) {
+	cli := &dummySNS{}
+	n := NewSNSNotifier(cli, "arn:aws:sns:dummy")
+	if err := n.Notify(context.Background(), SyncSummary{Source: "s3://bucket", Dest: "dest", Files: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(cli.inputs) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(cli.inputs))
+	}
+	if *cli.inputs[0].TopicArn != "arn:aws:sns:dummy" {
+		t.Errorf("Unexpected topic %s", *cli.inputs[0].TopicArn)
+	}
+	var s SyncSummary
+	if err := json.Unmarshal([]byte(*cli.inputs[0].Message), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Files != 1 {
+		t.Errorf("Expected 1 file, got %d", s.Files)
+	}
+
+	if err := n.Notify(context.Background(), SyncSummary{Source: "s3://bucket/" + strings.Repeat("long/", 30), Dest: "dest/\u00e9"}); err != nil {
+		t.Fatal(err)
+	}
+	if subject := *cli.inputs[1].Subject; len(subject) != maxSNSSubject || !strings.HasSuffix(subject, "...") {
+		t.Errorf("Expected the subject truncated to %d characters, got %q", maxSNSSubject, subject)
+	}
+}
+
+func TestSNSSubject(t *testing.T) {
+	if s := snsSubject("s3sync: dir/\u00e9\n to s3://bucket"); s != "s3sync: dir/??? to s3://bucket" {
+		t.Errorf("Unexpected subject %q", s)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var received SyncSummary
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Unexpected content type %s", ct)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Error(err)
+			}
+		}))
+		defer srv.Close()
+
+		if err := NewWebhookNotifier(srv.URL).Notify(context.Background(), SyncSummary{Bytes: 100}); err != nil {
+			t.Fatal(err)
+		}
+		if received.Bytes != 100 {
+			t.Errorf("Expected 100 bytes, got %d", received.Bytes)
+		}
+	})
+	t.Run("ErrorStatus", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		if err := NewWebhookNotifier(srv.URL).Notify(context.Background(), SyncSummary{}); err == nil {
+			t.Fatal("Notify must fail on error status")
+		}
+	})
+}
+
+type ctxErrNotifier struct {
+	err         error
+	hasDeadline bool
+}
+
+func (n *ctxErrNotifier) Notify(ctx context.Context, s SyncSummary) error {
+	n.err = ctx.Err()
+	_, n.hasDeadline = ctx.Deadline()
+	return nil
+}
+
+func TestNotify_CanceledSync(t *testing.T) {
+	n := &ctxErrNotifier{}
+	m := &Manager{notifiers: []Notifier{n}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.notify(ctx, SyncResult{Err: context.Canceled})
+	if n.err != nil {
+		t.Errorf("The notification must not be canceled with the sync, got %v", n.err)
+	}
+	if !n.hasDeadline {
+		t.Error("The notification must have the timeout")
+	}
+}
+
+type dummyMetricsCloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	inputs []*cloudwatch.PutMetricDataInput
+}
+
+func (c *dummyMetricsCloudWatch) PutMetricDataWithContext(ctx aws.Context, in *cloudwatch.PutMetricDataInput, opts ...request.Option) (*cloudwatch.PutMetricDataOutput, error) {
+	c.inputs = append(c.inputs, in)
+	return &cloudwatch.PutMetricDataOutput{}, nil
+}
+
+func TestCloudWatchNotifier(t *testing.T) {
+	cw := &dummyMetricsCloudWatch{}
+	n := NewCloudWatchNotifier(cw, "s3sync", map[string]string{"SyncName": "backup"})
+	if err := n.Notify(context.Background(), SyncSummary{Files: 2, Bytes: 100, SkippedFiles: 3, Failures: 1, DurationSeconds: 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	if len(cw.inputs) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(cw.inputs))
+	}
+	in := cw.inputs[0]
+	if *in.Namespace != "s3sync" {
+		t.Errorf("Unexpected namespace %s", *in.Namespace)
+	}
+	values := make(map[string]float64)
+	for _, d := range in.MetricData {
+		if len(d.Dimensions) != 1 || *d.Dimensions[0].Name != "SyncName" || *d.Dimensions[0].Value != "backup" {
+			t.Errorf("Unexpected dimensions %v", d.Dimensions)
+		}
+		values[*d.MetricName] = *d.Value
+	}
+	expected := map[string]float64{
+		"TransferredBytes": 100,
+		"TransferredFiles": 2,
+		"DeletedFiles":     0,
+		"SkippedFiles":     3,
+		"Failures":         1,
+		"Duration":         1.5,
+	}
+	if !reflect.DeepEqual(expected, values) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// OddKeyPolicy is the policy of the object keys which can't be mapped to the local paths
+// as is, i.e. containing sequential slashes ("a//b"), a leading slash ("/a")
+// or the dot segments ("a/../b").
+type OddKeyPolicy int
+
+const (
+	// OddKeySkip skips downloading the objects of such keys with a warning.
+	// They are synced between the buckets as is regardless of the policy.
+	OddKeySkip OddKeyPolicy = iota
+	// OddKeyEscape maps such keys to the local paths by percent-encoding the slashes
+	// starting the empty segments and the dots of the dot segments, e.g. "a//b" to "a/%2Fb"
+	// and "/a/../b" to "%2Fa/%2E%2E/b", and maps them back on upload.
+	// To keep the mapping lossless, "%" in all of the local file names is
+	// also encoded as "%25", e.g. the object "100%" is downloaded to the file "100%25".
+	OddKeyEscape
+)
+
+// hasOddSegment returns whether the slash separated name has the empty or dot segments.
+func hasOddSegment(name string) bool {
+	for _, seg := range strings.Split(name, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// objectName returns the name of the object relative to the prefix.
+// The names of the odd keys are kept as is, unlike filepath.Rel cleaning them.
+func objectName(prefix, key string) (string, error) {
+	rel := strings.TrimPrefix(key, prefix)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		// The prefix of the directory is followed by the separator.
+		rel = strings.TrimPrefix(rel, "/")
+		if len(rel) != len(key)-len(prefix)-1 {
+			rel = ""
+		}
+	}
+	if strings.HasPrefix(key, prefix) && rel != "" && hasOddSegment(rel) {
+		return rel, nil
+	}
+	return filepath.Rel(prefix, key)
+}
+
+// objectKey returns the key of the object of the name under the prefix.
+// It is the inverse of objectName.
+func objectKey(prefix, name string) string {
+	name = filepath.ToSlash(name)
+	if !hasOddSegment(name) {
+		return filepath.ToSlash(filepath.Join(prefix, name))
+	}
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix + name
+	}
+	return prefix + "/" + name
+}
+
+// escapeLocalName returns the local file name of the object name by OddKeyEscape.
+func escapeLocalName(name string) string {
+	segs := strings.Split(strings.ReplaceAll(name, "%", "%25"), "/")
+	escaped := make([]string, 0, len(segs))
+	var slashes string
+	for i, seg := range segs {
+		if seg == "" && i < len(segs)-1 {
+			// The slash starting the empty segment is merged into the next segment.
+			slashes += "%2F"
+			continue
+		}
+		if seg == "." || seg == ".." {
+			seg = strings.ReplaceAll(seg, ".", "%2E")
+		}
+		escaped = append(escaped, slashes+seg)
+		slashes = ""
+	}
+	return strings.Join(escaped, "/")
+}
+
+// unescapeLocalName returns the object name of the local file name by OddKeyEscape.
+// The name is returned as is if it is not escaped correctly.
+func unescapeLocalName(name string) string {
+	unescaped, err := url.PathUnescape(filepath.ToSlash(name))
+	if err != nil {
+		return name
+	}
+	return filepath.FromSlash(unescaped)
+}
+
+// localFilename returns the path of the local file of the object name under the directory.
+// It returns false if the name can't be mapped to the local path by the odd key policy.
+func (m *Manager) localFilename(dir, name string) (string, bool) {
+	if m.oddKeyPolicy == OddKeyEscape {
+		return filepath.Join(dir, filepath.FromSlash(escapeLocalName(filepath.ToSlash(name)))), true
+	}
+	if hasOddSegment(filepath.ToSlash(name)) {
+		return "", false
+	}
+	return filepath.Join(dir, name), true
+}
+
+// unescapeLocalFiles returns a channel which receives the given local file infos
+// with the names mapped back to the object names by OddKeyEscape.
+func (m *Manager) unescapeLocalFiles(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	if m.oddKeyPolicy != OddKeyEscape {
+		return files
+	}
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil && !fi.singleFile {
+				fi.name = unescapeLocalName(fi.name)
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestObjectName(t *testing.T) {
+	testCases := map[string]struct {
+		prefix, key, name string
+	}{
+		"Normal":       {"prefix", "prefix/a/b", "a/b"},
+		"Single":       {"prefix/a", "prefix/a", "."},
+		"DoubleSlash":  {"prefix", "prefix/a//b", "a//b"},
+		"AfterPrefix":  {"prefix", "prefix//a", "/a"},
+		"SlashPrefix":  {"prefix/", "prefix//a", "/a"},
+		"Leading":      {"", "/a", "/a"},
+		"DotSegments":  {"prefix", "prefix/a/../b", "a/../b"},
+		"NoSeparator":  {"prefix", "prefixed/a", "../prefixed/a"},
+		"NoSeparator2": {"prefix", "prefixed//a", "../prefixed/a"},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			got, err := objectName(tc.prefix, tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != filepath.FromSlash(tc.name) {
+				t.Errorf("Expected %s, got %s", tc.name, got)
+			}
+			if name == "Single" || name == "NoSeparator" || name == "NoSeparator2" {
+				return
+			}
+			if key := objectKey(tc.prefix, got); key != tc.key {
+				t.Errorf("Expected key %s, got %s", tc.key, key)
+			}
+		})
+	}
+}
+
+func TestEscapeLocalName(t *testing.T) {
+	testCases := map[string]string{
+		"a/b":    "a/b",
+		"a//b":   "a/%2Fb",
+		"a///b":  "a/%2F%2Fb",
+		"/a":     "%2Fa",
+		"a/../b": "a/%2E%2E/b",
+		"./a":    "%2E/a",
+		"100%":   "100%25",
+		"a/%2Fb": "a/%252Fb",
+	}
+	for name, expected := range testCases {
+		escaped := escapeLocalName(name)
+		if escaped != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, escaped)
+		}
+		if unescaped := filepath.ToSlash(unescapeLocalName(filepath.FromSlash(escaped))); unescaped != name {
+			t.Errorf("%s: expected to be unescaped to the name, got %s", name, unescaped)
+		}
+	}
+}
+
+func TestOddKeyPolicy(t *testing.T) {
+	keys := []string{"prefix/%", "prefix//b", "prefix/a", "prefix/c/../d"}
+	testCases := map[string]struct {
+		policy     OddKeyPolicy
+		downloaded []string
+		files      []string
+	}{
+		"Skip":   {OddKeySkip, []string{"prefix/%", "prefix/a"}, []string{"%", "a"}},
+		"Escape": {OddKeyEscape, keys, []string{"%25", "%2Fb", "a", "c/%2E%2E/d"}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: keys}}
+			m := New(session.New(), WithOddKeyPolicy(tc.policy))
+			m.s3 = s
+
+			for i := 0; i < 2; i++ {
+				// The second sync finds the files up to date.
+				if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sort.Strings(s.downloaded)
+			if !reflect.DeepEqual(tc.downloaded, s.downloaded) {
+				t.Errorf("Expected downloads %v, got %v", tc.downloaded, s.downloaded)
+			}
+			var files []string
+			if err := filepath.Walk(temp, func(path string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					rel, _ := filepath.Rel(temp, path)
+					files = append(files, filepath.ToSlash(rel))
+				}
+				return err
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.files, files) {
+				t.Errorf("Expected files %v, got %v", tc.files, files)
+			}
+		})
+	}
+}
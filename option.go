@@ -13,7 +13,17 @@
 
 package s3sync
 
-import "github.com/aws/aws-sdk-go/service/s3/s3manager"
+import (
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
 
 const (
 	// Default number of parallel file sync jobs.
@@ -30,6 +40,100 @@ func WithParallel(n int) Option {
 	}
 }
 
+// Executor runs the file sync jobs.
+// *errgroup.Group satisfies this interface.
+type Executor interface {
+	// Go runs the function. It may block until the function can be started.
+	Go(f func() error)
+}
+
+// WithExecutor sets the external executor to run the file sync jobs
+// instead of spawning the workers by the Manager.
+// It allows controlling the total concurrency of the application embedding
+// many Managers. WithParallel is ignored if the executor is set.
+// The jobs never return errors, and the errors are returned from the sync.
+func WithExecutor(e Executor) Option {
+	return func(m *Manager) {
+		m.executor = e
+	}
+}
+
+// WithLargeFileLane dedicates the given number of the workers to the files
+// whose size is threshold or more, and the rest of the workers to the smaller files,
+// so that a few huge files never occupy all of the workers and vice versa.
+// The number of the workers is given by WithParallel.
+// It is ignored if WithExecutor is given.
+func WithLargeFileLane(threshold int64, workers int) Option {
+	return func(m *Manager) {
+		m.largeFileThreshold = threshold
+		m.largeFileWorkers = workers
+	}
+}
+
+// WithDirection restricts the direction of the sync.
+// The sync in the other direction fails with ErrDirection,
+// protecting from accidentally swapped source and destination.
+// S3 to S3 sync is rejected unless AnyDirection is specified.
+func WithDirection(d Direction) Option {
+	return func(m *Manager) {
+		m.direction = d
+	}
+}
+
+// WithOddKeyPolicy sets the policy of the object keys which can't be mapped to
+// the local paths as is, e.g. "a//b" and "/a". The default is OddKeySkip.
+func WithOddKeyPolicy(p OddKeyPolicy) Option {
+	return func(m *Manager) {
+		m.oddKeyPolicy = p
+	}
+}
+
+// WithStreamingDiff enables to compare the source and destination listings
+// by merging them in the sorted order, instead of loading the whole destination
+// listing into the memory. It reduces the memory usage for huge buckets.
+// It is not applied if the destination keys are mapped by WithKeySanitizer or
+// the other key mapping options since the mapped keys are not sorted.
+func WithStreamingDiff() Option {
+	return func(m *Manager) {
+		m.streamingDiff = true
+	}
+}
+
+// WithEmptyDestProbe probes the S3 destination by a single list request
+// of one key before the full listing, and skips the listing and the diff
+// if the destination is empty, i.e. all of the source files are synced.
+// It speeds up the initial seed of a huge prefix, while adding a request
+// to each sync to the non-empty destination.
+func WithEmptyDestProbe() Option {
+	return func(m *Manager) {
+		m.emptyDestProbe = true
+	}
+}
+
+// WithInitialSeed declares that the destination is empty, e.g. for the first-time
+// seeding of a bucket, and streams the source listing straight to the workers
+// without listing the destination and comparing the files.
+// The existing destination files are overwritten, and nothing is deleted.
+func WithInitialSeed() Option {
+	return func(m *Manager) {
+		m.initialSeed = true
+	}
+}
+
+// WithKeyRange limits the sync to the files with the names (the slash separated paths
+// relative to the sync root) after start and up to end in lexicographic order,
+// i.e. start < name <= end. Empty start or end means unbounded.
+// S3 listing starts after the start key and terminates at the end key,
+// so that enormous prefixes can be sharded across multiple machines
+// by the adjacent ranges like ("", "g"), ("g", "p") and ("p", "").
+// Destination files out of the range are not deleted.
+func WithKeyRange(start, end string) Option {
+	return func(m *Manager) {
+		m.keyRangeStart = start
+		m.keyRangeEnd = end
+	}
+}
+
 // WithDelete enables to delete files unexisting on source directory.
 func WithDelete() Option {
 	return func(m *Manager) {
@@ -37,6 +141,249 @@ func WithDelete() Option {
 	}
 }
 
+// WithBypassGovernanceRetention bypasses the governance mode of S3 Object Lock
+// on the deletions of the destination objects, e.g. to clean up the governance-locked
+// prefixes. It requires s3:BypassGovernanceRetention permission.
+// Note that the retention doesn't prevent deleting or overwriting an object without
+// the version ID, which adds a delete marker or a new version to the versioned bucket,
+// so it matters only for the deletions of the locked versions.
+func WithBypassGovernanceRetention() Option {
+	return func(m *Manager) {
+		m.bypassGovernance = aws.Bool(true)
+	}
+}
+
+// WithDeleteOrdering sets the order of the deletions of WithDelete relative to the updates.
+// The default is DeleteDuring.
+func WithDeleteOrdering(o DeleteOrdering) Option {
+	return func(m *Manager) {
+		m.deleteOrdering = o
+	}
+}
+
+// WithDeleteList holds the deletions of WithDelete until the diff completes,
+// and calls f with the destination and the slash separated names of all of the files
+// to be deleted before any deletion starts, so that they can be reviewed or archived.
+// If f returns an error, no file is deleted and the sync fails with the error.
+// It is also called in dry-run mode. Use WriteDeleteList to write the list to a file.
+func WithDeleteList(f func(dest string, names []string) error) Option {
+	return func(m *Manager) {
+		m.deleteList = f
+	}
+}
+
+// WithBackupPrefix enables to back up the destination files before they are
+// deleted or overwritten by the sync, giving an undo path of the sync.
+// The url is the S3 prefix like "s3://bucket/backup/" if the destination is S3,
+// or the local directory if the destination is local.
+// The backup of each file has the path relative to the destination, or the key
+// in the destination bucket, prefixed by the url and suffixed by the timestamp,
+// e.g. "s3://bucket/backup/path/to/file.20260102T150405Z".
+// The S3 objects are copied, and the local files are moved to the backup.
+// The backup must not be under the destination, otherwise it is synced as well.
+func WithBackupPrefix(url string) Option {
+	return func(m *Manager) {
+		m.backup = url
+	}
+}
+
+// WithLocalTrash enables to move the local files deleted by the sync to the trash
+// directory instead of removing them. The trashed file has the path relative to
+// the destination, replacing the file trashed by the same path before.
+// The trashed files older than the retention are purged at the start of the
+// sync, or kept forever if the retention is zero.
+// The trash must not be under the destination, otherwise it is synced as well.
+func WithLocalTrash(dir string, retention time.Duration) Option {
+	return func(m *Manager) {
+		m.trash = dir
+		m.trashRetention = retention
+	}
+}
+
+// WithVersionTracking enables to track the versions of the source objects in the
+// S3 to S3 sync between the versioned buckets.
+// The copy is pinned to the version of the source object, and its version ID is
+// stored in the metadata of the destination object. The object is skipped if
+// the destination is copied from the current source version, and copied if it
+// is from another version, regardless of the sizes and timestamps.
+// The comparator is used if the versions can't be compared, e.g. the destination
+// is not copied with the version tracking.
+// Each compared object costs a HeadObject request on both of the buckets, and
+// the copy requires s3:GetObjectVersion permission on the source bucket.
+func WithVersionTracking() Option {
+	return func(m *Manager) {
+		m.versionTracking = true
+	}
+}
+
+// WithRuntimeStats enables to sample the heap usage, the number of the goroutines
+// and the backlogs of the listings and the events at the interval during the sync,
+// to tune the buffer sizes and the parallelism of huge syncs.
+// The stats are logged, and emitted as RuntimeSampled events.
+func WithRuntimeStats(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.runtimeStatsInterval = interval
+	}
+}
+
+// WithGlacierPolicy sets the policy of the archived objects in GLACIER or DEEP_ARCHIVE
+// storage class on the sync from S3 to local: GlacierFail (default), GlacierSkip
+// or GlacierRestoreAndWait.
+func WithGlacierPolicy(p GlacierPolicy) Option {
+	return func(m *Manager) {
+		m.glacierPolicy = p
+	}
+}
+
+// WithPreserveEmptyDirs enables to create the empty local directories as the folder objects
+// with the keys ending with "/" like the S3 console, on the sync from local to S3.
+// With WithDelete, the folder objects of the removed local directories are deleted.
+// The folder objects are always created as the local directories on the sync from S3 to local.
+func WithPreserveEmptyDirs() Option {
+	return func(m *Manager) {
+		m.preserveEmptyDirs = true
+	}
+}
+
+// WithMaxDepth limits the sync to the files up to the depth under the source and
+// destination paths, e.g. 1 syncs only the top level files. The S3 listing is
+// delimited by "/" not to list the objects under the deeper prefixes.
+// Zero means unlimited.
+func WithMaxDepth(n int) Option {
+	return func(m *Manager) {
+		m.maxDepth = n
+	}
+}
+
+// WithNameCodec encodes the local file names to the object keys by the codec on the upload,
+// and decodes the keys to the local file names on the download.
+// If the codec can't decode the key to the name, e.g. hashed, the name is stored
+// in the metadata of the object and read by HeadObject of each object on the download.
+// The filters match the local file names.
+func WithNameCodec(codec NameCodec) Option {
+	return func(m *Manager) {
+		m.nameCodec = codec
+	}
+}
+
+// WithIgnoreHidden excludes the hidden files and directories whose names start with "."
+// from the source and destination listings. The excluded destination files are not deleted.
+func WithIgnoreHidden() Option {
+	return func(m *Manager) {
+		m.ignoreHidden = true
+	}
+}
+
+// WithIgnoreFile excludes the files matching the patterns of the ignore file IgnoreFileName
+// in the source root, local directory or bucket prefix, in gitignore syntax,
+// from the source and destination listings. The excluded destination files are not deleted.
+func WithIgnoreFile() Option {
+	return func(m *Manager) {
+		m.ignoreFile = true
+	}
+}
+
+// WithMinSize excludes the files smaller than the bytes from the sync.
+// The destination files smaller than the bytes are not deleted.
+func WithMinSize(bytes int64) Option {
+	return func(m *Manager) {
+		m.minSize = bytes
+	}
+}
+
+// WithMaxSize excludes the files larger than the bytes from the sync.
+// The destination files larger than the bytes are not deleted. Zero means unlimited.
+func WithMaxSize(bytes int64) Option {
+	return func(m *Manager) {
+		m.maxSize = bytes
+	}
+}
+
+// WithModifiedAfter syncs only the source files modified after the time,
+// e.g. for the incremental jobs. The other source files are skipped,
+// and their destination files are not deleted.
+func WithModifiedAfter(t time.Time) Option {
+	return func(m *Manager) {
+		m.modifiedAfter = t
+	}
+}
+
+// WithModifiedBefore syncs only the source files modified before the time,
+// e.g. for the archive jobs. The other source files are skipped,
+// and their destination files are not deleted.
+func WithModifiedBefore(t time.Time) Option {
+	return func(m *Manager) {
+		m.modifiedBefore = t
+	}
+}
+
+// WithDeleteExcluded deletes the destination files excluded by the filters, WithIgnoreHidden
+// and WithIgnoreFile, as well as the ones not in the source, like rsync --delete-excluded.
+// It implies WithDelete. Without it, the excluded destination files are preserved.
+func WithDeleteExcluded() Option {
+	return func(m *Manager) {
+		m.del = true
+		m.deleteExcluded = true
+	}
+}
+
+// WithBiSyncState sets the path of the file storing the state of the files on both sides
+// after BiSync, which is compared on the next run to detect the changes on each side.
+// It is required by BiSync, and should be used by a BiSync of the same paths.
+func WithBiSyncState(path string) Option {
+	return func(m *Manager) {
+		m.biSyncStatePath = path
+	}
+}
+
+// WithBiSyncMaxDelete sets the maximum percentage of the files on a side deleted by BiSync.
+// BiSync deleting more returns ErrBiSyncTooManyDeletes without changing any file.
+// The default is DefaultBiSyncMaxDelete, and 100 allows any deletion, including
+// all the files when the other side is listed as empty.
+func WithBiSyncMaxDelete(percent int) Option {
+	return func(m *Manager) {
+		m.biSyncMaxDelete = percent
+	}
+}
+
+// WithStateFile sets the path of the file recording the size, the modification time
+// and the ETag of the source and destination files compared to be in sync.
+// The next syncs skip the comparisons of the files unchanged since then, which saves
+// the checksums of the local files and the requests of the comparators like ChecksumComparator.
+// The file keeps the states of the syncs of each source and destination.
+// Both sides are still listed on every sync.
+func WithStateFile(path string) Option {
+	return func(m *Manager) {
+		m.stateFilePath = path
+	}
+}
+
+// WithHashWorkers sets the number of the workers comparing the source and destination
+// files, separated from the transfer workers of WithParallel.
+// It speeds up the comparators hashing the local files like ChecksumComparator,
+// which otherwise compare the files one by one. FileCompared events are emitted
+// for the comparisons. Default is 0, comparing the files on listing.
+func WithHashWorkers(n int) Option {
+	return func(m *Manager) {
+		m.hashWorkers = n
+	}
+}
+
+// WithVerify enables to verify the transferred files against the checksums of
+// the objects, e.g. WithVerify(VerifyETag | VerifySHA256), to detect the silent
+// corruptions by the flaky disks or networks.
+// After each upload and download, the local file is read again and its checksums
+// are compared with the ones returned by HeadObject. The mismatched file fails,
+// and the mismatched destination is removed to be transferred again by the next sync.
+// The multipart objects are verified only if they are uploaded with the same part
+// size as the uploads of the Manager. The files compressed by WithCompression or
+// encrypted by the client-side encryption are not verified.
+func WithVerify(mode VerifyMode) Option {
+	return func(m *Manager) {
+		m.verifyMode = mode
+	}
+}
+
 // WithACL sets Access Control List string for uploading.
 func WithACL(acl string) Option {
 	return func(m *Manager) {
@@ -45,6 +392,185 @@ func WithACL(acl string) Option {
 	}
 }
 
+// WithACLCopy enables to copy the object ACL from the source to the destination on S3 to S3 sync.
+// The ACL is read by GetObjectAcl and applied by PutObjectAcl after the copy,
+// overwriting the ACL specified by WithACL.
+func WithACLCopy() Option {
+	return func(m *Manager) {
+		m.copyACL = true
+	}
+}
+
+// WithInclude adds the glob patterns of the file names to be included.
+// Include and exclude filters are evaluated in the order of the options and
+// the last matching filter wins, like aws s3 sync.
+// The patterns are matched against the path relative to the sync root,
+// "*" matches any sequence of characters including "/".
+func WithInclude(globs ...string) Option {
+	return func(m *Manager) {
+		for _, g := range globs {
+			m.filters = append(m.filters, newFilterRule(true, g))
+		}
+	}
+}
+
+// WithExclude adds the glob patterns of the file names to be excluded.
+// See WithInclude for the evaluation order and the pattern syntax.
+// Excluded destination files are not deleted by WithDelete.
+// Local directories whose all paths match the pattern, like "node_modules/*",
+// are not walked into unless an include filter follows.
+func WithExclude(globs ...string) Option {
+	return func(m *Manager) {
+		for _, g := range globs {
+			m.filters = append(m.filters, newFilterRule(false, g))
+		}
+	}
+}
+
+// WithSkipRecentlyModified skips the source files modified within the given duration
+// to avoid syncing the files still being written by other processes.
+// The destination files corresponding to the skipped files are not deleted.
+func WithSkipRecentlyModified(d time.Duration) Option {
+	return func(m *Manager) {
+		m.skipRecent = d
+	}
+}
+
+// WithBandwidthLimit limits the total bandwidth of uploads and downloads in bytes per second.
+// The limit is shared by all parallel workers.
+// S3 to S3 copy is not limited since the data is transferred on the server side.
+func WithBandwidthLimit(bytesPerSec int64) Option {
+	return func(m *Manager) {
+		if bytesPerSec > 0 {
+			m.bandwidth = newRateLimiter(bytesPerSec)
+		} else {
+			m.bandwidth = nil
+		}
+	}
+}
+
+// WithPutRateLimitPerPrefix limits the rate of the write requests, i.e. PUT, COPY and DELETE,
+// to each prefix of the destination bucket in requests per second.
+// The prefix is the "directory" of the key. S3 throttles the requests per prefix,
+// so limiting the rate avoids the storm of the retries of the throttled requests
+// when many parallel workers write into a single prefix.
+// Each part of the multipart upload and copy is counted as a request.
+func WithPutRateLimitPerPrefix(requestsPerSec int64) Option {
+	return func(m *Manager) {
+		if requestsPerSec > 0 {
+			m.putRate = newPrefixRateLimiter(requestsPerSec)
+		} else {
+			m.putRate = nil
+		}
+	}
+}
+
+// WithLocalIOConcurrency limits the number of the simultaneous reads and writes
+// of the local files to n, independently from the parallelism of the S3 requests,
+// e.g. to avoid thrashing HDD or NFS by the concurrent transfers.
+// Zero or negative value disables the limit.
+func WithLocalIOConcurrency(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.localIO = newLocalIOLimiter(n)
+		} else {
+			m.localIO = nil
+		}
+	}
+}
+
+// WithReadAhead enables to read the local files of the uploads into memory ahead of
+// the uploads, so that the next files are read while the current uploads are in flight
+// and the network is kept saturated on slow local storage.
+// Up to files files not larger than maxSize are read ahead, so the memory usage is up to
+// maxSize times the sum of files and the parallelism.
+// It is ignored if WithStabilityCheck is given.
+func WithReadAhead(files int, maxSize int64) Option {
+	return func(m *Manager) {
+		m.readAheadFiles = files
+		m.readAheadSize = maxSize
+	}
+}
+
+// WithStabilityCheck enables to re-stat the local source files after the given delay
+// before uploading, and skips the files changed during the delay
+// to avoid uploading truncated files being actively appended.
+func WithStabilityCheck(delay time.Duration) Option {
+	return func(m *Manager) {
+		m.stabilityDelay = delay
+	}
+}
+
+// WithOperationTimeout sets the timeout of each file operation
+// (upload, download, copy and deletion of S3 objects).
+func WithOperationTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		m.opTimeout = d
+	}
+}
+
+// WithListTimeout sets the timeout of each page request of S3 object listing.
+func WithListTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		m.listTimeout = d
+	}
+}
+
+// WithManifest enables to write the manifest object with the given name
+// under the destination prefix after the successful local to S3 sync.
+// The manifest is a JSON listing all objects under the prefix
+// with the sizes, modification times and ETags.
+// DefaultManifestName is used if the name is empty.
+func WithManifest(name string) Option {
+	return func(m *Manager) {
+		if name == "" {
+			name = DefaultManifestName
+		}
+		m.manifestName = name
+	}
+}
+
+// WithManifestHMACKey enables to embed HMAC-SHA256 of the manifest
+// written by WithManifest using the given key.
+// The manifest can be verified by VerifyManifest with HMACManifestVerifier.
+func WithManifestHMACKey(key []byte) Option {
+	return func(m *Manager) {
+		m.manifestHMACKey = append([]byte{}, key...)
+	}
+}
+
+// WithSSE sets the server-side encryption algorithm of the uploaded and copied objects,
+// s3.ServerSideEncryptionAes256 (SSE-S3) or s3.ServerSideEncryptionAwsKms (SSE-KMS).
+// Note that the ETag of the object encrypted by SSE-KMS or SSE-C is not
+// the MD5 checksum of the contents and can't be compared by ChecksumComparator.
+func WithSSE(sseType string) Option {
+	return func(m *Manager) {
+		m.sse = &sseType
+	}
+}
+
+// WithKMSKeyID sets the KMS key ID or ARN used for SSE-KMS.
+// It enables SSE-KMS if the other server-side encryption is not specified.
+func WithKMSKeyID(keyID string) Option {
+	return func(m *Manager) {
+		if m.sse == nil {
+			m.sse = aws.String(s3.ServerSideEncryptionAwsKms)
+		}
+		m.sseKMSKeyID = &keyID
+	}
+}
+
+// WithSSECustomerKey sets the 256-bit key for the server-side encryption
+// with the customer provided key (SSE-C).
+// The key is used for uploading, downloading and copying, so the source and
+// destination objects of S3 to S3 sync must be encrypted by the same key.
+func WithSSECustomerKey(key []byte) Option {
+	return func(m *Manager) {
+		m.sseCustomerAlgorithm = aws.String(s3.ServerSideEncryptionAes256)
+		m.sseCustomerKey = aws.String(string(key))
+	}
+}
+
 // WithDryRun enables dry-run mode.
 func WithDryRun() Option {
 	return func(m *Manager) {
@@ -52,6 +578,211 @@ func WithDryRun() Option {
 	}
 }
 
+// WithListingCache enables to reuse the listings of the dry-run by the real run.
+// The dry-run writes the S3 listings of both of the source and destination to the cache file,
+// and the following real run of the same source and destination within the TTL syncs
+// the files by the cached listings without listing the objects again.
+// The cache file is removed by the real run. Local files are always listed.
+func WithListingCache(path string, ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.listingCachePath = path
+		m.listingCacheTTL = ttl
+	}
+}
+
+// WithWarmStart enables to reuse the destination listing of the previous sync
+// which didn't change the destination. The listing is stored in the file at the path,
+// and the next sync lists only the first page to validate it and the keys added after
+// the last key of the previous listing, instead of listing all of the objects.
+// It assumes that the destination is changed only by the syncs or by the new keys
+// sorted after the existing ones, e.g. time partitioned. The other changes are detected
+// only in the first page, so the full listing is forced after maxAge. Zero means no limit.
+func WithWarmStart(path string, maxAge time.Duration) Option {
+	return func(m *Manager) {
+		m.warmStartPath = path
+		m.warmStartMaxAge = maxAge
+	}
+}
+
+// WithErrorPolicy sets the policy of the sync on the failures of the file operations.
+// FailFast and MaxErrors abort the sync by cancelling the remaining operations,
+// and the returned error includes ErrTooManyErrors. Default is ContinueAll.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(m *Manager) {
+		m.errorPolicy = policy
+	}
+}
+
+// WithRetries enables to retry the upload, download, copy and deletion of each file
+// up to max times on the transient errors of S3, such as SlowDown, server errors
+// and connection resets, in addition to the retries of each request by the SDK.
+// The delay before each retry is given by the backoff, or DefaultBackoff if nil.
+func WithRetries(max int, backoff BackoffFunc) Option {
+	return func(m *Manager) {
+		m.maxRetries = max
+		m.backoff = backoff
+	}
+}
+
+// WithRegionalClients enables to access each bucket by the client of its region,
+// so that the source and destination in different regions can be synced by a Manager.
+// The region of each bucket is looked up once, and the clients of the regions are
+// created on demand from the clients of the Manager, sharing their credentials,
+// HTTP client and request handlers.
+// The clients with the custom endpoint and the access point ARNs are used as is.
+func WithRegionalClients() Option {
+	return func(m *Manager) {
+		m.clientPool = newClientPool()
+	}
+}
+
+// WithTransferAcceleration enables to upload and download the objects through
+// the S3 Transfer Acceleration endpoint for the long-haul transfers.
+// The acceleration status of each bucket is looked up once by GetBucketAccelerateConfiguration,
+// and the regular endpoint is used for the buckets without the acceleration enabled.
+// The other requests, e.g. listing and S3 to S3 copy, use the regular endpoint.
+func WithTransferAcceleration() Option {
+	return func(m *Manager) {
+		m.accelerator = newAccelerator()
+	}
+}
+
+// WithEndpoint sets the endpoint url of the S3 compatible storage,
+// e.g. MinIO, Cloudflare R2 or Ceph RGW, to the clients of the Manager.
+// pathStyle enables the path-style addressing (http://endpoint/bucket/key)
+// required by most of the storages without the wildcard DNS of the buckets.
+// It is applied to the clients given to New and NewWithClients
+// if they are *s3.S3, and the other clients are used as is.
+func WithEndpoint(url string, pathStyle bool) Option {
+	return func(m *Manager) {
+		m.endpoint = url
+		m.pathStyle = pathStyle
+	}
+}
+
+// WithReadOnly enables read-only mode.
+// In read-only mode, the manager only calls read APIs of S3 and
+// the sync fails with ErrReadOnly if any upload, copy or deletion is required.
+func WithReadOnly() Option {
+	return func(m *Manager) {
+		m.readOnly = true
+	}
+}
+
+// WithMetadata sets the user metadata of the uploaded and copied objects.
+// On S3 to S3 sync, the metadata of the source objects are preserved
+// and overwritten by the given metadata.
+func WithMetadata(metadata map[string]string) Option {
+	return func(m *Manager) {
+		m.metadata = make(map[string]string, len(metadata))
+		for k, v := range metadata {
+			m.metadata[k] = v
+		}
+	}
+}
+
+// WithCacheControl sets Cache-Control of the uploaded and copied objects.
+func WithCacheControl(cacheControl string) Option {
+	return func(m *Manager) {
+		m.cacheControl = &cacheControl
+	}
+}
+
+// WithContentEncoding sets Content-Encoding of the uploaded and copied objects.
+func WithContentEncoding(encoding string) Option {
+	return func(m *Manager) {
+		m.contentEncoding = &encoding
+	}
+}
+
+// WithStorageClass sets the storage class of the uploaded and copied objects,
+// e.g. s3.StorageClassStandardIa.
+func WithStorageClass(class string) Option {
+	return func(m *Manager) {
+		m.storageClass = &class
+	}
+}
+
+// WithTags sets the tags of the uploaded objects.
+// On S3 to S3 sync, the tags of the source objects are preserved instead.
+func WithTags(tags map[string]string) Option {
+	return func(m *Manager) {
+		m.tagging = aws.String(encodeTags(tags))
+	}
+}
+
+// WithUploadInputModifier sets a function called before every upload
+// to customize the parameters of the object, e.g. ACL, Cache-Control or
+// storage class, based on the file.
+// The input is filled with the other options beforehand.
+// Bucket, Key and Body must not be changed.
+func WithUploadInputModifier(f func(file FileInfo, in *s3manager.UploadInput)) Option {
+	return func(m *Manager) {
+		m.uploadInputModifier = f
+	}
+}
+
+// WithCompression compresses the uploaded files not smaller than minSize by
+// the given codec, e.g. Gzip, and sets Content-Encoding of the objects.
+// If includeExtensions are given, e.g. ".log", only the files with them are compressed.
+// The matching objects are downloaded by a single request and decompressed
+// if they have the Content-Encoding.
+// Since the sizes of the compressed objects differ from the files, they are
+// compared by the modification time, and the ETag comparison is not applicable.
+func WithCompression(codec Compression, minSize int64, includeExtensions ...string) Option {
+	return func(m *Manager) {
+		c := &compressionRule{codec: codec, minSize: minSize}
+		if len(includeExtensions) > 0 {
+			c.exts = make(map[string]bool, len(includeExtensions))
+			for _, ext := range includeExtensions {
+				c.exts[normalizeExt(ext)] = true
+			}
+		}
+		m.compression = c
+	}
+}
+
+// WithClientSideEncryptionKey encrypts the uploaded files by AES-GCM with the data keys
+// wrapped by the given AES-256 key, and decrypts the downloaded objects.
+// The objects are stored in the envelope format of the Amazon S3 Encryption Client
+// with the "AES/GCM" key wrap, so that they can be read by the compatible clients.
+// The contents of each file are held in memory on encryption and decryption,
+// and the ETag comparison is not applicable to the encrypted objects.
+func WithClientSideEncryptionKey(key []byte) Option {
+	return func(m *Manager) {
+		m.encryption = newKeyEncryption(key)
+	}
+}
+
+// WithClientSideEncryptionKMS encrypts the uploaded files like WithClientSideEncryptionKey
+// with the data keys generated by the KMS key, in the "kms+context" key wrap of
+// the Amazon S3 Encryption Client.
+func WithClientSideEncryptionKMS(client kmsiface.KMSAPI, keyID string) Option {
+	return func(m *Manager) {
+		m.encryption = newKMSEncryption(client, keyID)
+	}
+}
+
+// WithOwnership enables to preserve the ownership of the local files.
+// The uid and gid are stored in the object metadata on upload and
+// restored on download.
+func WithOwnership() Option {
+	return func(m *Manager) {
+		m.ownership = true
+	}
+}
+
+// WithOwnerNames enables to preserve the ownership with the user and group names
+// in addition to the uid and gid.
+// On download, names are resolved first so that the ownership is restored
+// across machines with different uid and gid mappings.
+func WithOwnerNames() Option {
+	return func(m *Manager) {
+		m.ownership = true
+		m.ownerNames = true
+	}
+}
+
 // WithoutGuessMimeType disables guessing MIME type from contents.
 func WithoutGuessMimeType() Option {
 	return func(m *Manager) {
@@ -66,7 +797,29 @@ func WithContentType(mime string) Option {
 	}
 }
 
+// WithContentTypeMap sets the MIME types of the uploaded files by extension,
+// e.g. {".css": "text/css"}, which take precedence over guessing from the contents.
+// The extensions are case-insensitive.
+func WithContentTypeMap(types map[string]string) Option {
+	return func(m *Manager) {
+		m.contentTypeMap = make(map[string]string, len(types))
+		for ext, mime := range types {
+			m.contentTypeMap[normalizeExt(ext)] = mime
+		}
+	}
+}
+
+// WithMimeSniffMaxSize skips guessing MIME type from the contents of
+// the empty files and the files larger than maxSize.
+// Their Content-Type is left to the default of S3.
+func WithMimeSniffMaxSize(maxSize int64) Option {
+	return func(m *Manager) {
+		m.sniffMaxSize = maxSize
+	}
+}
+
 // WithDownloaderOptions sets underlying s3manager's options.
+// They are applied after WithDownloaderConcurrency and WithDownloadPartSize.
 func WithDownloaderOptions(opts ...func(*s3manager.Downloader)) Option {
 	return func(m *Manager) {
 		m.downloaderOpts = opts
@@ -74,8 +827,250 @@ func WithDownloaderOptions(opts ...func(*s3manager.Downloader)) Option {
 }
 
 // WithUploaderOptions sets underlying s3manager's options.
+// They are applied after WithUploaderConcurrency and WithUploadPartSize.
 func WithUploaderOptions(opts ...func(*s3manager.Uploader)) Option {
 	return func(m *Manager) {
 		m.uploaderOpts = opts
 	}
 }
+
+// WithDownloaderConcurrency sets the number of the parts of each file downloaded in parallel.
+func WithDownloaderConcurrency(n int) Option {
+	return func(m *Manager) {
+		m.downloaderConcurrency = n
+	}
+}
+
+// WithDownloadPartSize sets the size of the parts of each file downloaded in parallel.
+func WithDownloadPartSize(size int64) Option {
+	return func(m *Manager) {
+		m.downloadPartSize = size
+	}
+}
+
+// WithUploaderConcurrency sets the number of the parts of each file uploaded in parallel.
+func WithUploaderConcurrency(n int) Option {
+	return func(m *Manager) {
+		m.uploaderConcurrency = n
+	}
+}
+
+// WithUploadPartSize sets the part size of the multipart upload.
+// It must be at least s3manager.MinUploadPartSize.
+// Use ETagComparator with the same part size to compare the checksums of the uploaded files.
+func WithUploadPartSize(size int64) Option {
+	return func(m *Manager) {
+		m.uploadPartSize = size
+	}
+}
+
+// WithCopyConcurrency sets the number of the parts of each object copied in parallel
+// by the multipart copy. Objects larger than 5 GiB are copied by the multipart copy
+// since CopyObject doesn't support them. Default is DefaultCopyConcurrency.
+func WithCopyConcurrency(n int) Option {
+	return func(m *Manager) {
+		m.copyConcurrency = n
+	}
+}
+
+// WithCopyPartSize sets the part size of the multipart copy. Default is DefaultCopyPartSize.
+// It is enlarged if the number of the parts exceeds the limit of the multipart upload.
+func WithCopyPartSize(size int64) Option {
+	return func(m *Manager) {
+		m.copyPartSize = size
+	}
+}
+
+// WithTimeBudgetMargin sets the remaining time to stop starting new operations
+// in SyncWithTimeBudget. It should be long enough to finish the operations in progress.
+// Default is DefaultTimeBudgetMargin.
+func WithTimeBudgetMargin(d time.Duration) Option {
+	return func(m *Manager) {
+		m.timeBudgetMargin = d
+	}
+}
+
+// WithSymlinkPolicy sets the handling of the local symbolic links. Default is SymlinkFollow.
+func WithSymlinkPolicy(p SymlinkPolicy) Option {
+	return func(m *Manager) {
+		m.symlinkPolicy = p
+	}
+}
+
+// WithIncompleteUploadCleanup aborts the multipart uploads under the s3 destination
+// initiated before olderThan ago, before each sync.
+// olderThan should be longer than the longest upload to avoid aborting the uploads
+// of the other running syncs.
+func WithIncompleteUploadCleanup(olderThan time.Duration) Option {
+	return func(m *Manager) {
+		m.cleanupOlderThan = olderThan
+	}
+}
+
+// WithOnComplete sets the callback function called when each sync finishes,
+// regardless of whether the sync succeeded or failed.
+func WithOnComplete(f func(SyncResult)) Option {
+	return func(m *Manager) {
+		m.onComplete = f
+	}
+}
+
+// WithNotifier adds the notifier which publishes the summary after each sync.
+func WithNotifier(n Notifier) Option {
+	return func(m *Manager) {
+		m.notifiers = append(m.notifiers, n)
+	}
+}
+
+// WithCloudWatchMetrics publishes the metrics of each sync to CloudWatch
+// under the given namespace. See CloudWatchNotifier for the published metrics.
+func WithCloudWatchMetrics(cw cloudwatchiface.CloudWatchAPI, namespace string, dimensions map[string]string) Option {
+	return WithNotifier(NewCloudWatchNotifier(cw, namespace, dimensions))
+}
+
+// WithEventQueueClient sets the SQS client to receive the S3 event notifications
+// by SyncFromEvents.
+func WithEventQueueClient(client sqsiface.SQSAPI) Option {
+	return func(m *Manager) {
+		m.sqs = client
+	}
+}
+
+// WithComparator sets the comparator to decide whether the file should be synced
+// to the existing destination file.
+func WithComparator(c Comparator) Option {
+	return func(m *Manager) {
+		m.comparator = c
+	}
+}
+
+// WithSizeOnly syncs the files only if the size differs.
+// This is the same as WithComparator(SizeOnlyComparator).
+func WithSizeOnly() Option {
+	return WithComparator(SizeOnlyComparator)
+}
+
+// WithExactTimestamps syncs the files if the size or the modification time differs.
+// This is the same as WithComparator(ExactTimestampsComparator).
+func WithExactTimestamps() Option {
+	return WithComparator(ExactTimestampsComparator)
+}
+
+// WithChecksum syncs the files if the size or the MD5 checksum differs.
+// This is the same as WithComparator(ChecksumComparator).
+// Use WithETagComparison if the uploader part size is customized.
+func WithChecksum() Option {
+	return WithComparator(ChecksumComparator)
+}
+
+// WithETagComparison syncs the files if the size or the ETag differs.
+// partSize must be the part size used to upload the objects by multipart upload.
+// This is the same as WithComparator(ETagComparator(partSize)).
+func WithETagComparison(partSize int64) Option {
+	return WithComparator(ETagComparator(partSize))
+}
+
+// WithClockSkew compensates the systematic skew between the local clock and
+// the clock of S3, e.g. on the edge devices and VMs with drifting clocks,
+// which causes constant unnecessary re-transfers.
+// d is the offset of the local clock relative to S3, i.e. positive if the local
+// clock is ahead, and the timestamps of the objects are shifted by d before
+// being compared with the local files.
+func WithClockSkew(d time.Duration) Option {
+	return func(m *Manager) {
+		m.clockSkew = d
+	}
+}
+
+// WithChecksumPolicy sets the policy of the checksums to compare and store the objects.
+// ChecksumSHA256Only sets SHA256ChecksumComparator as the comparator,
+// so use WithComparator after this option to customize the comparator.
+func WithChecksumPolicy(policy ChecksumPolicy) Option {
+	return func(m *Manager) {
+		m.checksumPolicy = policy
+		if policy == ChecksumSHA256Only {
+			m.comparator = SHA256ChecksumComparator
+		}
+	}
+}
+
+// WithKeySanitizer sanitizes the destination keys of the upload and s3 to s3 sync.
+// If multiple source files are mapped to the same key, the files except the first
+// one are not synced and reported as errors.
+func WithKeySanitizer(s KeySanitizer) Option {
+	return func(m *Manager) {
+		m.keyMappers = append(m.keyMappers, s.Sanitize)
+	}
+}
+
+// WithKeyMapper rewrites the destination keys of the upload and s3 to s3 sync by the function
+// called with the slash separated relative path of each source file, e.g. to add a date
+// partition prefix, or to flatten the directories. The returned key is relative to the
+// destination prefix. The mappers are applied in the order of the options including
+// WithKeySanitizer. If multiple source files are mapped to the same key, the files
+// except the first one are not synced and reported as errors, as well as the files
+// mapped to the empty key.
+func WithKeyMapper(mapper func(localRelPath string) string) Option {
+	return func(m *Manager) {
+		m.keyMappers = append(m.keyMappers, slashKeyMapper(mapper))
+	}
+}
+
+// WithExpectedTotals sets the expected number and size of the source files
+// to estimate the progress before the source listing is completed.
+func WithExpectedTotals(files, bytes int64) Option {
+	return func(m *Manager) {
+		m.expectedFiles = files
+		m.expectedBytes = bytes
+	}
+}
+
+// WithBucketMetrics enables to estimate the progress before the source listing
+// is completed using the CloudWatch storage metrics of the source bucket.
+// Since the metrics are reported for the whole bucket once a day,
+// the estimation is rough if syncing a prefix.
+func WithBucketMetrics(cw cloudwatchiface.CloudWatchAPI) Option {
+	return func(m *Manager) {
+		m.cloudWatch = cw
+	}
+}
+
+// WithProgressFunc sets the function called on the start, the transfer of each
+// chunk and the completion of each file transfer.
+// The function is called concurrently from the sync workers.
+func WithProgressFunc(f func(ProgressEvent)) Option {
+	return func(m *Manager) {
+		m.progressFunc = f
+	}
+}
+
+// WithItemizedOutput writes the changes of the files to w in the format of
+// the itemized changes of rsync (rsync -i), e.g. "<f+++++++++ path" for an uploaded
+// new file and "*deleting   path" for a deleted file, so that the log parsers of
+// rsync can be reused. The changes are written in dry-run mode as well.
+func WithItemizedOutput(w io.Writer) Option {
+	return func(m *Manager) {
+		if w != nil {
+			m.itemizer = &itemizer{w: w}
+		} else {
+			m.itemizer = nil
+		}
+	}
+}
+
+// WithEncodedKeyLog enables to log the raw and the percent-encoded keys
+// of the copy sources to debug the objects failing to be copied.
+func WithEncodedKeyLog() Option {
+	return func(m *Manager) {
+		m.logEncodedKeys = true
+	}
+}
+
+// WithWatchInterval sets the interval of Watch to wait for the changed files to settle.
+// A changed file is synced once it has no file system events for an interval.
+// Defaults to DefaultWatchInterval.
+func WithWatchInterval(d time.Duration) Option {
+	return func(m *Manager) {
+		m.watchInterval = d
+	}
+}
@@ -14,11 +14,23 @@
 package s3sync
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
@@ -54,6 +66,76 @@ func TestWithACL(t *testing.T) {
 	})
 }
 
+func TestWithTags(t *testing.T) {
+	m := New(session.New(), WithTags(map[string]string{"project": "s3 sync", "env": "prod"}))
+	if tags := aws.StringValue(m.tagging); tags != "env=prod&project=s3+sync" {
+		t.Errorf("Unexpected tagging %s", tags)
+	}
+}
+
+func TestWithUploadInputModifier(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	for _, name := range []string{"index.html", "app.js"} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte("a"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	puts := make(map[string]*s3.PutObjectInput)
+	c := s3.New(session.New(), aws.NewConfig().
+		WithRegion("us-east-1").
+		WithCredentials(credentials.AnonymousCredentials))
+	c.Handlers.Send.Clear()
+	c.Handlers.Send.PushBack(func(r *request.Request) {
+		in := r.Params.(*s3.PutObjectInput)
+		mu.Lock()
+		puts[*in.Key] = in
+		mu.Unlock()
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	})
+
+	m := New(session.New(),
+		WithACL("private"),
+		WithCacheControl("max-age=60"),
+		WithUploadInputModifier(func(file FileInfo, in *s3manager.UploadInput) {
+			if filepath.Ext(file.Name) == ".html" {
+				in.ACL = aws.String("public-read")
+				in.CacheControl = aws.String("no-cache")
+			}
+		}),
+	)
+	m.s3 = c
+
+	for _, name := range []string{"index.html", "app.js"} {
+		file := &fileInfo{name: name, path: filepath.Join(temp, name), size: 1, local: true}
+		if err := m.upload(context.Background(), file, temp, &s3Path{bucket: "bucket", bucketPrefix: "prefix"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := map[string][2]string{
+		"prefix/index.html": {"public-read", "no-cache"},
+		"prefix/app.js":     {"private", "max-age=60"},
+	}
+	for key, e := range expected {
+		in, ok := puts[key]
+		if !ok {
+			t.Fatalf("%s is not uploaded", key)
+		}
+		if acl, cc := aws.StringValue(in.ACL), aws.StringValue(in.CacheControl); acl != e[0] || cc != e[1] {
+			t.Errorf("%s: expected ACL %s and Cache-Control %s, got %s and %s", key, e[0], e[1], acl, cc)
+		}
+	}
+}
+
 func TestUploaderDownloaderOptions(t *testing.T) {
 	sess := session.New(&aws.Config{
 		Credentials: credentials.AnonymousCredentials,
@@ -77,3 +159,167 @@ func TestUploaderDownloaderOptions(t *testing.T) {
 		}
 	})
 }
+
+func TestTransferOptions(t *testing.T) {
+	sess := session.New(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("dummy"),
+	})
+
+	t.Run("Uploader", func(t *testing.T) {
+		m := New(sess,
+			WithUploaderConcurrency(3),
+			WithUploadPartSize(s3manager.MinUploadPartSize*2),
+			WithUploaderOptions(func(u *s3manager.Uploader) { u.LeavePartsOnError = true }),
+		)
+		u := m.getUploader()
+		if u.Concurrency != 3 || u.PartSize != s3manager.MinUploadPartSize*2 || !u.LeavePartsOnError {
+			t.Fatalf("Uploader is not configured by the options: %+v", u)
+		}
+		if m.getUploader() != u {
+			t.Fatal("Uploader must be reused")
+		}
+	})
+	t.Run("Downloader", func(t *testing.T) {
+		m := New(sess, WithDownloaderConcurrency(2), WithDownloadPartSize(1024))
+		d := m.getDownloader()
+		if d.Concurrency != 2 || d.PartSize != 1024 {
+			t.Fatalf("Downloader is not configured by the options: %+v", d)
+		}
+		if m.getDownloader() != d {
+			t.Fatal("Downloader must be reused")
+		}
+	})
+}
+
+func TestWithPreset(t *testing.T) {
+	sess := session.New(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("dummy"),
+	})
+
+	t.Run("Website", func(t *testing.T) {
+		m := New(sess, WithPreset(PresetWebsite))
+		if !m.del || !m.guessMime {
+			t.Fatal("website preset must enable delete and MIME type guessing")
+		}
+		if aws.StringValue(m.cacheControl) != DefaultWebsiteCacheControl || m.verifyMode != VerifyETag {
+			t.Fatal("website preset must set Cache-Control and verify ETags")
+		}
+	})
+	t.Run("Backup", func(t *testing.T) {
+		m := New(sess, WithDelete(), WithPreset("backup"))
+		if m.del {
+			t.Fatal("backup preset must disable delete")
+		}
+		if m.verifyMode != VerifyETag|VerifySHA256 {
+			t.Fatal("backup preset must verify ETags and SHA-256")
+		}
+	})
+	t.Run("Dataset", func(t *testing.T) {
+		m := New(sess, WithPreset(PresetDataset))
+		if !m.del || m.guessMime {
+			t.Fatal("dataset preset must enable delete and disable MIME type guessing")
+		}
+		if m.verifyMode != VerifyETag {
+			t.Fatal("dataset preset must verify ETags")
+		}
+	})
+	t.Run("Overwrite", func(t *testing.T) {
+		m := New(sess, WithPreset(PresetDataset), WithParallel(3))
+		if m.nJobs != 3 {
+			t.Fatal("options after WithPreset must be applied")
+		}
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := NewWithOptionsValidated(sess, WithPreset("webiste")); !errors.Is(err, ErrInvalidOption) {
+			t.Fatalf("Expected %v, got %v", ErrInvalidOption, err)
+		}
+		m := New(sess, WithPreset("webiste"), WithBiSyncState("state.json"), WithEventQueueClient(&dummySQS{}))
+		ctx := context.Background()
+		for name, sync := range map[string]func() error{
+			"Sync": func() error { return m.Sync(ctx, "s3://bucket/prefix", "local") },
+			"SyncProviders": func() error {
+				return m.SyncProviders(ctx, nil, "s3://bucket/prefix")
+			},
+			"Migrate": func() error {
+				_, err := m.Migrate(ctx, "s3://bucket/prefix", "s3://bucket2/prefix")
+				return err
+			},
+			"BiSync":         func() error { return m.BiSync(ctx, "s3://bucket/prefix", "local", NewerWins) },
+			"SyncFromEvents": func() error { return m.SyncFromEvents(ctx, "queue", "local") },
+			"Watch":          func() error { return m.Watch(ctx, "local", "s3://bucket/prefix") },
+			"SyncShards":     func() error { return m.SyncShards(ctx, nil, "s3://bucket/prefix", "local") },
+		} {
+			if err := sync(); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("%s: expected %v, got %v", name, ErrInvalidOption, err)
+			}
+		}
+	})
+	t.Run("Transformed", func(t *testing.T) {
+		for name, opt := range map[string]Option{
+			"Compression": WithCompression(Gzip, 0),
+			"Encryption":  WithClientSideEncryptionKey(make([]byte, 32)),
+		} {
+			m := New(sess, WithPreset(PresetBackup), opt)
+			if err := m.Sync(context.Background(), "s3://bucket/prefix", "local"); !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("%s: expected %v, got %v", name, ErrInvalidOption, err)
+			}
+			m = New(sess, WithPreset(PresetBackup), opt, WithVerify(0))
+			if err := m.checkPreset(); err != nil {
+				t.Fatalf("%s: expected no error without the verification, got %v", name, err)
+			}
+		}
+	})
+}
+
+type semaphoreExecutor struct {
+	sem     chan struct{}
+	mu      sync.Mutex
+	started int
+}
+
+func (e *semaphoreExecutor) Go(f func() error) {
+	e.sem <- struct{}{}
+	e.mu.Lock()
+	e.started++
+	e.mu.Unlock()
+	go func() {
+		defer func() { <-e.sem }()
+		f()
+	}()
+}
+
+func TestWithExecutor(t *testing.T) {
+	sess := session.New(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("dummy"),
+	})
+	e := &semaphoreExecutor{sem: make(chan struct{}, 2)}
+	m := New(sess, WithExecutor(e))
+
+	var running, maxRunning, done int32
+	workers, stopWorkers := m.startWorkers()
+	for i := 0; i < 10; i++ {
+		workers.run(0, func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		})
+	}
+	stopWorkers()
+
+	if done != 10 || e.started != 10 {
+		t.Errorf("All jobs must be run by the executor, done: %d, started: %d", done, e.started)
+	}
+	if maxRunning > 2 {
+		t.Errorf("Concurrency must be limited by the executor, max: %d", maxRunning)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Metadata keys to store the file ownership.
+const (
+	metadataUID   = "uid"
+	metadataGID   = "gid"
+	metadataUser  = "user"
+	metadataGroup = "group"
+)
+
+// ownerMetadata returns the object metadata of the ownership of the local file.
+func (m *Manager) ownerMetadata(filename string) map[string]*string {
+	uid, gid, ok := fileOwner(filename)
+	if !ok {
+		return nil
+	}
+	md := map[string]*string{
+		metadataUID: aws.String(strconv.Itoa(uid)),
+		metadataGID: aws.String(strconv.Itoa(gid)),
+	}
+	if m.ownerNames {
+		if u, err := user.LookupId(strconv.Itoa(uid)); err == nil {
+			md[metadataUser] = aws.String(u.Username)
+		}
+		if g, err := user.LookupGroupId(strconv.Itoa(gid)); err == nil {
+			md[metadataGroup] = aws.String(g.Name)
+		}
+	}
+	return md
+}
+
+// restoreOwner changes the ownership of the downloaded file according to the object metadata.
+// Names are preferred over IDs if stored and resolvable on this machine.
+// Failures to change the ownership are logged and ignored
+// since only privileged users can change it.
+func (m *Manager) restoreOwner(ctx context.Context, bucket, key, filename string) error {
+	head, err := m.regionalClient(ctx, m.sourceClient(), bucket).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return err
+	}
+	uid, gid := ownerFromMetadata(head.Metadata)
+	if uid < 0 && gid < 0 {
+		return nil
+	}
+	if err := os.Lchown(filename, uid, gid); err != nil {
+		println("Failed to change the ownership of", filename+":", err.Error())
+	}
+	return nil
+}
+
+// metadataValue returns the value of the object metadata.
+func metadataValue(md map[string]*string, key string) (string, bool) {
+	// Metadata keys are canonicalized by the SDK.
+	for k, v := range md {
+		if strings.EqualFold(k, key) && v != nil {
+			return *v, true
+		}
+	}
+	return "", false
+}
+
+// ownerFromMetadata returns the uid and gid stored in the metadata, or -1 if not available.
+func ownerFromMetadata(md map[string]*string) (uid, gid int) {
+	get := func(key string) (string, bool) {
+		return metadataValue(md, key)
+	}
+	uid, gid = -1, -1
+	if name, ok := get(metadataUser); ok {
+		if u, err := user.Lookup(name); err == nil {
+			uid, _ = strconv.Atoi(u.Uid)
+		}
+	}
+	if uid < 0 {
+		if s, ok := get(metadataUID); ok {
+			if id, err := strconv.Atoi(s); err == nil {
+				uid = id
+			}
+		}
+	}
+	if name, ok := get(metadataGroup); ok {
+		if g, err := user.LookupGroup(name); err == nil {
+			gid, _ = strconv.Atoi(g.Gid)
+		}
+	}
+	if gid < 0 {
+		if s, ok := get(metadataGID); ok {
+			if id, err := strconv.Atoi(s); err == nil {
+				gid = id
+			}
+		}
+	}
+	return uid, gid
+}
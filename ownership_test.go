@@ -0,0 +1,100 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestOwnerMetadata(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ownership is not supported on Windows")
+	}
+	temp, err := ioutil.TempDir("", "s3synctest")
+	defer os.RemoveAll(temp)
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	filename := filepath.Join(temp, "foo")
+	if err := ioutil.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	current, err := user.Current()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	t.Run("IDs", func(t *testing.T) {
+		md := (&Manager{ownership: true}).ownerMetadata(filename)
+		if aws.StringValue(md[metadataUID]) != current.Uid {
+			t.Errorf("Expected uid %s, got %s", current.Uid, aws.StringValue(md[metadataUID]))
+		}
+		if _, ok := md[metadataUser]; ok {
+			t.Error("User name must not be stored")
+		}
+	})
+	t.Run("Names", func(t *testing.T) {
+		md := (&Manager{ownership: true, ownerNames: true}).ownerMetadata(filename)
+		if aws.StringValue(md[metadataUser]) != current.Username {
+			t.Errorf("Expected user %s, got %s", current.Username, aws.StringValue(md[metadataUser]))
+		}
+	})
+}
+
+func TestOwnerFromMetadata(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skip(err)
+	}
+	currentUID, _ := strconv.Atoi(current.Uid)
+
+	testCases := map[string]struct {
+		md       map[string]*string
+		uid, gid int
+	}{
+		"Empty": {
+			md:  nil,
+			uid: -1, gid: -1,
+		},
+		"IDs": {
+			md:  map[string]*string{"Uid": aws.String("1234"), "Gid": aws.String("5678")},
+			uid: 1234, gid: 5678,
+		},
+		"NamePreferred": {
+			md:  map[string]*string{"Uid": aws.String("1234"), "User": aws.String(current.Username)},
+			uid: currentUID, gid: -1,
+		},
+		"UnknownName": {
+			md:  map[string]*string{"Uid": aws.String("1234"), "User": aws.String("s3sync-unknown-user")},
+			uid: 1234, gid: -1,
+		},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			uid, gid := ownerFromMetadata(tt.md)
+			if uid != tt.uid || gid != tt.gid {
+				t.Errorf("Expected %d:%d, got %d:%d", tt.uid, tt.gid, uid, gid)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// maxPathSegmentLength is the maximum length of a file name
+// on the common file systems.
+const maxPathSegmentLength = 255
+
+// PathIssue is a destination path which is problematic on some platforms.
+type PathIssue struct {
+	// Name is the destination path relative to the sync root, separated by slashes.
+	Name string
+	// Problem describes why the path is problematic.
+	Problem string
+}
+
+func (i PathIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Name, i.Problem)
+}
+
+// AuditPaths lists the source files passing the filters and reports
+// the destination paths which are problematic on any of Linux, macOS and Windows,
+// e.g. the keys containing backslashes, the reserved names of Windows and
+// the names differing only in case, before running the sync.
+// The result doesn't depend on the platform AuditPaths runs on.
+func (m *Manager) AuditPaths(ctx context.Context, source, dest string) ([]PathIssue, error) {
+	sourceURL, err := parseURL(source)
+	if err != nil {
+		return nil, err
+	}
+	destURL, err := parseURL(dest)
+	if err != nil {
+		return nil, err
+	}
+	if !isS3URL(sourceURL) && !isS3URL(destURL) {
+		return nil, errors.New("local to local sync is not supported")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var files chan *fileInfo
+	if isS3URL(sourceURL) {
+		sourcePath, err := urlToS3Path(sourceURL)
+		if err != nil {
+			return nil, err
+		}
+		sourcePath.source = true
+		files = m.listS3Files(ctx, sourcePath, nil)
+	} else {
+		files = listLocalFiles(ctx, source, nil)
+	}
+
+	var issues []PathIssue
+	folded := make(map[string]string)
+	for fi := range m.mapDestKeys(ctx, m.applyFilters(ctx, files)) {
+		if fi.err != nil {
+			return nil, fi.err
+		}
+		name := filepath.ToSlash(fi.destKeyName())
+		for _, problem := range auditPath(name) {
+			issues = append(issues, PathIssue{Name: name, Problem: problem})
+		}
+		lower := strings.ToLower(name)
+		if other, ok := folded[lower]; ok {
+			issues = append(issues, PathIssue{Name: name, Problem: "differs only in case from " + other})
+			continue
+		}
+		folded[lower] = name
+	}
+	return issues, nil
+}
+
+// auditPath returns the problems of the path separated by slashes.
+func auditPath(name string) []string {
+	var problems []string
+	if strings.Contains(name, `\`) {
+		problems = append(problems, "contains a backslash which is a path separator on Windows")
+	}
+	if hasOddSegment(name) {
+		problems = append(problems, `contains an empty, "." or ".." segment`)
+	}
+	// Backslashes separate the segments on Windows as well.
+	for _, seg := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if seg == "." || seg == ".." {
+			continue
+		}
+		if len(seg) > maxPathSegmentLength {
+			problems = append(problems, fmt.Sprintf("segment %q is longer than %d bytes", seg[:16]+"...", maxPathSegmentLength))
+		}
+		if isWindowsReservedName(seg) {
+			problems = append(problems, fmt.Sprintf("segment %q is a reserved name on Windows", seg))
+		}
+		if i := strings.IndexFunc(seg, isWindowsInvalidRune); i >= 0 {
+			problems = append(problems, fmt.Sprintf("segment %q contains %q which is invalid on Windows", seg, seg[i]))
+		}
+		if strings.HasSuffix(seg, ".") || strings.HasSuffix(seg, " ") {
+			problems = append(problems, fmt.Sprintf("segment %q ends with a dot or a space which is dropped on Windows", seg))
+		}
+	}
+	return problems
+}
+
+// isWindowsReservedName returns true if the file name is reserved on Windows
+// regardless of the extension, e.g. "CON" and "nul.txt".
+func isWindowsReservedName(seg string) bool {
+	base := strings.ToUpper(strings.TrimRight(strings.SplitN(seg, ".", 2)[0], " "))
+	switch base {
+	case "CON", "PRN", "AUX", "NUL":
+		return true
+	}
+	if len(base) == 4 && (strings.HasPrefix(base, "COM") || strings.HasPrefix(base, "LPT")) {
+		return '1' <= base[3] && base[3] <= '9'
+	}
+	return false
+}
+
+// isWindowsInvalidRune returns true if the character is not allowed
+// in the file names on Windows.
+func isWindowsInvalidRune(r rune) bool {
+	return r < 0x20 || strings.ContainsRune(`<>:"|?*`, r)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestAuditPath(t *testing.T) {
+	testCases := map[string]int{
+		"dir/file.txt":                    0,
+		"dir/con.txt.bak":                 1,
+		"dir/COM0":                        0,
+		`dir\file.txt`:                    1,
+		"dir//file.txt":                   1,
+		"dir/../file.txt":                 1,
+		"dir/CON":                         1,
+		"nul.txt":                         1,
+		"dir/Com1.log":                    1,
+		"lpt9":                            1,
+		"dir/a:b.txt":                     1,
+		"dir/a?b":                         1,
+		"dir/a\x01b":                      1,
+		"dir./file":                       1,
+		"dir/file ":                       1,
+		strings.Repeat("a", 256):          1,
+		`aux\a|b`:                         3,
+		"dir/" + strings.Repeat("a", 255): 0,
+	}
+	for name, n := range testCases {
+		if problems := auditPath(name); len(problems) != n {
+			t.Errorf("%q: expected %d problems, got %v", name, n, problems)
+		}
+	}
+}
+
+func TestAuditPaths(t *testing.T) {
+	s := &dummyKeyRangeS3{keys: []string{
+		"prefix/README",
+		"prefix/Readme",
+		"prefix/dir/aux.go",
+		"prefix/dir/ok.go",
+		`prefix/dir\x.go`,
+		"prefix/skip/CON",
+	}}
+	m := New(session.New(), WithExclude("skip/*"))
+	m.s3 = s
+
+	issues, err := m.AuditPaths(context.Background(), "s3://bucket/prefix", "dest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, issue := range issues {
+		names = append(names, issue.Name)
+	}
+	expected := []string{"Readme", "dir/aux.go", `dir\x.go`}
+	if !reflect.DeepEqual(expected, names) {
+		t.Errorf("Expected issues of %v, got %v", expected, issues)
+	}
+
+	if _, err := m.AuditPaths(context.Background(), "a", "b"); err == nil {
+		t.Error("Expected local to local audit to fail")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// The reasons of the planned operations.
+const (
+	// PlanReasonNew is the reason of the file missing in the destination.
+	PlanReasonNew = "missing in the destination"
+	// PlanReasonChanged is the reason of the file differing from the destination.
+	PlanReasonChanged = "differs from the destination"
+	// PlanReasonNotInSource is the reason of the destination file missing in the source.
+	PlanReasonNotInSource = "missing in the source"
+	// PlanReasonUpToDate is the reason of the file skipped as up-to-date.
+	PlanReasonUpToDate = "up-to-date"
+	// PlanReasonPostponed is the reason of the file skipped since it is still being written.
+	PlanReasonPostponed = "recently modified"
+)
+
+// PlannedOperation is the operation of a file which the sync would perform.
+type PlannedOperation struct {
+	// Type is FileUploaded, FileDownloaded, FileCopied, FileDeleted or FileSkipped.
+	Type SyncEventType
+	// Path is the file path relative to the sync root.
+	Path string
+	Size int64
+	// Reason is one of the PlanReason constants.
+	Reason string
+}
+
+// SyncPlan is the list of the operations which the sync would perform.
+type SyncPlan struct {
+	Source string
+	Dest   string
+	// Operations is sorted by the path.
+	Operations []PlannedOperation
+}
+
+type planKey struct{}
+
+// planCollector collects the operations planned by a dry-run sync.
+type planCollector struct {
+	mu  sync.Mutex
+	ops []PlannedOperation
+}
+
+// Plan returns the operations which Sync would perform without performing them,
+// like the dry-run mode, for the programmatic review of the sync.
+// The options of the Manager are applied as they are.
+func (m *Manager) Plan(ctx context.Context, source, dest string) (*SyncPlan, error) {
+	c := &planCollector{}
+	if _, err := m.sync(context.WithValue(ctx, planKey{}, c), source, dest, nil); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sort.SliceStable(c.ops, func(i, j int) bool { return c.ops[i].Path < c.ops[j].Path })
+	return &SyncPlan{
+		Source:     source,
+		Dest:       dest,
+		Operations: c.ops,
+	}, nil
+}
+
+// isDryRun returns whether the operations are not performed in the dry-run mode
+// or by Plan.
+func (m *Manager) isDryRun(ctx context.Context) bool {
+	if m.dryrun {
+		return true
+	}
+	_, ok := ctx.Value(planKey{}).(*planCollector)
+	return ok
+}
+
+// recordPlan records the operation of the file to the plan.
+func (m *Manager) recordPlan(ctx context.Context, typ SyncEventType, file *fileInfo) {
+	c, ok := ctx.Value(planKey{}).(*planCollector)
+	if !ok {
+		return
+	}
+	op := PlannedOperation{Type: typ, Path: file.name, Size: file.size}
+	switch {
+	case typ == FileSkipped && file.postponed:
+		op.Reason = PlanReasonPostponed
+	case typ == FileSkipped:
+		op.Reason = PlanReasonUpToDate
+	case typ == FileDeleted:
+		op.Reason = PlanReasonNotInSource
+	case file.destExists:
+		op.Reason = PlanReasonChanged
+	default:
+		op.Reason = PlanReasonNew
+	}
+	c.mu.Lock()
+	c.ops = append(c.ops, op)
+	c.mu.Unlock()
+}
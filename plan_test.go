@@ -0,0 +1,90 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// dummyPlanS3 only lists the objects, so that Plan fails on the modifications.
+type dummyPlanS3 struct {
+	s3iface.S3API
+	objects []*s3.Object
+}
+
+func (s *dummyPlanS3) ListObjectsV2(in *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+	return &s3.ListObjectsV2Output{Contents: s.objects}, nil
+}
+
+func (s *dummyPlanS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	return s.ListObjectsV2(in)
+}
+
+func TestPlan(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, content := range map[string]string{"changed": "aa", "new": "a", "same": "a"} {
+		filename := filepath.Join(temp, name)
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	s := &dummyPlanS3{objects: []*s3.Object{
+		{Key: aws.String("prefix/changed"), Size: aws.Int64(1), LastModified: aws.Time(now)},
+		{Key: aws.String("prefix/same"), Size: aws.Int64(1), LastModified: aws.Time(now)},
+		{Key: aws.String("prefix/stale"), Size: aws.Int64(1), LastModified: aws.Time(now)},
+	}}
+	m := New(session.New(), WithDelete())
+	m.s3 = s
+
+	plan, err := m.Plan(context.Background(), temp, "s3://bucket/prefix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &SyncPlan{
+		Source: temp,
+		Dest:   "s3://bucket/prefix",
+		Operations: []PlannedOperation{
+			{Type: FileUploaded, Path: "changed", Size: 2, Reason: PlanReasonChanged},
+			{Type: FileUploaded, Path: "new", Size: 1, Reason: PlanReasonNew},
+			{Type: FileSkipped, Path: "same", Size: 1, Reason: PlanReasonUpToDate},
+			{Type: FileDeleted, Path: "stale", Size: 1, Reason: PlanReasonNotInSource},
+		},
+	}
+	if !reflect.DeepEqual(expected, plan) {
+		t.Errorf("Expected %+v, got %+v", expected, plan)
+	}
+}
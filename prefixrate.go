@@ -0,0 +1,79 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"path"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// prefixRateLimiter limits the rate of the write requests per key prefix of the destination.
+// S3 scales the request rate per prefix, so the requests concentrated on a prefix
+// are throttled by S3 and retried again and again if not limited on the client side.
+type prefixRateLimiter struct {
+	mu       sync.Mutex
+	rate     int64
+	limiters map[string]*rateLimiter
+}
+
+func newPrefixRateLimiter(requestsPerSec int64) *prefixRateLimiter {
+	return &prefixRateLimiter{
+		rate:     requestsPerSec,
+		limiters: make(map[string]*rateLimiter),
+	}
+}
+
+// get returns the rate limiter of the prefix of the object.
+func (l *prefixRateLimiter) get(bucket, key string) *rateLimiter {
+	if l == nil {
+		return nil
+	}
+	prefix := bucket + "/" + keyPrefix(key)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rl, ok := l.limiters[prefix]
+	if !ok {
+		rl = newRateLimiter(l.rate)
+		l.limiters[prefix] = rl
+	}
+	return rl
+}
+
+// keyPrefix returns the prefix of the key, i.e. the "directory" of the object.
+func keyPrefix(key string) string {
+	dir := path.Dir(key)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir + "/"
+}
+
+// putRateOption returns the request option to wait for the PUT rate limit
+// of the prefix of the object before sending the request.
+// The limit is applied on each attempt so that the retries are also limited.
+func (m *Manager) putRateOption(bucket, key string) request.Option {
+	l := m.putRate.get(bucket, key)
+	return func(r *request.Request) {
+		if l == nil {
+			return
+		}
+		r.Handlers.Sign.PushFront(func(r *request.Request) {
+			if err := l.wait(r.Context(), 1); err != nil {
+				r.Error = err
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestKeyPrefix(t *testing.T) {
+	testCases := map[string]string{
+		"file":         "",
+		"dir/file":     "dir/",
+		"dir/sub/file": "dir/sub/",
+		"/file":        "",
+	}
+	for key, expected := range testCases {
+		if prefix := keyPrefix(key); prefix != expected {
+			t.Errorf("Expected prefix of %s: %q, got: %q", key, expected, prefix)
+		}
+	}
+}
+
+func TestPutRateLimitPerPrefix(t *testing.T) {
+	const rate = 100
+	m := &Manager{}
+	WithPutRateLimitPerPrefix(rate)(m)
+
+	send := func(bucket, key string) error {
+		r := &request.Request{HTTPRequest: &http.Request{}}
+		r.SetContext(context.Background())
+		m.putRateOption(bucket, key)(r)
+		r.Handlers.Sign.Run(r)
+		return r.Error
+	}
+
+	// Initial burst is the amount of one second, and the other prefixes are not limited.
+	t0 := time.Now()
+	for i := 0; i < rate; i++ {
+		for _, key := range []string{"a/file", "b/file", "a/sub/file"} {
+			if err := send("bucket", key); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := send("bucket2", "a/file"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(t0); d > 200*time.Millisecond {
+		t.Errorf("Requests within the burst must not be limited, took %v", d)
+	}
+
+	t0 = time.Now()
+	for i := 0; i < rate/2; i++ {
+		if err := send("bucket", "a/another-file"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(t0); d < 400*time.Millisecond || d > 2*time.Second {
+		t.Errorf("Expected to take 0.5s, took %v", d)
+	}
+}
+
+func TestPutRateLimitPerPrefix_Cancel(t *testing.T) {
+	m := &Manager{}
+	WithPutRateLimitPerPrefix(1)(m)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		r := &request.Request{HTTPRequest: &http.Request{}}
+		r.SetContext(ctx)
+		m.putRateOption("bucket", "file")(r)
+		r.Handlers.Sign.Run(r)
+		err = r.Error
+	}
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestPutRateLimitPerPrefix_Disabled(t *testing.T) {
+	m := &Manager{}
+	WithPutRateLimitPerPrefix(0)(m)
+	r := &request.Request{}
+	m.putRateOption("bucket", "file")(r)
+	if n := r.Handlers.Sign.Len(); n != 0 {
+		t.Errorf("Handler must not be added without the limit, got %d handlers", n)
+	}
+}
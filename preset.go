@@ -0,0 +1,98 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import "fmt"
+
+// Preset is a name of the bundle of options suitable for a common use case.
+type Preset string
+
+const (
+	// PresetWebsite mirrors a static website: files removed from the
+	// source are deleted, files are compared by checksum since builds
+	// touch the modification time, the content type is guessed from contents,
+	// the objects are cached for DefaultWebsiteCacheControl, and the ETags
+	// of the uploaded files are verified.
+	PresetWebsite Preset = "website"
+	// PresetBackup keeps everything on the destination even if the
+	// source file is removed, compares the files by checksum not to miss
+	// any change, and verifies the ETags and the SHA-256 checksums of the
+	// transferred files.
+	PresetBackup Preset = "backup"
+	// PresetDataset mirrors a dataset of opaque binary files without
+	// guessing their content type, compares the files by the size and the
+	// modification time, and verifies the ETags of the transferred files.
+	PresetDataset Preset = "dataset"
+)
+
+// DefaultWebsiteCacheControl is Cache-Control of the objects uploaded by PresetWebsite.
+const DefaultWebsiteCacheControl = "public, max-age=300"
+
+var presets = map[Preset][]Option{
+	PresetWebsite: {
+		WithDelete(),
+		WithChecksum(),
+		WithCacheControl(DefaultWebsiteCacheControl),
+		WithVerify(VerifyETag),
+		func(m *Manager) {
+			m.guessMime = true
+		},
+	},
+	PresetBackup: {
+		func(m *Manager) {
+			m.del = false
+		},
+		WithChecksum(),
+		WithVerify(VerifyETag | VerifySHA256),
+	},
+	PresetDataset: {
+		WithDelete(),
+		WithoutGuessMimeType(),
+		WithComparator(DefaultComparator),
+		WithVerify(VerifyETag),
+	},
+}
+
+// WithPreset applies the bundle of options of the given preset.
+// Options specified after WithPreset overwrite the preset.
+// Unknown preset fails NewWithOptionsValidated and every sync of the Manager, e.g.
+// Sync, Migrate, BiSync and Watch, with ErrInvalidOption.
+// The presets verify the transfers, so they also fail with WithCompression and
+// the client-side encryption unless the verification is disabled by WithVerify(0).
+func WithPreset(p Preset) Option {
+	return func(m *Manager) {
+		opts, ok := presets[p]
+		if !ok {
+			m.unknownPreset = p
+			return
+		}
+		m.preset = p
+		for _, o := range opts {
+			o(m)
+		}
+	}
+}
+
+// checkPreset returns ErrInvalidOption if WithPreset is given an unknown preset,
+// or if the verification of the preset is combined with the transformed contents.
+func (m *Manager) checkPreset() error {
+	if m.unknownPreset != "" {
+		return fmt.Errorf("%w: unknown preset %q", ErrInvalidOption, m.unknownPreset)
+	}
+	if m.preset != "" && m.verifyMode != 0 && (m.compression != nil || m.encryption != nil) {
+		return fmt.Errorf("%w: preset %q verifies the transfers and can't be used with WithCompression or the client-side encryption",
+			ErrInvalidOption, m.preset)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// listDestS3Files lists the destination files to be compared with the source files.
+// It returns nil without the full listing if the destination is known empty
+// by WithInitialSeed or found empty by WithEmptyDestProbe.
+func (m *Manager) listDestS3Files(ctx context.Context, path *s3Path, patterns []*regexp.Regexp) chan *fileInfo {
+	if m.initialSeed {
+		return nil
+	}
+	if m.emptyDestProbe {
+		empty, err := m.emptyDest(ctx, path)
+		if err != nil {
+			c := make(chan *fileInfo, 1)
+			c <- &fileInfo{err: err}
+			close(c)
+			return c
+		}
+		if empty {
+			println("Destination", path.String(), "is empty, skipping the diff")
+			return nil
+		}
+	}
+	return m.listS3Files(ctx, path, patterns)
+}
+
+// emptyDest returns whether the destination has no object in the key range.
+func (m *Manager) emptyDest(ctx context.Context, path *s3Path) (bool, error) {
+	ctx, cancel := withTimeout(ctx, m.listTimeout)
+	defer cancel()
+	list, err := m.client(ctx, path).ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:     &path.bucket,
+		Prefix:     &path.bucketPrefix,
+		StartAfter: m.startAfter(path),
+		MaxKeys:    aws.Int64(1),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(list.Contents) == 0, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestListDestS3Files_EmptyDestProbe(t *testing.T) {
+	testCases := map[string]struct {
+		keys     []string
+		probe    bool
+		files    int
+		requests int
+	}{
+		"Empty":         {nil, true, 0, 1},
+		"Seed":          {[]string{"prefix/a"}, false, 0, 0},
+		"NotEmpty":      {[]string{"prefix/a", "prefix/b", "prefix/c"}, true, 3, 3},
+		"NoProbe":       {nil, false, 0, 1},
+		"NoProbeFilled": {[]string{"prefix/a", "prefix/b", "prefix/c"}, false, 3, 2},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			s := &dummyKeyRangeS3{keys: tc.keys}
+			m := New(session.New())
+			if tc.probe {
+				WithEmptyDestProbe()(m)
+			}
+			if name == "Seed" {
+				WithInitialSeed()(m)
+			}
+			m.s3 = s
+
+			var files int
+			listed := m.listDestS3Files(context.Background(), &s3Path{bucket: "bucket", bucketPrefix: "prefix"}, nil)
+			if listed == nil {
+				listed = make(chan *fileInfo)
+				close(listed)
+			}
+			for fi := range listed {
+				if fi.err != nil {
+					t.Fatal(fi.err)
+				}
+				files++
+			}
+			if files != tc.files {
+				t.Errorf("Expected %d files, got %d", tc.files, files)
+			}
+			if len(s.startAfter) != tc.requests {
+				t.Errorf("Expected %d list requests, got %d", tc.requests, len(s.startAfter))
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+// Progress is the snapshot of the progress of the running sync.
+type Progress struct {
+	// Files and Bytes are the number and the size of the source files
+	// processed (transferred, failed or skipped as up-to-date).
+	Files int64
+	Bytes int64
+	// TotalFiles and TotalBytes are the estimated totals of the source files.
+	// They are the exact values after the source listing is completed.
+	// Zero if unknown.
+	TotalFiles int64
+	TotalBytes int64
+	// ListingCompleted is true if the source listing is completed.
+	ListingCompleted bool
+	StartTime        time.Time
+}
+
+// Percent returns the progress in percent based on the bytes.
+// It returns -1 if the total is unknown.
+func (p Progress) Percent() float64 {
+	if p.TotalBytes <= 0 {
+		return -1
+	}
+	percent := float64(p.Bytes) * 100 / float64(p.TotalBytes)
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// ETA returns the estimated remaining time based on the bytes processed so far.
+// It returns -1 if it can't be estimated.
+func (p Progress) ETA() time.Duration {
+	if p.TotalBytes <= 0 || p.Bytes <= 0 {
+		return -1
+	}
+	if p.Bytes >= p.TotalBytes {
+		return 0
+	}
+	elapsed := time.Since(p.StartTime)
+	return time.Duration(float64(elapsed) * float64(p.TotalBytes-p.Bytes) / float64(p.Bytes))
+}
+
+type progressTracker struct {
+	mu       sync.Mutex
+	progress Progress
+	listed   Progress
+}
+
+func (t *progressTracker) reset(totalFiles, totalBytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = Progress{
+		TotalFiles: totalFiles,
+		TotalBytes: totalBytes,
+		StartTime:  time.Now(),
+	}
+	t.listed = Progress{}
+}
+
+func (t *progressTracker) snapshot() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
+
+// processed counts the source file as processed.
+func (t *progressTracker) processed(size int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress.Files++
+	t.progress.Bytes += size
+}
+
+// trackListing returns a channel which receives the given source file infos
+// counting the listed files to update the totals to the exact values on completion.
+func (t *progressTracker) trackListing(ctx context.Context, files chan *fileInfo) chan *fileInfo {
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for fi := range files {
+			if fi.err == nil {
+				t.mu.Lock()
+				t.listed.Files++
+				t.listed.Bytes += fi.size
+				t.mu.Unlock()
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+		t.mu.Lock()
+		t.progress.TotalFiles = t.listed.Files
+		t.progress.TotalBytes = t.listed.Bytes
+		t.progress.ListingCompleted = true
+		t.mu.Unlock()
+	}()
+	return c
+}
+
+// Progress returns the progress of the running or the last sync.
+func (m *Manager) Progress() Progress {
+	return m.progress.snapshot()
+}
+
+// startProgress resets the progress with the expected totals.
+// If the bucket metrics are enabled and the source is s3, the totals are
+// taken from CloudWatch metrics of the source bucket.
+func (m *Manager) startProgress(ctx context.Context, sourceURL *url.URL) {
+	totalFiles, totalBytes := m.expectedFiles, m.expectedBytes
+	if m.cloudWatch != nil && isS3URL(sourceURL) && totalFiles == 0 && totalBytes == 0 {
+		var err error
+		totalFiles, totalBytes, err = bucketMetrics(ctx, m.cloudWatch, sourceURL.Host)
+		if err != nil {
+			println("Failed to get bucket metrics:", err)
+		}
+	}
+	m.progress.reset(totalFiles, totalBytes)
+}
+
+// bucketMetrics returns the latest number of objects and the size of the bucket
+// from the daily storage metrics of CloudWatch.
+func bucketMetrics(ctx context.Context, cw cloudwatchiface.CloudWatchAPI, bucket string) (int64, int64, error) {
+	get := func(name, storageType string) (int64, error) {
+		now := time.Now()
+		out, err := cw.GetMetricStatisticsWithContext(ctx, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/S3"),
+			MetricName: aws.String(name),
+			Dimensions: []*cloudwatch.Dimension{
+				{Name: aws.String("BucketName"), Value: aws.String(bucket)},
+				{Name: aws.String("StorageType"), Value: aws.String(storageType)},
+			},
+			StartTime:  aws.Time(now.Add(-3 * 24 * time.Hour)),
+			EndTime:    aws.Time(now),
+			Period:     aws.Int64(86400),
+			Statistics: []*string{aws.String(cloudwatch.StatisticAverage)},
+		})
+		if err != nil {
+			return 0, err
+		}
+		var latest *cloudwatch.Datapoint
+		for _, d := range out.Datapoints {
+			if latest == nil || d.Timestamp.After(*latest.Timestamp) {
+				latest = d
+			}
+		}
+		if latest == nil {
+			return 0, nil
+		}
+		return int64(aws.Float64Value(latest.Average)), nil
+	}
+
+	files, err := get("NumberOfObjects", "AllStorageTypes")
+	if err != nil {
+		return 0, 0, err
+	}
+	bytes, err := get("BucketSizeBytes", "StandardStorage")
+	if err != nil {
+		return 0, 0, err
+	}
+	return files, bytes, nil
+}
+
+// ProgressEventType is the type of ProgressEvent.
+type ProgressEventType int
+
+const (
+	// ProgressFileStarted is fired when the transfer of a file is started.
+	ProgressFileStarted ProgressEventType = iota
+	// ProgressBytesTransferred is fired when a chunk of the file is transferred.
+	ProgressBytesTransferred
+	// ProgressFileCompleted is fired when the transfer of a file is finished
+	// regardless of whether it succeeded or failed.
+	ProgressFileCompleted
+)
+
+// ProgressEvent is the event of the file transfer passed to the progress function.
+type ProgressEvent struct {
+	Type ProgressEventType
+	// Op is the operation of the transfer: upload, download or copy.
+	Op   string
+	Name string
+	Size int64
+	// Bytes is the number of bytes newly transferred by this event.
+	Bytes int64
+	// TransferredBytes is the number of bytes of the file transferred so far.
+	TransferredBytes int64
+	// Err is the error of the transfer on ProgressFileCompleted event.
+	Err error
+	// Progress is the progress of the whole sync.
+	Progress Progress
+}
+
+// fileProgress fires the progress events of a file transfer.
+// Nil fileProgress is valid and does nothing.
+type fileProgress struct {
+	m     *Manager
+	op    string
+	name  string
+	size  int64
+	mu    sync.Mutex
+	spans [][2]int64 // sorted and merged ranges of the transferred bytes
+	total int64
+}
+
+// startFileProgress fires ProgressFileStarted event and returns fileProgress
+// of the file transfer, or nil if the progress function is not set.
+func (m *Manager) startFileProgress(op string, file *fileInfo) *fileProgress {
+	if m.progressFunc == nil {
+		return nil
+	}
+	p := &fileProgress{m: m, op: op, name: file.name, size: file.size}
+	p.fire(ProgressFileStarted, 0, nil)
+	return p
+}
+
+func (p *fileProgress) fire(typ ProgressEventType, n int64, err error) {
+	p.m.progressFunc(ProgressEvent{
+		Type:             typ,
+		Op:               p.op,
+		Name:             p.name,
+		Size:             p.size,
+		Bytes:            n,
+		TransferredBytes: p.total,
+		Err:              err,
+		Progress:         p.m.progress.snapshot(),
+	})
+}
+
+// add marks the range of the file as transferred.
+// Since the SDK may read the same range multiple times for signing and
+// retrying, only the bytes never marked before are counted.
+func (p *fileProgress) add(off, n int64) {
+	if p == nil || n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start, end := off, off+n
+	added := n
+	var spans [][2]int64
+	for _, s := range p.spans {
+		switch {
+		case s[1] < start || end < s[0]:
+			spans = append(spans, s)
+		default:
+			// Overlapping or adjacent range is merged.
+			overlap := minInt64(s[1], end) - maxInt64(s[0], start)
+			if overlap > 0 {
+				added -= overlap
+			}
+			start, end = minInt64(s[0], start), maxInt64(s[1], end)
+		}
+	}
+	spans = append(spans, [2]int64{start, end})
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	p.spans = spans
+
+	if added <= 0 {
+		return
+	}
+	p.total += added
+	p.fire(ProgressBytesTransferred, added, nil)
+}
+
+// finish fires ProgressFileCompleted event with the error.
+func (p *fileProgress) finish(err *error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fire(ProgressFileCompleted, 0, *err)
+}
+
+// wrapReader returns the reader reporting the read bytes as transferred.
+// io.Seeker and io.ReaderAt are kept available if the given reader implements them
+// since s3manager.Uploader uses them to avoid buffering.
+func (p *fileProgress) wrapReader(r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	pr := &progressReader{r: r, p: p}
+	if _, ok := r.(readSeekerAt); ok {
+		return &progressReadSeekerAt{pr}
+	}
+	return pr
+}
+
+// wrapWriterAt returns the writer reporting the written bytes as transferred.
+func (p *fileProgress) wrapWriterAt(w io.WriterAt) io.WriterAt {
+	if p == nil {
+		return w
+	}
+	return &progressWriterAt{w: w, p: p}
+}
+
+type readSeekerAt interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+type progressReader struct {
+	r   io.Reader
+	p   *fileProgress
+	pos int64
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.add(r.pos, int64(n))
+	r.pos += int64(n)
+	return n, err
+}
+
+type progressReadSeekerAt struct {
+	*progressReader
+}
+
+func (r *progressReadSeekerAt) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.r.(io.Seeker).Seek(offset, whence)
+	if err == nil {
+		r.pos = pos
+	}
+	return pos, err
+}
+
+func (r *progressReadSeekerAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := r.r.(io.ReaderAt).ReadAt(b, off)
+	r.p.add(off, int64(n))
+	return n, err
+}
+
+type progressWriterAt struct {
+	w io.WriterAt
+	p *fileProgress
+}
+
+func (w *progressWriterAt) WriteAt(b []byte, off int64) (int, error) {
+	n, err := w.w.WriteAt(b, off)
+	w.p.add(off, int64(n))
+	return n, err
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+type dummyCloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	values map[string][]float64
+}
+
+func (c *dummyCloudWatch) GetMetricStatisticsWithContext(ctx aws.Context, in *cloudwatch.GetMetricStatisticsInput, opts ...request.Option) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	out := &cloudwatch.GetMetricStatisticsOutput{}
+	t0 := time.Now().Add(-72 * time.Hour)
+	for i, v := range c.values[*in.MetricName] {
+		out.Datapoints = append(out.Datapoints, &cloudwatch.Datapoint{
+			Timestamp: aws.Time(t0.Add(time.Duration(i) * 24 * time.Hour)),
+			Average:   aws.Float64(v),
+		})
+	}
+	return out, nil
+}
+
+func TestProgress(t *testing.T) {
+	t.Run("Unknown", func(t *testing.T) {
+		p := Progress{Bytes: 10, StartTime: time.Now()}
+		if p.Percent() != -1 || p.ETA() != -1 {
+			t.Errorf("Progress without totals must be unknown, got %v %v", p.Percent(), p.ETA())
+		}
+	})
+	t.Run("Estimate", func(t *testing.T) {
+		p := Progress{Bytes: 25, TotalBytes: 100, StartTime: time.Now().Add(-time.Minute)}
+		if p.Percent() != 25 {
+			t.Errorf("Expected 25%%, got %v", p.Percent())
+		}
+		if eta := p.ETA(); eta < 3*time.Minute || eta > 3*time.Minute+time.Second {
+			t.Errorf("Expected ETA of 3 minutes, got %v", eta)
+		}
+	})
+}
+
+func TestProgressTracker_trackListing(t *testing.T) {
+	var tr progressTracker
+	tr.reset(100, 1000)
+
+	files := make(chan *fileInfo, 2)
+	files <- &fileInfo{name: "foo", size: 10}
+	files <- &fileInfo{name: "bar", size: 20}
+	close(files)
+
+	for fi := range tr.trackListing(context.Background(), files) {
+		if p := tr.snapshot(); p.ListingCompleted || p.TotalFiles != 100 {
+			t.Errorf("Expected totals must be kept during listing, got %+v", p)
+		}
+		tr.processed(fi.size)
+	}
+	p := tr.snapshot()
+	if !p.ListingCompleted || p.TotalFiles != 2 || p.TotalBytes != 30 || p.Files != 2 || p.Bytes != 30 {
+		t.Errorf("Unexpected progress %+v", p)
+	}
+}
+
+func TestBucketMetrics(t *testing.T) {
+	cw := &dummyCloudWatch{values: map[string][]float64{
+		"NumberOfObjects": {10, 20},
+		"BucketSizeBytes": {100, 200},
+	}}
+	files, bytes, err := bucketMetrics(context.Background(), cw, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 20 || bytes != 200 {
+		t.Errorf("Expected latest values 20 files 200 bytes, got %d files %d bytes", files, bytes)
+	}
+}
+
+func TestFileProgress(t *testing.T) {
+	var events []ProgressEvent
+	m := New(getSession(), WithProgressFunc(func(e ProgressEvent) {
+		events = append(events, e)
+	}))
+
+	p := m.startFileProgress("upload", &fileInfo{name: "foo", size: 10})
+	r := p.wrapReader(bytes.NewReader(make([]byte, 10)))
+	if _, ok := r.(readSeekerAt); !ok {
+		t.Fatal("Wrapped reader must implement io.Seeker and io.ReaderAt")
+	}
+	b := make([]byte, 4)
+	// Read twice the same range like signing and sending.
+	if _, err := r.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.(io.Seeker).Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.(io.ReaderAt).ReadAt(b, 6); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.(io.ReaderAt).ReadAt(b, 2); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	p.finish(&err)
+
+	expected := []struct {
+		typ         ProgressEventType
+		bytes       int64
+		transferred int64
+	}{
+		{ProgressFileStarted, 0, 0},
+		{ProgressBytesTransferred, 4, 4},
+		{ProgressBytesTransferred, 4, 8},
+		{ProgressBytesTransferred, 2, 10},
+		{ProgressFileCompleted, 0, 10},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, e := range expected {
+		if events[i].Type != e.typ || events[i].Bytes != e.bytes || events[i].TransferredBytes != e.transferred {
+			t.Errorf("Event %d is expected to be %+v, got %+v", i, e, events[i])
+		}
+		if events[i].Op != "upload" || events[i].Name != "foo" || events[i].Size != 10 {
+			t.Errorf("Event %d has unexpected file info %+v", i, events[i])
+		}
+	}
+}
+
+func TestFileProgress_Nil(t *testing.T) {
+	m := New(getSession())
+	p := m.startFileProgress("upload", &fileInfo{name: "foo", size: 10})
+	if p != nil {
+		t.Fatal("fileProgress must be nil without progress function")
+	}
+	r := bytes.NewReader(nil)
+	if p.wrapReader(r) != r {
+		t.Error("Reader must not be wrapped")
+	}
+	p.add(0, 10)
+	var err error
+	p.finish(&err)
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"time"
+)
+
+// SourceProvider provides a file content to be synced,
+// such as in-memory or dynamically generated one.
+type SourceProvider interface {
+	// Name returns the slash-separated path relative to the destination.
+	Name() string
+	// Size returns the size of the content in bytes.
+	Size() int64
+	// ModTime returns the modification time of the content.
+	ModTime() time.Time
+	// Open returns the reader of the content.
+	Open() (io.ReadCloser, error)
+}
+
+var errProviderDestNotS3 = errors.New("destination of the source providers must be s3 url")
+
+// SyncProviders syncs the contents given by the providers to the s3 destination.
+func (m *Manager) SyncProviders(ctx context.Context, providers []SourceProvider, dest string) (err error) {
+	ctx, done := m.trackCompletion(ctx, "providers", dest)
+	defer done(&err)
+
+	destURL, err := parseURL(dest)
+	if err != nil {
+		return err
+	}
+	if !isS3URL(destURL) {
+		return errProviderDestNotS3
+	}
+	if err := m.checkPreset(); err != nil {
+		return err
+	}
+	if err := m.checkDirection(false, true); err != nil {
+		return err
+	}
+	if err := m.checkBackup(true); err != nil {
+		return err
+	}
+	destS3Path, err := urlToS3Path(destURL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = m.withErrorPolicy(ctx, cancel)
+
+	m.progress.reset(m.expectedFiles, m.expectedBytes)
+
+	workers, stopWorkers := m.startWorkers()
+	defer stopWorkers()
+
+	if m.streamingDiff {
+		// Streaming diff requires the sorted listing.
+		providers = append([]SourceProvider{}, providers...)
+		sort.Slice(providers, func(i, j int) bool {
+			return providers[i].Name() < providers[j].Name()
+		})
+	}
+
+	return m.syncLocalToS3(ctx, workers, listProviders(ctx, providers), "", destS3Path, nil)
+}
+
+// listProviders returns a channel which receives the file infos of the given providers.
+func listProviders(ctx context.Context, providers []SourceProvider) chan *fileInfo {
+	c := make(chan *fileInfo)
+
+	go func() {
+		defer close(c)
+		for _, p := range providers {
+			name := path.Clean(p.Name())
+			fi := &fileInfo{
+				name:         name,
+				path:         name,
+				size:         p.Size(),
+				lastModified: p.ModTime(),
+				provider:     p,
+			}
+			select {
+			case c <- fi:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+type bytesProvider struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+func (p *bytesProvider) Name() string       { return p.name }
+func (p *bytesProvider) Size() int64        { return int64(len(p.data)) }
+func (p *bytesProvider) ModTime() time.Time { return p.modTime }
+func (p *bytesProvider) Open() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(p.data)), nil
+}
+
+func TestListProviders(t *testing.T) {
+	t0 := time.Now()
+	var names []string
+	for fi := range listProviders(context.Background(), []SourceProvider{
+		&bytesProvider{name: "foo/bar.txt", data: []byte("bar"), modTime: t0},
+		&bytesProvider{name: "./baz.txt", data: []byte("bazbaz"), modTime: t0},
+	}) {
+		if fi.provider == nil {
+			t.Errorf("%s doesn't have provider", fi.name)
+		}
+		if !fi.lastModified.Equal(t0) {
+			t.Errorf("%s has unexpected modification time %v", fi.name, fi.lastModified)
+		}
+		names = append(names, fi.name)
+	}
+	if len(names) != 2 || names[0] != "foo/bar.txt" || names[1] != "baz.txt" {
+		t.Fatalf("Unexpected file names %v", names)
+	}
+}
+
+func TestSyncProvidersNotS3(t *testing.T) {
+	err := New(getSession()).SyncProviders(context.Background(), nil, "local/dir")
+	if err != errProviderDestNotS3 {
+		t.Fatalf("Expected %v, got %v", errProviderDestNotS3, err)
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	const html = "<html><body>test</body></html>"
+	for name, r := range map[string]io.Reader{
+		"ReadSeeker": bytes.NewReader([]byte(html)),
+		"Reader":     ioutil.NopCloser(bytes.NewReader([]byte(html))),
+	} {
+		r := r
+		t.Run(name, func(t *testing.T) {
+			mime, body, err := detectContentType(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mime != "text/html; charset=utf-8" {
+				t.Errorf("Unexpected MIME type %s", mime)
+			}
+			b, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != html {
+				t.Errorf("Body is expected to be %s, got %s", html, string(b))
+			}
+		})
+	}
+}
+
+func TestSyncProviders(t *testing.T) {
+	err := New(getSession()).SyncProviders(context.Background(), []SourceProvider{
+		&bytesProvider{name: "index.html", data: []byte("<html><body>test</body></html>"), modTime: time.Now()},
+		&bytesProvider{name: "foo/data.bin", data: make([]byte, 10), modTime: time.Now()},
+	}, "s3://example-bucket-providers")
+	if err != nil {
+		t.Fatal("Sync should be successful", err)
+	}
+
+	objs := listObjectsSorted(t, "example-bucket-providers")
+	if n := len(objs); n != 2 {
+		t.Fatalf("Number of the files should be 2 (result: %v)", objs)
+	}
+	if objs[0].path != "foo/data.bin" || objs[0].size != 10 ||
+		objs[1].path != "index.html" || objs[1].contentType != "text/html; charset=utf-8" {
+		t.Error("Unexpected objects", objs)
+	}
+}
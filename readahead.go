@@ -0,0 +1,105 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// prefetchedFile is the content of the local file read ahead of the upload.
+type prefetchedFile struct {
+	data        []byte
+	contentType string
+}
+
+// bytesReadCloser is the io.ReadCloser of the prefetched content keeping
+// io.Seeker and io.ReaderAt available for detectContentType and the uploader.
+type bytesReadCloser struct {
+	*bytes.Reader
+}
+
+func (bytesReadCloser) Close() error {
+	return nil
+}
+
+// sourceFilename returns the local filename of the source file.
+func (m *Manager) sourceFilename(file *fileInfo, sourcePath string) string {
+	if file.singleFile {
+		return sourcePath
+	}
+	if filename, ok := m.localFilename(sourcePath, file.name); ok {
+		return filename
+	}
+	return filepath.Join(sourcePath, file.name)
+}
+
+// readAhead returns the channel of the file operations whose local files are read
+// into memory ahead of the upload, so that the next files are read while the current
+// uploads are in flight. The order of the operations is kept.
+// Up to readAheadFiles files not larger than readAheadSize are read concurrently.
+func (m *Manager) readAhead(ctx context.Context, ops chan *fileOp, sourcePath string) chan *fileOp {
+	if m.readAheadFiles <= 0 || m.stabilityDelay > 0 {
+		return ops
+	}
+	pending := make(chan chan *fileOp, m.readAheadFiles)
+	go func() {
+		defer close(pending)
+		for op := range ops {
+			r := make(chan *fileOp, 1)
+			pending <- r
+			go func(op *fileOp) {
+				m.prefetch(ctx, op, sourcePath)
+				r <- op
+			}(op)
+		}
+	}()
+
+	c := make(chan *fileOp)
+	go func() {
+		defer close(c)
+		for r := range pending {
+			c <- <-r
+		}
+	}()
+	return c
+}
+
+// prefetch reads the local file of the upload into memory.
+// The file is uploaded from the disk as usual if it fails.
+func (m *Manager) prefetch(ctx context.Context, op *fileOp, sourcePath string) {
+	if op.err != nil || op.op != opUpdate || op.provider != nil || op.symlink != "" ||
+		op.size > m.readAheadSize || ctx.Err() != nil {
+		return
+	}
+	f, err := os.Open(m.sourceFilename(op.fileInfo, sourcePath))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(m.limitLocalReader(ctx, f))
+	if err != nil {
+		return
+	}
+	p := &prefetchedFile{data: data}
+	if m.sniffContentType(op.fileInfo) {
+		if p.contentType, _, err = detectContentType(bytes.NewReader(data)); err != nil {
+			return
+		}
+	}
+	op.prefetched = p
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadAhead(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	files := map[string]string{"a": "aaa", "b": "bbb", "c": "ccc", "large": "large file"}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte(data), 0644); err != nil {
+			t.Fatal("Failed to write", err)
+		}
+	}
+
+	m := &Manager{guessMime: true}
+	WithReadAhead(2, 5)(m)
+
+	names := []string{"a", "large", "b", "missing", "c"}
+	ops := make(chan *fileOp)
+	go func() {
+		defer close(ops)
+		for _, name := range names {
+			ops <- &fileOp{fileInfo: &fileInfo{name: name, size: int64(len(files[name]))}}
+		}
+	}()
+
+	var i int
+	for op := range m.readAhead(context.Background(), ops, temp) {
+		if op.name != names[i] {
+			t.Errorf("Expected %s at %d, got %s", names[i], i, op.name)
+		}
+		i++
+		switch op.name {
+		case "large", "missing":
+			if op.prefetched != nil {
+				t.Errorf("Expected %s not to be read ahead", op.name)
+			}
+		default:
+			if op.prefetched == nil {
+				t.Errorf("Expected %s to be read ahead", op.name)
+				continue
+			}
+			if string(op.prefetched.data) != files[op.name] {
+				t.Errorf("Expected %q, got %q", files[op.name], op.prefetched.data)
+			}
+			if op.prefetched.contentType != "text/plain; charset=utf-8" {
+				t.Errorf("Unexpected content type %s", op.prefetched.contentType)
+			}
+		}
+	}
+	if i != len(names) {
+		t.Errorf("Expected %d operations, got %d", len(names), i)
+	}
+}
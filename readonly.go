@@ -0,0 +1,79 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// ErrReadOnly is returned if the sync requires modification in read-only mode.
+var ErrReadOnly = errors.New("modification is refused in read-only mode")
+
+// readOperationPrefixes are the prefixes of the S3 API operation names
+// which never modify the buckets.
+var readOperationPrefixes = []string{"Get", "Head", "List", "Select"}
+
+func isReadOperation(name string) bool {
+	for _, prefix := range readOperationPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectWriteOperation is the request handler which fails all S3 API
+// calls other than read operations.
+func rejectWriteOperation(r *request.Request) {
+	if !isReadOperation(r.Operation.Name) {
+		r.Error = fmt.Errorf("%w: %s", ErrReadOnly, r.Operation.Name)
+	}
+}
+
+// enforceReadOnly replaces the S3 clients by the copies refusing write operations
+// as a defense-in-depth of read-only mode. The clients given to the Manager are
+// not modified since they may be shared with the other code.
+func (m *Manager) enforceReadOnly() {
+	m.s3 = readOnlyClient(m.s3)
+	if m.sourceS3 != nil {
+		m.sourceS3 = readOnlyClient(m.sourceS3)
+	}
+}
+
+// readOnlyClient returns the copy of the client with the handler rejecting write operations.
+// The base client is returned as is if it is not *s3.S3.
+func readOnlyClient(base s3iface.S3API) s3iface.S3API {
+	c, ok := base.(*s3.S3)
+	if !ok {
+		return base
+	}
+	cc := *c.Client
+	cc.Handlers = c.Handlers.Copy()
+	cc.Handlers.Validate.PushFront(rejectWriteOperation)
+	return &s3.S3{Client: &cc}
+}
+
+// refuseIfReadOnly returns an error if the manager is in read-only mode.
+func (m *Manager) refuseIfReadOnly(action, name string) error {
+	if m.readOnly {
+		return fmt.Errorf("%w: %s %s", ErrReadOnly, action, name)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+func TestIsReadOperation(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"GetObject":     true,
+		"HeadObject":    true,
+		"ListObjectsV2": true,
+		"PutObject":     false,
+		"CopyObject":    false,
+		"DeleteObject":  false,
+		"UploadPart":    false,
+	} {
+		if ret := isReadOperation(name); ret != expected {
+			t.Errorf("isReadOperation(%s) is expected to be %v, got %v", name, expected, ret)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	sess := session.New(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("dummy"),
+		Endpoint:    aws.String("http://localhost:0"),
+	})
+
+	t.Run("RejectWriteAPI", func(t *testing.T) {
+		m := New(sess, WithReadOnly())
+		_, err := m.s3.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+		})
+		if !errors.Is(err, ErrReadOnly) {
+			t.Fatalf("Expected %v, got %v", ErrReadOnly, err)
+		}
+	})
+	t.Run("SharedClient", func(t *testing.T) {
+		client := s3.New(sess)
+		m := NewWithClients(client, client, WithReadOnly())
+		if m.s3 == s3iface.S3API(client) || m.sourceS3 == s3iface.S3API(client) {
+			t.Fatal("The given client must not be used as is")
+		}
+		if _, err := m.sourceS3.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+		}); !errors.Is(err, ErrReadOnly) {
+			t.Fatalf("Expected %v, got %v", ErrReadOnly, err)
+		}
+		if _, err := client.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String("key"),
+		}); errors.Is(err, ErrReadOnly) {
+			t.Fatal("The given client must not be read-only")
+		}
+	})
+	t.Run("RefuseUpload", func(t *testing.T) {
+		m := New(sess, WithReadOnly(), WithDryRun())
+		err := m.upload(context.Background(), &fileInfo{name: "test"}, "test", &s3Path{bucket: "bucket"})
+		if !errors.Is(err, ErrReadOnly) {
+			t.Fatalf("Expected %v, got %v", ErrReadOnly, err)
+		}
+	})
+	t.Run("RefuseDeleteLocal", func(t *testing.T) {
+		m := New(sess, WithReadOnly(), WithDryRun())
+		err := m.deleteLocal(context.Background(), &fileInfo{name: "test"}, "test")
+		if !errors.Is(err, ErrReadOnly) {
+			t.Fatalf("Expected %v, got %v", ErrReadOnly, err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FileResult is the outcome of the operation of a file.
+type FileResult struct {
+	// Path is the file path relative to the sync root.
+	Path string
+	Size int64
+	// Duration is the time taken by the file operation. Zero for skipped files.
+	Duration time.Duration
+	// Err is the error of the failed operation.
+	Err error
+}
+
+type resultCollectorKey struct{}
+
+// resultCollector collects the per-file outcomes of a sync from the events.
+type resultCollector struct {
+	mu sync.Mutex
+	r  SyncResult
+}
+
+func (c *resultCollector) add(ev SyncEvent) {
+	f := FileResult{Path: ev.Path, Size: ev.Size, Duration: ev.Duration, Err: ev.Err}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch ev.Type {
+	case FileUploaded:
+		c.r.Uploaded = append(c.r.Uploaded, f)
+	case FileDownloaded:
+		c.r.Downloaded = append(c.r.Downloaded, f)
+	case FileCopied:
+		c.r.Copied = append(c.r.Copied, f)
+	case FileDeleted:
+		c.r.Deleted = append(c.r.Deleted, f)
+	case FileSkipped:
+		c.r.Skipped = append(c.r.Skipped, f)
+	case FileFailed:
+		c.r.Failed = append(c.r.Failed, f)
+	}
+}
+
+// planned records the file operation skipped in dry-run mode to the itemized
+// output, the result of SyncWithResult and the plan of Plan.
+func (m *Manager) planned(ctx context.Context, typ SyncEventType, file *fileInfo) {
+	m.itemize(typ, file)
+	if c, ok := ctx.Value(resultCollectorKey{}).(*resultCollector); ok {
+		c.add(SyncEvent{Type: typ, Path: file.name, Size: file.size})
+	}
+	m.recordPlan(ctx, typ, file)
+}
+
+// SyncWithResult syncs the files like Sync, and returns the result of the sync
+// including the per-file outcomes in addition to the error.
+// Unlike GetStatistics, the result is not shared by the other syncs.
+// In dry-run mode, the result lists the planned operations, which can be
+// passed to EstimateCost.
+func (m *Manager) SyncWithResult(ctx context.Context, source, dest string) (*SyncResult, error) {
+	c := &resultCollector{}
+	startTime := time.Now()
+	ctx, stats := withCallStatistics(context.WithValue(ctx, resultCollectorKey{}, c))
+	_, err := m.sync(ctx, source, dest, nil)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := c.r
+	r.Source = source
+	r.Dest = dest
+	r.Statistics = stats.get()
+	r.StartTime = startTime
+	r.EndTime = time.Now()
+	r.Err = err
+	return &r, err
+}
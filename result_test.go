@@ -0,0 +1,129 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestSyncWithResult(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	for _, name := range []string{"b", "z"} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte("b"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}}
+	m := New(session.New(), WithDelete())
+	m.s3 = s
+
+	r, err := m.SyncWithResult(context.Background(), "s3://bucket/prefix", temp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(kind string, files []FileResult, expected string) {
+		t.Helper()
+		if len(files) != 1 || files[0].Path != expected {
+			t.Errorf("Expected %s to be %s, got %v", expected, kind, files)
+		}
+	}
+	check("downloaded", r.Downloaded, "a")
+	check("skipped", r.Skipped, "b")
+	check("deleted", r.Deleted, "z")
+	if len(r.Uploaded) != 0 || len(r.Copied) != 0 || len(r.Failed) != 0 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if r.Downloaded[0].Size != 1 || r.Downloaded[0].Duration <= 0 {
+		t.Errorf("Size and duration must be recorded, got %+v", r.Downloaded[0])
+	}
+	if r.Source != "s3://bucket/prefix" || r.Statistics.Files != 1 || r.Statistics.DeletedFiles != 1 {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if st := r.Statistics; st.SkippedFiles != 1 || st.FailedFiles != 0 || st.ListedObjects != 2 {
+		t.Errorf("Unexpected statistics %+v", st)
+	}
+	if st := r.Statistics; st.StartTime.IsZero() || st.EndTime.Before(st.StartTime) {
+		t.Errorf("Start and end time must be recorded, got %+v", st)
+	}
+}
+
+func TestSyncStatistics_Throughput(t *testing.T) {
+	t0 := time.Now()
+	testCases := map[string]struct {
+		stats    SyncStatistics
+		expected float64
+	}{
+		"Finished":    {SyncStatistics{Bytes: 300, StartTime: t0, EndTime: t0.Add(2 * time.Second)}, 150},
+		"NotFinished": {SyncStatistics{Bytes: 300, StartTime: t0}, 0},
+		"NotStarted":  {SyncStatistics{}, 0},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if v := tc.stats.Throughput(); v != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, v)
+			}
+		})
+	}
+}
+
+func TestSyncWithResult_Statistics(t *testing.T) {
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}}
+	m := New(session.New())
+	m.s3 = s
+
+	var wg sync.WaitGroup
+	results := make([]*SyncResult, 2)
+	for i := range results {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r, err := m.SyncWithResult(context.Background(), "s3://bucket/prefix", temp)
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = r
+		}(i)
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r != nil && (r.Statistics.Files != 2 || r.Statistics.Bytes != 2) {
+			t.Errorf("Statistics must be counted per sync, got %+v", r.Statistics)
+		}
+	}
+	if s := m.GetStatistics(); s.Files != 4 || s.ListedObjects != 4 {
+		t.Errorf("Cumulative statistics must count all syncs, got %+v", s)
+	}
+}
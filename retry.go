@@ -0,0 +1,89 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// BackoffFunc returns the delay before the n-th retry of the file operation, starting from 1.
+type BackoffFunc func(retry int) time.Duration
+
+// ExponentialBackoff returns the BackoffFunc of the exponential backoff with full jitter.
+// The delay is randomly chosen between 0 and base*2^(retry-1), capped by max.
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(retry int) time.Duration {
+		d := max
+		if retry < 32 && base<<uint(retry-1) < max {
+			d = base << uint(retry-1)
+		}
+		if d <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(d)))
+	}
+}
+
+// DefaultBackoff is the default BackoffFunc of WithRetries.
+var DefaultBackoff = ExponentialBackoff(time.Second, 30*time.Second)
+
+// isRetryableError returns whether the error of the file operation is transient,
+// i.e. throttling, server errors or connection errors of S3.
+func isRetryableError(err error) bool {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return false
+	}
+	if request.IsErrorRetryable(aerr) || request.IsErrorThrottle(aerr) {
+		return true
+	}
+	var rerr awserr.RequestFailure
+	return errors.As(err, &rerr) && rerr.StatusCode() >= 500
+}
+
+// willRetry returns whether the failed file operation run by retry will be retried.
+func (m *Manager) willRetry(ctx context.Context, err error) bool {
+	attempt, ok := ctx.Value(attemptKey{}).(int)
+	return ok && err != nil && attempt <= m.maxRetries && ctx.Err() == nil && isRetryableError(err)
+}
+
+// retry runs the file operation, and retries it on the transient errors
+// up to the number of times given by WithRetries.
+// The attempt number is passed to the operation by the context.
+func (m *Manager) retry(ctx context.Context, op func(context.Context) error) error {
+	backoff := m.backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		actx := withAttempt(ctx, attempt)
+		err := op(actx)
+		if !m.willRetry(actx, err) {
+			return err
+		}
+		t := time.NewTimer(backoff(attempt))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		}
+	}
+}
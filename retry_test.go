@@ -0,0 +1,124 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyFlakyS3 struct {
+	dummyKeyRangeS3
+	mu       sync.Mutex
+	calls    int
+	failures int
+	err      error
+}
+
+func (s *dummyFlakyS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return nil, s.err
+	}
+	return &s3.GetObjectOutput{
+		Body:          ioutil.NopCloser(strings.NewReader("a")),
+		ContentLength: aws.Int64(1),
+	}, nil
+}
+
+func TestWithRetries(t *testing.T) {
+	noDelay := func(int) time.Duration { return 0 }
+	slowDown := awserr.NewRequestFailure(awserr.New("SlowDown", "Please reduce your request rate.", nil), http.StatusServiceUnavailable, "")
+	internal := awserr.NewRequestFailure(awserr.New("InternalError", "", nil), http.StatusInternalServerError, "")
+	denied := awserr.NewRequestFailure(awserr.New("AccessDenied", "Access Denied", nil), http.StatusForbidden, "")
+
+	testCases := map[string]struct {
+		maxRetries    int
+		failures      int
+		err           error
+		expectedCalls int
+		failed        bool
+	}{
+		"Recovered":    {3, 2, slowDown, 3, false},
+		"ServerError":  {1, 1, internal, 2, false},
+		"Exhausted":    {2, 10, slowDown, 3, true},
+		"NotRetryable": {3, 10, denied, 1, true},
+		"Disabled":     {0, 1, slowDown, 1, true},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyFlakyS3{
+				dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}},
+				failures:        tc.failures,
+				err:             tc.err,
+			}
+			m := New(session.New(), WithRetries(tc.maxRetries, noDelay))
+			m.s3 = s
+
+			r, err := m.SyncWithResult(context.Background(), "s3://bucket/prefix", temp)
+			if (err != nil) != tc.failed {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if s.calls != tc.expectedCalls {
+				t.Errorf("Expected %d requests, got %d", tc.expectedCalls, s.calls)
+			}
+			// Failures of the retried attempts are not reported.
+			if tc.failed {
+				if len(r.Failed) != 1 || r.Statistics.FailedFiles != 1 {
+					t.Errorf("Expected 1 failure, got %v", r.Failed)
+				}
+			} else if len(r.Failed) != 0 || len(r.Downloaded) != 1 || r.Statistics.FailedFiles != 0 {
+				t.Errorf("Expected 1 download without failure, got %+v", r)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for retry, max := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		5:  time.Second,
+		64: time.Second,
+	} {
+		for i := 0; i < 100; i++ {
+			if d := backoff(retry); d < 0 || d >= max {
+				t.Fatalf("Expected delay of retry %d in [0, %v), got %v", retry, max, d)
+			}
+		}
+	}
+}
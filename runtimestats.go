@@ -0,0 +1,151 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RuntimeStats is the snapshot of the Go runtime sampled by WithRuntimeStats
+// during the sync, to tune the buffer sizes and the parallelism of huge syncs.
+type RuntimeStats struct {
+	// HeapAlloc is the bytes of the allocated heap objects.
+	HeapAlloc uint64
+	// Sys is the total bytes of the memory obtained from the OS.
+	Sys uint64
+	// NumGC is the number of the completed GC cycles.
+	NumGC      uint32
+	Goroutines int
+	// Backlogs are the numbers of the items buffered in the channels of the sync
+	// by the names like "source listing", which are waiting to be diffed or received.
+	Backlogs map[string]Backlog
+}
+
+// Backlog is the number of the items buffered in a channel and its capacity.
+type Backlog struct {
+	Len int
+	Cap int
+}
+
+type runtimeMonitorKey struct{}
+
+// runtimeMonitor samples the runtime stats of a sync call.
+type runtimeMonitor struct {
+	mu       sync.Mutex
+	backlogs map[string][]func() (int, int)
+}
+
+// startRuntimeStats starts sampling the runtime stats at the interval of WithRuntimeStats
+// until the returned function is called. The stats are emitted as RuntimeSampled events
+// and logged.
+func (m *Manager) startRuntimeStats(ctx context.Context) (context.Context, func()) {
+	if m.runtimeStatsInterval <= 0 {
+		return ctx, func() {}
+	}
+	mon := &runtimeMonitor{backlogs: make(map[string][]func() (int, int))}
+	ctx = context.WithValue(ctx, runtimeMonitorKey{}, mon)
+	mon.add("events", func() (int, int) {
+		m.eventsMu.Lock()
+		defer m.eventsMu.Unlock()
+		return len(m.events), cap(m.events)
+	})
+
+	// The sampling is canceled separately not to be blocked by the full events channel on stop.
+	sampleCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(m.runtimeStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.emitRuntimeStats(sampleCtx, mon.sample())
+			case <-sampleCtx.Done():
+				return
+			}
+		}
+	}()
+	return ctx, func() {
+		cancel()
+		<-stopped
+	}
+}
+
+// trackBacklog registers the channel buffer of the sync to be sampled by WithRuntimeStats.
+// The backlogs of the same name are summed.
+func trackBacklog(ctx context.Context, name string, backlog func() (int, int)) {
+	if mon, ok := ctx.Value(runtimeMonitorKey{}).(*runtimeMonitor); ok {
+		mon.add(name, backlog)
+	}
+}
+
+func (mon *runtimeMonitor) add(name string, backlog func() (int, int)) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+	mon.backlogs[name] = append(mon.backlogs[name], backlog)
+}
+
+func (mon *runtimeMonitor) sample() *RuntimeStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	stats := &RuntimeStats{
+		HeapAlloc:  ms.HeapAlloc,
+		Sys:        ms.Sys,
+		NumGC:      ms.NumGC,
+		Goroutines: runtime.NumGoroutine(),
+		Backlogs:   make(map[string]Backlog),
+	}
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+	for name, funcs := range mon.backlogs {
+		var b Backlog
+		for _, f := range funcs {
+			l, c := f()
+			b.Len += l
+			b.Cap += c
+		}
+		stats.Backlogs[name] = b
+	}
+	return stats
+}
+
+func (m *Manager) emitRuntimeStats(ctx context.Context, stats *RuntimeStats) {
+	msg := fmt.Sprintf("Runtime: heap %.1f MiB, sys %.1f MiB, %d GCs, %d goroutines",
+		float64(stats.HeapAlloc)/1024/1024, float64(stats.Sys)/1024/1024, stats.NumGC, stats.Goroutines)
+	attrs := []interface{}{
+		"op", "runtime",
+		"heap_alloc", stats.HeapAlloc,
+		"sys", stats.Sys,
+		"num_gc", stats.NumGC,
+		"goroutines", stats.Goroutines,
+	}
+	names := make([]string, 0, len(stats.Backlogs))
+	for name := range stats.Backlogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b := stats.Backlogs[name]
+		msg += fmt.Sprintf(", %s backlog %d/%d", name, b.Len, b.Cap)
+		attrs = append(attrs, "backlog_"+name, b.Len)
+	}
+	logOp(ctx, attrs, msg)
+	m.emit(ctx, SyncEvent{Type: RuntimeSampled, Runtime: stats})
+}
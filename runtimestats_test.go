@@ -0,0 +1,105 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummySlowS3 struct {
+	dummyBudgetS3
+}
+
+func (s *dummySlowS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	time.Sleep(20 * time.Millisecond)
+	return s.dummyBudgetS3.GetObjectWithContext(ctx, in, opts...)
+}
+
+func TestRuntimeStats(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	s := &dummySlowS3{dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}}}}
+	m := New(session.New(), WithRuntimeStats(time.Millisecond), WithParallel(1))
+	m.s3 = s
+	events := m.Events()
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats *RuntimeStats
+	for len(events) > 0 {
+		if ev := <-events; ev.Type == RuntimeSampled {
+			stats = ev.Runtime
+		}
+	}
+	if stats == nil {
+		t.Fatal("Expected the runtime stats to be emitted")
+	}
+	if stats.HeapAlloc == 0 || stats.Sys == 0 || stats.Goroutines == 0 {
+		t.Errorf("Expected the runtime stats to be sampled, got %+v", stats)
+	}
+	if b, ok := stats.Backlogs["source listing"]; !ok || b.Cap != 50000 {
+		t.Errorf("Expected the backlog of the source listing, got %+v", stats.Backlogs)
+	}
+	if b, ok := stats.Backlogs["events"]; !ok || b.Cap != eventsBufferSize {
+		t.Errorf("Expected the backlog of the events, got %+v", stats.Backlogs)
+	}
+}
+
+func TestRuntimeStats_Stop(t *testing.T) {
+	m := New(session.New(), WithRuntimeStats(time.Millisecond))
+	// The events are never received.
+	m.events = make(chan SyncEvent)
+	_, stop := m.startRuntimeStats(context.Background())
+	time.Sleep(10 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sampling must be stopped even if the events are not received")
+	}
+}
+
+func TestRuntimeStats_Backlogs(t *testing.T) {
+	m := New(session.New(), WithRuntimeStats(time.Hour))
+	ctx, stop := m.startRuntimeStats(context.Background())
+	defer stop()
+
+	trackBacklog(ctx, "listing", func() (int, int) { return 1, 10 })
+	trackBacklog(ctx, "listing", func() (int, int) { return 2, 10 })
+	stats := ctx.Value(runtimeMonitorKey{}).(*runtimeMonitor).sample()
+	if b := stats.Backlogs["listing"]; b.Len != 3 || b.Cap != 20 {
+		t.Errorf("Expected the backlogs to be summed, got %+v", b)
+	}
+	// Nothing is tracked without WithRuntimeStats.
+	trackBacklog(context.Background(), "listing", func() (int, int) { return 1, 1 })
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+var errNoEventQueueClient = errors.New("SyncFromEvents requires WithEventQueueClient")
+
+// s3EventMessage is the S3 event notification delivered to the SQS queue
+// directly or through SNS.
+type s3EventMessage struct {
+	Records []s3EventRecord `json:"Records"`
+	// Type and Message are the SNS envelope of the notification.
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// s3EventRecord is a record of the S3 event notification.
+type s3EventRecord struct {
+	EventName string `json:"eventName"`
+	S3        struct {
+		Bucket struct {
+			Name string `json:"name"`
+		} `json:"bucket"`
+		Object struct {
+			// Key is URL encoded.
+			Key string `json:"key"`
+		} `json:"object"`
+	} `json:"s3"`
+}
+
+// parseS3EventMessage returns the records of the S3 event notification.
+// The test event sent on the configuration of the notification has no record.
+func parseS3EventMessage(body string) ([]s3EventRecord, error) {
+	var msg s3EventMessage
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		return nil, err
+	}
+	if msg.Type == "Notification" && msg.Message != "" {
+		return parseS3EventMessage(msg.Message)
+	}
+	return msg.Records, nil
+}
+
+// SyncFromEvents mirrors the changes of the S3 bucket to the local directory
+// by consuming the s3:ObjectCreated:* and s3:ObjectRemoved:* event notifications
+// from the SQS queue given by WithEventQueueClient and queueURL, instead of listing
+// the whole bucket, until the context is canceled.
+// The object keys are mirrored to the relative paths under destPath, and the events
+// of the keys excluded by the filters are ignored.
+// Since the events may be delivered out of order or more than once, the current
+// state of the object is applied, i.e. the object is downloaded if it exists,
+// and otherwise the local file is deleted if WithDelete is given.
+// The messages are deleted after all of their records are applied, and the failed
+// ones are retried after the visibility timeout of the queue.
+// Run Sync once before to mirror the objects created before the notification is configured.
+// It returns nil when the context is canceled.
+func (m *Manager) SyncFromEvents(ctx context.Context, queueURL, destPath string) error {
+	if m.sqs == nil {
+		return errNoEventQueueClient
+	}
+	if err := m.checkPreset(); err != nil {
+		return err
+	}
+	for {
+		out, err := m.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: aws.Int64(10),
+			WaitTimeSeconds:     aws.Int64(int64(DefaultShardWaitTime / time.Second)),
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, msg := range m.applyEventMessages(ctx, out.Messages, destPath) {
+			if _, err := m.sqs.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(queueURL),
+				ReceiptHandle: msg.ReceiptHandle,
+			}); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+// applyEventMessages applies the records of the messages to the local directory
+// and returns the messages successfully applied.
+func (m *Manager) applyEventMessages(ctx context.Context, msgs []*sqs.Message, destPath string) []*sqs.Message {
+	var mu sync.Mutex
+	var applied []*sqs.Message
+	wg := &sync.WaitGroup{}
+	workers, stopWorkers := m.startWorkers()
+	defer stopWorkers()
+
+	for _, msg := range msgs {
+		msg := msg
+		records, err := parseS3EventMessage(aws.StringValue(msg.Body))
+		if err != nil {
+			println("Failed to parse the event message", aws.StringValue(msg.MessageId)+":", err.Error())
+			continue
+		}
+		wg.Add(1)
+		workers.run(0, func() {
+			defer wg.Done()
+			for _, record := range records {
+				if err := m.applyEvent(ctx, record, destPath); err != nil {
+					println("Failed to apply the event of", record.S3.Object.Key+":", err.Error())
+					return
+				}
+			}
+			mu.Lock()
+			applied = append(applied, msg)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return applied
+}
+
+// applyEvent applies the current state of the object notified by the event record.
+func (m *Manager) applyEvent(ctx context.Context, record s3EventRecord, destPath string) error {
+	if !strings.HasPrefix(record.EventName, "ObjectCreated:") && !strings.HasPrefix(record.EventName, "ObjectRemoved:") {
+		return nil
+	}
+	key, err := url.QueryUnescape(record.S3.Object.Key)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(key, "/") || !m.included(key) {
+		return nil
+	}
+	sourcePath := &s3Path{bucket: record.S3.Bucket.Name, source: true}
+	file := &fileInfo{name: filepath.FromSlash(key)}
+	filename, ok := m.localFilename(destPath, file.name)
+	if !ok {
+		println("Skipping", key, "which can't be mapped to a local path")
+		return nil
+	}
+
+	err = m.retry(ctx, func(ctx context.Context) error {
+		ctx, cancel := withTimeout(ctx, m.opTimeout)
+		defer cancel()
+		head, err := m.client(ctx, sourcePath).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket:               aws.String(sourcePath.bucket),
+			Key:                  aws.String(key),
+			SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+			SSECustomerKey:       m.sseCustomerKey,
+		})
+		if err != nil {
+			return err
+		}
+		file.size = aws.Int64Value(head.ContentLength)
+		file.lastModified = aws.TimeValue(head.LastModified)
+		return nil
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+		if !m.del {
+			return nil
+		}
+		if _, err := os.Lstat(filename); os.IsNotExist(err) {
+			return nil
+		}
+		return m.deleteLocal(ctx, file, destPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	if stat, err := os.Stat(filename); err == nil {
+		file.overwritten = &fileInfo{name: file.name, path: filename, size: stat.Size(), lastModified: stat.ModTime(), local: true}
+		if !m.fileComparator().ShouldSync(file.export(), file.overwritten.export()) {
+			return nil
+		}
+	}
+	return m.retry(ctx, func(ctx context.Context) error {
+		return m.download(ctx, file, sourcePath, destPath)
+	})
+}
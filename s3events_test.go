@@ -0,0 +1,96 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyEventS3 struct {
+	dummyBudgetS3
+	objects map[string]bool
+}
+
+func (s *dummyEventS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	if !s.objects[*in.Bucket+"/"+*in.Key] {
+		return nil, awserr.New("NotFound", "Not Found", nil)
+	}
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(1),
+		LastModified:  aws.Time(time.Unix(1600000000, 0)),
+	}, nil
+}
+
+func TestSyncFromEvents(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	if err := ioutil.WriteFile(filepath.Join(temp, "removed"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &dummyEventS3{objects: map[string]bool{"bucket/dir/a b": true}}
+	q := &dummySQS{queues: map[string][]string{"queue": {
+		`{"Records":[{"eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"bucket"},"object":{"key":"dir/a+b"}}}]}`,
+		`{"Records":[{"eventName":"ObjectRemoved:Delete","s3":{"bucket":{"name":"bucket"},"object":{"key":"removed"}}}]}`,
+		`{"Type":"Notification","Message":"{\"Records\":[{\"eventName\":\"ObjectRemoved:Delete\",\"s3\":{\"bucket\":{\"name\":\"bucket\"},\"object\":{\"key\":\"missing\"}}}]}"}`,
+		`{"Service":"Amazon S3","Event":"s3:TestEvent"}`,
+		`invalid`,
+	}}, inFlight: map[string]string{}}
+	m := New(session.New(), WithDelete(), WithEventQueueClient(q))
+	m.s3 = s
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := m.SyncFromEvents(ctx, "queue", temp); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"dir/a b"}; !reflect.DeepEqual(expected, s.downloaded) {
+		t.Errorf("Expected downloads %v, got %v", expected, s.downloaded)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(temp, "dir", "a b")); err != nil || string(data) != "a" {
+		t.Errorf("Expected the object to be downloaded, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(temp, "removed")); !os.IsNotExist(err) {
+		t.Errorf("Expected the removed object to be deleted, got %v", err)
+	}
+	if q.deleted != 4 {
+		t.Errorf("Expected 4 messages deleted, got %d", q.deleted)
+	}
+	if len(q.inFlight) != 1 {
+		t.Errorf("Expected the invalid message to be left, got %v", q.inFlight)
+	}
+}
+
+func TestSyncFromEvents_NoClient(t *testing.T) {
+	m := New(session.New())
+	if err := m.SyncFromEvents(context.Background(), "queue", "dest"); err != errNoEventQueueClient {
+		t.Errorf("Expected %v, got %v", errNoEventQueueClient, err)
+	}
+}
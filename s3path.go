@@ -15,9 +15,13 @@ package s3sync
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
 )
 
 var errNoBucketName = errors.New("s3 url is missing bucket name")
@@ -25,6 +29,41 @@ var errNoBucketName = errors.New("s3 url is missing bucket name")
 type s3Path struct {
 	bucket       string
 	bucketPrefix string
+	// source is whether the path is the source of the sync,
+	// which is accessed by the source client.
+	source bool
+}
+
+// parseURL parses the source or destination url.
+// The access point ARN, including the S3 Object Lambda access point, is accepted
+// as the bucket name of the s3 url like "s3://arn:aws:s3-object-lambda:region:account:accesspoint/name/prefix",
+// which can't be parsed by url.Parse because of the colons.
+func parseURL(s string) (*url.URL, error) {
+	const s3Scheme = "s3://"
+	if !strings.HasPrefix(s, s3Scheme+"arn:") {
+		return url.Parse(s)
+	}
+	a, err := arn.Parse(strings.TrimPrefix(s, s3Scheme))
+	if err != nil {
+		return nil, err
+	}
+	// Resource is "accesspoint/name/prefix" or "accesspoint:name/prefix".
+	i := strings.IndexAny(a.Resource, "/:")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid access point ARN: %s", a.String())
+	}
+	name, prefix := a.Resource[i+1:], ""
+	if j := strings.Index(name, "/"); j >= 0 {
+		name, prefix = name[:j], name[j:]
+	}
+	a.Resource = a.Resource[:i+1] + name
+	return &url.URL{Scheme: "s3", Host: a.String(), Path: prefix}, nil
+}
+
+// isObjectLambda returns whether the bucket is the ARN of the S3 Object Lambda access point.
+func isObjectLambda(bucket string) bool {
+	a, err := arn.Parse(bucket)
+	return err == nil && a.Service == "s3-object-lambda"
 }
 
 func urlToS3Path(url *url.URL) (*s3Path, error) {
@@ -48,3 +87,36 @@ func urlToS3Path(url *url.URL) (*s3Path, error) {
 func (p *s3Path) String() string {
 	return "s3://" + p.bucket + "/" + p.bucketPrefix
 }
+
+// encodeCopySource returns the URL-encoded CopySource parameter of the object.
+// All characters except the unreserved ones and slashes are percent-encoded
+// since S3 decodes some of the reserved characters like "+" in the CopySource.
+func encodeCopySource(bucket, key string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.WriteString(bucket)
+	b.WriteByte('/')
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+	return b.String()
+}
+
+// copySource returns the encoded CopySource parameter of the object,
+// logging the raw and encoded keys if WithEncodedKeyLog is set.
+func (m *Manager) copySource(bucket, key string) string {
+	s := encodeCopySource(bucket, key)
+	if m.logEncodedKeys {
+		println("CopySource of", strconv.Quote(bucket+"/"+key), "is encoded to", s)
+	}
+	return s
+}
@@ -16,7 +16,10 @@ package s3sync
 import (
 	"fmt"
 	"net/url"
+	"strings"
 	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
 )
 
 func assertS3Path(t *testing.T, expectedBucket, expectedPrefix string, p *s3Path) {
@@ -67,6 +70,55 @@ func TestURLToS3Path(t *testing.T) {
 	})
 }
 
+func TestParseURL_AccessPointARN(t *testing.T) {
+	const olap = "arn:aws:s3-object-lambda:us-east-1:123456789012:accesspoint/olap"
+	testCases := map[string]struct {
+		url            string
+		expectedBucket string
+		expectedPrefix string
+		objectLambda   bool
+	}{
+		"Bucket":             {"s3://bucket/prefix", "bucket", "prefix", false},
+		"ObjectLambda":       {"s3://" + olap, olap, "", true},
+		"ObjectLambdaPrefix": {"s3://" + olap + "/path/to/dir", olap, "path/to/dir", true},
+		"AccessPoint": {
+			"s3://arn:aws:s3:us-east-1:123456789012:accesspoint/ap/dir",
+			"arn:aws:s3:us-east-1:123456789012:accesspoint/ap", "dir", false,
+		},
+		"ColonSeparated": {
+			"s3://arn:aws:s3-object-lambda:us-east-1:123456789012:accesspoint:olap/dir",
+			"arn:aws:s3-object-lambda:us-east-1:123456789012:accesspoint:olap", "dir", true,
+		},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			u, err := parseURL(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isS3URL(u) {
+				t.Fatalf("Expected s3 url, got %v", u)
+			}
+			p, err := urlToS3Path(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertS3Path(t, tc.expectedBucket, tc.expectedPrefix, p)
+			if isObjectLambda(p.bucket) != tc.objectLambda {
+				t.Errorf("Expected isObjectLambda to be %v", tc.objectLambda)
+			}
+		})
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range []string{"s3://arn:aws:s3", "s3://arn:aws:s3:us-east-1:123456789012:accesspoint"} {
+			if _, err := parseURL(s); err == nil {
+				t.Errorf("Expected error for %s", s)
+			}
+		}
+	})
+}
+
 func TestS3Path_String(t *testing.T) {
 	p := &s3Path{
 		bucket:       "bucket",
@@ -77,3 +129,40 @@ func TestS3Path_String(t *testing.T) {
 		t.Fatalf("Expected %s, got %s", expected, s)
 	}
 }
+
+func TestEncodeCopySource(t *testing.T) {
+	testCases := map[string]string{
+		"dir/file.txt":     "bucket/dir/file.txt",
+		"dir/a+b c.txt":    "bucket/dir/a%2Bb%20c.txt",
+		"dir/#1?x=y&z.txt": "bucket/dir/%231%3Fx%3Dy%26z.txt",
+		"dir/日本.txt":       "bucket/dir/%E6%97%A5%E6%9C%AC.txt",
+		"dir/100%.txt":     "bucket/dir/100%25.txt",
+	}
+	for key, expected := range testCases {
+		if s := encodeCopySource("bucket", key); s != expected {
+			t.Errorf("%s: expected %s, got %s", key, expected, s)
+		}
+		if u, err := url.PathUnescape(expected); err != nil || u != "bucket/"+key {
+			t.Errorf("%s: encoded value must be decoded to the key, got %s (%v)", key, u, err)
+		}
+	}
+}
+
+func TestEncodedKeyLog(t *testing.T) {
+	var logs []string
+	SetLogger(createLoggerWithLogFunc(func(v ...interface{}) {
+		logs = append(logs, fmt.Sprint(v...))
+	}))
+	defer SetLogger(nil)
+
+	New(session.New()).copySource("bucket", "a b")
+	if len(logs) != 0 {
+		t.Fatalf("Expected no logs by default, got %v", logs)
+	}
+	if s := New(session.New(), WithEncodedKeyLog()).copySource("bucket", "a b"); s != "bucket/a%20b" {
+		t.Errorf("Unexpected CopySource %s", s)
+	}
+	if len(logs) != 1 || !strings.Contains(logs[0], `"bucket/a b"`) || !strings.Contains(logs[0], "bucket/a%20b") {
+		t.Errorf("Expected the raw and encoded keys to be logged, got %v", logs)
+	}
+}
@@ -13,36 +13,152 @@
 package s3sync
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/gabriel-vasile/mimetype"
 )
 
 // Manager manages the sync operation.
 type Manager struct {
-	s3             s3iface.S3API
-	nJobs          int
-	del            bool
-	dryrun         bool
-	acl            *string
-	guessMime      bool
-	contentType    *string
-	downloaderOpts []func(*s3manager.Downloader)
-	uploaderOpts   []func(*s3manager.Uploader)
-	statistics     SyncStatistics
+	s3                    s3iface.S3API
+	sourceS3              s3iface.S3API
+	nJobs                 int
+	keyRangeStart         string
+	keyRangeEnd           string
+	streamingDiff         bool
+	direction             Direction
+	executor              Executor
+	del                   bool
+	dryrun                bool
+	readOnly              bool
+	acl                   *string
+	sse                   *string
+	sseKMSKeyID           *string
+	sseCustomerAlgorithm  *string
+	sseCustomerKey        *string
+	copyACL               bool
+	guessMime             bool
+	unknownPreset         Preset
+	preset                Preset
+	ownership             bool
+	ownerNames            bool
+	contentType           *string
+	contentTypeMap        map[string]string
+	sniffMaxSize          int64
+	compression           *compressionRule
+	encryption            *clientSideEncryption
+	metadata              map[string]string
+	cacheControl          *string
+	contentEncoding       *string
+	storageClass          *string
+	tagging               *string
+	uploadInputModifier   func(FileInfo, *s3manager.UploadInput)
+	filters               []filterRule
+	skipRecent            time.Duration
+	stabilityDelay        time.Duration
+	manifestName          string
+	manifestHMACKey       []byte
+	opTimeout             time.Duration
+	listTimeout           time.Duration
+	downloaderOpts        []func(*s3manager.Downloader)
+	uploaderOpts          []func(*s3manager.Uploader)
+	downloaderConcurrency int
+	downloadPartSize      int64
+	uploaderConcurrency   int
+	uploadPartSize        int64
+	copyConcurrency       int
+	copyPartSize          int64
+	timeBudgetMargin      time.Duration
+	symlinkPolicy         SymlinkPolicy
+	cleanupOlderThan      time.Duration
+	downloaderOnce        sync.Once
+	downloader            *s3manager.Downloader
+	uploaderOnce          sync.Once
+	uploader              *s3manager.Uploader
+	bandwidth             *rateLimiter
+	putRate               *prefixRateLimiter
+	listingCachePath      string
+	listingCacheTTL       time.Duration
+	errorPolicy           ErrorPolicy
+	maxRetries            int
+	backoff               BackoffFunc
+	checksumPolicy        ChecksumPolicy
+	clientPool            *clientPool
+	accelerator           *accelerator
+	endpoint              string
+	pathStyle             bool
+	bypassGovernance      *bool
+	largeFileThreshold    int64
+	largeFileWorkers      int
+	deleteOrdering        DeleteOrdering
+	deleteList            func(dest string, names []string) error
+	localIO               localIOLimiter
+	backup                string
+	trash                 string
+	trashRetention        time.Duration
+	readAheadFiles        int
+	readAheadSize         int64
+	itemizer              *itemizer
+	watchInterval         time.Duration
+	emptyDestProbe        bool
+	initialSeed           bool
+	sqs                   sqsiface.SQSAPI
+	comparator            Comparator
+	clockSkew             time.Duration
+	keyMappers            []func(string) string
+	oddKeyPolicy          OddKeyPolicy
+	logEncodedKeys        bool
+	versionTracking       bool
+	verifyMode            VerifyMode
+	hashWorkers           int
+	runtimeStatsInterval  time.Duration
+	glacierPolicy         GlacierPolicy
+	preserveEmptyDirs     bool
+	maxDepth              int
+	nameCodec             NameCodec
+	ignoreHidden          bool
+	ignoreFile            bool
+	warmStartPath         string
+	warmStartMaxAge       time.Duration
+	minSize               int64
+	maxSize               int64
+	modifiedAfter         time.Time
+	modifiedBefore        time.Time
+	deleteExcluded        bool
+	biSyncStatePath       string
+	biSyncMaxDelete       int
+	stateFilePath         string
+	expectedFiles         int64
+	expectedBytes         int64
+	cloudWatch            cloudwatchiface.CloudWatchAPI
+	progress              progressTracker
+	progressFunc          func(ProgressEvent)
+	onComplete            func(SyncResult)
+	notifiers             []Notifier
+	events                chan SyncEvent
+	eventsMu              sync.Mutex
+	statistics            SyncStatistics
+	statisticsMu          sync.RWMutex
 }
 
 // SyncStatistics captures the sync statistics.
@@ -50,7 +166,47 @@ type SyncStatistics struct {
 	Bytes        int64
 	Files        int64
 	DeletedFiles int64
-	mutex        sync.RWMutex
+	// SkippedFiles is the number of the source files skipped as up-to-date or postponed.
+	SkippedFiles int64
+	// FailedFiles is the number of the file operations failed.
+	FailedFiles int64
+	// ListedObjects is the number of the objects listed from S3,
+	// including both of the source and destination listings.
+	ListedObjects int64
+	// StartTime is the time the (first) sync started.
+	StartTime time.Time
+	// EndTime is the time the (last) sync finished, or zero if not finished yet.
+	EndTime time.Time
+}
+
+// Throughput returns the average transfer rate in bytes per second
+// between StartTime and EndTime. It returns 0 if the sync is not finished.
+func (s SyncStatistics) Throughput() float64 {
+	d := s.EndTime.Sub(s.StartTime)
+	if s.EndTime.IsZero() || d <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / d.Seconds()
+}
+
+// SyncResult is the result of a sync passed to the completion callback.
+type SyncResult struct {
+	Source string
+	Dest   string
+	// Statistics is the difference of the Manager statistics during the sync.
+	Statistics SyncStatistics
+	StartTime  time.Time
+	EndTime    time.Time
+	// Err is the error returned by the sync, or nil if succeeded.
+	Err error
+
+	// Per-file outcomes, only available in the result of SyncWithResult.
+	Uploaded   []FileResult
+	Downloaded []FileResult
+	Copied     []FileResult
+	Deleted    []FileResult
+	Skipped    []FileResult
+	Failed     []FileResult
 }
 
 type operation int
@@ -66,8 +222,29 @@ type fileInfo struct {
 	path           string
 	size           int64
 	lastModified   time.Time
+	etag           string
+	checksums      func() (map[string]string, error)
+	head           func() (*s3.HeadObjectOutput, error)
+	destName       string
 	singleFile     bool
+	local          bool
 	existsInSource bool
+	destExists     bool
+	postponed      bool
+	symlink        string
+	provider       SourceProvider
+	// overwritten is the destination file overwritten by the sync of the source file.
+	overwritten *fileInfo
+	// prefetched is the content of the local file read ahead of the upload.
+	prefetched *prefetchedFile
+	// storageClass is the storage class of the source object.
+	storageClass string
+	// restored is set when the archived source object is restored to be downloaded.
+	restored bool
+	// key is the key of the source object whose name is decoded by the NameCodec.
+	key string
+	// originalName is the name of the file whose key is encoded by the NameCodec.
+	originalName string
 }
 
 type fileOp struct {
@@ -77,17 +254,56 @@ type fileOp struct {
 
 // New returns a new Manager.
 func New(sess *session.Session, options ...Option) *Manager {
+	return NewWithClients(nil, s3.New(sess), options...)
+}
+
+// NewWithClients returns a new Manager which accesses the source and destination
+// buckets by the different clients, e.g. for the sync between the accounts with
+// different credentials.
+// The source client lists and gets the source objects, and the destination client
+// lists, puts and deletes the destination objects.
+// The S3 to S3 copy is requested by the destination client, so the destination
+// credentials must be allowed to read the source objects.
+// If srcClient is nil, dstClient is used for both of the source and destination.
+func NewWithClients(srcClient, dstClient s3iface.S3API, options ...Option) *Manager {
 	m := &Manager{
-		s3:        s3.New(sess),
-		nJobs:     DefaultParallel,
-		guessMime: true,
+		s3:         dstClient,
+		sourceS3:   srcClient,
+		nJobs:      DefaultParallel,
+		guessMime:  true,
+		comparator: DefaultComparator,
 	}
 	for _, o := range options {
 		o(m)
 	}
+	if m.endpoint != "" {
+		m.s3 = endpointClient(m.s3, m.endpoint, m.pathStyle)
+		if m.sourceS3 != nil {
+			m.sourceS3 = endpointClient(m.sourceS3, m.endpoint, m.pathStyle)
+		}
+	}
+	if m.readOnly {
+		m.enforceReadOnly()
+	}
 	return m
 }
 
+// sourceClient returns the client of the source bucket.
+func (m *Manager) sourceClient() s3iface.S3API {
+	if m.sourceS3 != nil {
+		return m.sourceS3
+	}
+	return m.s3
+}
+
+// client returns the client accessing the path.
+func (m *Manager) client(ctx context.Context, path *s3Path) s3iface.S3API {
+	if path.source {
+		return m.regionalClient(ctx, m.sourceClient(), path.bucket)
+	}
+	return m.destClient(ctx, path.bucket)
+}
+
 // Sync syncs the files between s3 and local disks.
 func (m *Manager) Sync(ctx context.Context, source, dest string) error {
 	_, err := m.sync(ctx, source, dest, nil)
@@ -108,49 +324,69 @@ func (m *Manager) SyncWithIsChanged(ctx context.Context, source, dest string) (b
 
 // SyncWithContext syncs the files between s3 and local disks.
 // The context will be used for operation cancellation.
-func (m *Manager) sync(ctx context.Context, source, dest string, patterns []*regexp.Regexp) (bool, error) {
-	sourceURL, err := url.Parse(source)
+func (m *Manager) sync(ctx context.Context, source, dest string, patterns []*regexp.Regexp) (changed bool, err error) {
+	ctx, done := m.trackCompletion(ctx, source, dest)
+	defer done(&err)
+	ctx, cacheDone := m.useListingCache(ctx, source, dest)
+	defer cacheDone(&err)
+	ctx, warmDone := m.useWarmStart(ctx)
+	defer warmDone(&err)
+	ctx, stateDone := m.useState(ctx, source, dest)
+	defer stateDone(&err)
+
+	sourceURL, err := parseURL(source)
 	if err != nil {
 		return false, err
 	}
 
-	destURL, err := url.Parse(dest)
+	destURL, err := parseURL(dest)
 	if err != nil {
 		return false, err
 	}
+	if err := m.checkPreset(); err != nil {
+		return false, err
+	}
+	if err := m.checkDirection(isS3URL(sourceURL), isS3URL(destURL)); err != nil {
+		return false, err
+	}
+	if err := m.checkBackup(isS3URL(destURL)); err != nil {
+		return false, err
+	}
+	if err := m.checkTrash(ctx, isS3URL(destURL)); err != nil {
+		return false, err
+	}
+	if ctx, err = m.loadIgnoreFile(ctx, source, sourceURL); err != nil {
+		return false, err
+	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	ctx = m.withErrorPolicy(ctx, cancel)
 
-	chJob := make(chan func())
-	var wg sync.WaitGroup
-	for i := 0; i < m.nJobs; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for job := range chJob {
-				job()
-			}
-		}()
-	}
-	defer func() {
-		close(chJob)
-		wg.Wait()
-	}()
+	m.startProgress(ctx, sourceURL)
+	ctx, stopRuntimeStats := m.startRuntimeStats(ctx)
+	defer stopRuntimeStats()
+
+	workers, stopWorkers := m.startWorkers()
+	defer stopWorkers()
 
 	if isS3URL(sourceURL) {
 		sourceS3Path, err := urlToS3Path(sourceURL)
 		if err != nil {
 			return false, err
 		}
+		sourceS3Path.source = true
 		if isS3URL(destURL) {
 			destS3Path, err := urlToS3Path(destURL)
 			if err != nil {
 				return false, err
 			}
-			return false, m.syncS3ToS3(ctx, chJob, sourceS3Path, destS3Path, patterns)
+			if err := m.cleanupBeforeSync(ctx, destS3Path); err != nil {
+				return false, err
+			}
+			return false, m.syncS3ToS3(ctx, workers, sourceS3Path, destS3Path, patterns)
 		}
-		return m.syncS3ToLocal(ctx, chJob, sourceS3Path, dest, patterns)
+		return m.syncS3ToLocal(ctx, workers, sourceS3Path, dest, patterns)
 	}
 
 	if isS3URL(destURL) {
@@ -158,32 +394,153 @@ func (m *Manager) sync(ctx context.Context, source, dest string, patterns []*reg
 		if err != nil {
 			return false, err
 		}
-		return false, m.syncLocalToS3(ctx, chJob, source, destS3Path, patterns)
+		if err := m.cleanupBeforeSync(ctx, destS3Path); err != nil {
+			return false, err
+		}
+		return false, m.syncLocalToS3(ctx, workers, m.listLocalFiles(ctx, source, patterns), source, destS3Path, patterns)
 	}
 
 	return false, errors.New("local to local sync is not supported")
 }
 
+// startWorkers starts the workers running the sync jobs sent to the returned pool.
+// The returned function stops the workers after all jobs are processed.
+func (m *Manager) startWorkers() (*workerPool, func()) {
+	pool := &workerPool{jobs: make(chan func())}
+	var wg sync.WaitGroup
+	if m.executor != nil {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for job := range pool.jobs {
+				job := job
+				wg.Add(1)
+				m.executor.Go(func() error {
+					defer wg.Done()
+					job()
+					return nil
+				})
+			}
+		}()
+		return pool, func() {
+			pool.close()
+			<-done
+			wg.Wait()
+		}
+	}
+	worker := func(jobs chan func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job()
+			}
+		}()
+	}
+	nJobs := m.nJobs
+	if m.largeFileWorkers > 0 {
+		pool.large = make(chan func())
+		pool.threshold = m.largeFileThreshold
+		large := make(chan func())
+		go queueJobs(pool.large, large)
+		for i := 0; i < m.largeFileWorkers; i++ {
+			worker(large)
+		}
+		if nJobs -= m.largeFileWorkers; nJobs < 1 {
+			nJobs = 1
+		}
+	}
+	for i := 0; i < nJobs; i++ {
+		worker(pool.jobs)
+	}
+	return pool, func() {
+		pool.close()
+		wg.Wait()
+	}
+}
+
+// trackCompletion returns the context counting the statistics of the sync and
+// the function to be called with the error of the sync when the sync finishes,
+// which calls the completion callback and the notifiers.
+func (m *Manager) trackCompletion(ctx context.Context, source, dest string) (context.Context, func(*error)) {
+	ctx, stats := withCallStatistics(ctx)
+	startTime := time.Now()
+	m.updateStatistics(ctx, func(s *SyncStatistics) {
+		if s.StartTime.IsZero() {
+			s.StartTime = startTime
+		}
+	})
+	return ctx, func(err *error) {
+		m.updateStatistics(ctx, func(s *SyncStatistics) {
+			s.EndTime = time.Now()
+		})
+		if m.onComplete == nil && len(m.notifiers) == 0 {
+			return
+		}
+		r := SyncResult{
+			Source:     source,
+			Dest:       dest,
+			Statistics: stats.get(),
+			StartTime:  startTime,
+			EndTime:    time.Now(),
+			Err:        *err,
+		}
+		m.notify(ctx, r)
+		if m.onComplete != nil {
+			m.onComplete(r)
+		}
+	}
+}
+
 // GetStatistics returns the structure that contains the sync statistics
+// accumulated across all syncs of the Manager.
+// Use SyncWithResult or WithOnComplete for the statistics of each sync.
 func (m *Manager) GetStatistics() SyncStatistics {
-	m.statistics.mutex.Lock()
-	defer m.statistics.mutex.Unlock()
-	return SyncStatistics{Bytes: m.statistics.Bytes, Files: m.statistics.Files, DeletedFiles: m.statistics.DeletedFiles}
+	m.statisticsMu.RLock()
+	defer m.statisticsMu.RUnlock()
+	return m.statistics
+}
+
+type callStatisticsKey struct{}
+
+// callStatistics is the statistics of a sync call,
+// not affected by the other syncs running concurrently on the same Manager.
+type callStatistics struct {
+	mu sync.Mutex
+	s  SyncStatistics
+}
+
+// withCallStatistics returns the context counting the statistics of the sync call.
+// The statistics of the parent context is reused if exists.
+func withCallStatistics(ctx context.Context) (context.Context, *callStatistics) {
+	if stats, ok := ctx.Value(callStatisticsKey{}).(*callStatistics); ok {
+		return ctx, stats
+	}
+	stats := &callStatistics{}
+	return context.WithValue(ctx, callStatisticsKey{}, stats), stats
+}
+
+func (s *callStatistics) get() SyncStatistics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.s
 }
 
 func isS3URL(url *url.URL) bool {
 	return url.Scheme == "s3"
 }
 
-func (m *Manager) syncS3ToS3(ctx context.Context, chJob chan func(), sourcePath *s3Path, destPath *s3Path, patterns []*regexp.Regexp) error {
+func (m *Manager) syncS3ToS3(ctx context.Context, workers *workerPool, sourcePath *s3Path, destPath *s3Path, patterns []*regexp.Regexp) error {
 	wg := &sync.WaitGroup{}
-	errs := &multiErr{}
-	for source := range filterFilesForSync(
-		m.listS3Files(ctx, sourcePath, patterns), m.listS3Files(ctx, destPath, patterns), m.del,
+	errs := newSyncErrors(ctx)
+	for source := range m.filterFiles(ctx,
+		m.mapDestKeys(ctx, m.progress.trackListing(ctx, m.markNotReady(ctx, m.applyFilters(ctx, m.listS3Files(ctx, sourcePath, patterns))))),
+		m.applyDestFilters(ctx, m.listDestS3Files(ctx, destPath, patterns)),
 	) {
+		m.queued(ctx, source)
 		wg.Add(1)
 		source := source
-		chJob <- func() {
+		workers.run(source.size, func() {
 			defer wg.Done()
 			if source.err != nil {
 				errs.Append(source.err)
@@ -191,11 +548,14 @@ func (m *Manager) syncS3ToS3(ctx context.Context, chJob chan func(), sourcePath
 			}
 			switch source.op {
 			case opUpdate:
-				if err := m.copyS3ToS3(ctx, source.fileInfo, sourcePath, destPath); err != nil {
+				defer m.progress.processed(source.size)
+				if err := m.retry(ctx, func(ctx context.Context) error {
+					return m.copyS3ToS3(ctx, source.fileInfo, sourcePath, destPath)
+				}); err != nil {
 					errs.Append(err)
 				}
 			}
-		}
+		})
 	}
 	wg.Wait()
 
@@ -203,16 +563,35 @@ func (m *Manager) syncS3ToS3(ctx context.Context, chJob chan func(), sourcePath
 
 }
 
-func (m *Manager) syncLocalToS3(ctx context.Context, chJob chan func(), sourcePath string, destPath *s3Path, patterns []*regexp.Regexp) error {
+func (m *Manager) syncLocalToS3(ctx context.Context, workers *workerPool, sourceFiles chan *fileInfo, sourcePath string, destPath *s3Path, patterns []*regexp.Regexp) error {
+	ctx, folders := withFolders(ctx)
 	wg := &sync.WaitGroup{}
-	errs := &multiErr{}
+	errs := newSyncErrors(ctx)
 
-	for source := range filterFilesForSync(
-		listLocalFiles(ctx, sourcePath, patterns), m.listS3Files(ctx, destPath, patterns), m.del,
-	) {
+	// Deletions are batched by DeleteObjects.
+	var deletes []*fileInfo
+	deleteBatch := func() {
+		files := deletes
+		deletes = nil
 		wg.Add(1)
-		source := source
-		chJob <- func() {
+		workers.run(0, func() {
+			defer wg.Done()
+			for _, err := range m.deleteRemoteBatch(ctx, files, destPath) {
+				errs.Append(err)
+			}
+		})
+	}
+
+	dispatch := func(source *fileOp) {
+		m.queued(ctx, source)
+		if source.err == nil && source.op == opDelete {
+			if deletes = append(deletes, source.fileInfo); len(deletes) >= maxDeleteObjects {
+				deleteBatch()
+			}
+			return
+		}
+		wg.Add(1)
+		workers.run(source.size, func() {
 			defer wg.Done()
 			if source.err != nil {
 				errs.Append(source.err)
@@ -220,35 +599,78 @@ func (m *Manager) syncLocalToS3(ctx context.Context, chJob chan func(), sourcePa
 			}
 			switch source.op {
 			case opUpdate:
-				if err := m.upload(source.fileInfo, sourcePath, destPath); err != nil {
-					errs.Append(err)
-				}
-			case opDelete:
-				if err := m.deleteRemote(source.fileInfo, destPath); err != nil {
+				defer m.progress.processed(source.size)
+				if err := m.retry(ctx, func(ctx context.Context) error {
+					return m.upload(ctx, source.fileInfo, sourcePath, destPath)
+				}); err != nil {
 					errs.Append(err)
 				}
 			}
+		})
+	}
+	wait := func() {
+		if len(deletes) > 0 {
+			deleteBatch()
+		}
+		wg.Wait()
+	}
+
+	var deferred []*fileOp
+	for source := range m.readAhead(ctx, m.filterFiles(ctx,
+		m.encodeDestNames(ctx, m.mapDestKeys(ctx, m.progress.trackListing(ctx, m.markNotReady(ctx, m.applyFilters(ctx, sourceFiles))))),
+		m.applyDestFilters(ctx, m.decodeDestNames(ctx, m.listDestS3Files(ctx, destPath, patterns), destPath)),
+	), sourcePath) {
+		if m.deferOp(source) {
+			deferred = append(deferred, source)
+			continue
+		}
+		dispatch(source)
+	}
+	if len(deferred) > 0 {
+		wait()
+		phases, err := m.deferredOps(deferred, errs.Len() > 0, destPath.String())
+		if err != nil {
+			errs.Append(err)
+		}
+		for _, phase := range phases {
+			for _, source := range phase {
+				dispatch(source)
+			}
+			wait()
+		}
+	}
+	wait()
+
+	if m.preserveEmptyDirs && errs.Len() == 0 {
+		if err := m.syncRemoteFolders(ctx, folders, sourcePath, destPath); err != nil {
+			errs.Append(err)
+		}
+	}
+	if errs.Len() == 0 && m.manifestName != "" {
+		if err := m.writeManifest(ctx, destPath); err != nil {
+			errs.Append(err)
 		}
 	}
-	wg.Wait()
 
 	return errs.ErrOrNil()
 }
 
 // syncS3ToLocal syncs the given s3 path to the given local path.
 func (m *Manager) syncS3ToLocal(
-	ctx context.Context, chJob chan func(), sourcePath *s3Path, destPath string, patterns []*regexp.Regexp,
+	ctx context.Context, workers *workerPool, sourcePath *s3Path, destPath string, patterns []*regexp.Regexp,
 ) (bool, error) {
+	ctx, folders := withFolders(ctx)
 	wg := &sync.WaitGroup{}
-	errs := &multiErr{}
+	errs := newSyncErrors(ctx)
 
-	changed := false
-	for source := range filterFilesForSync(
-		m.listS3Files(ctx, sourcePath, patterns), listLocalFiles(ctx, destPath, patterns), m.del,
-	) {
+	var changed int32
+	restoring := &restoringFiles{}
+	dispatch := func(source *fileOp) {
+		if !source.restored {
+			m.queued(ctx, source)
+		}
 		wg.Add(1)
-		source := source
-		chJob <- func() {
+		workers.run(source.size, func() {
 			defer wg.Done()
 			if source.err != nil {
 				errs.Append(source.err)
@@ -256,64 +678,197 @@ func (m *Manager) syncS3ToLocal(
 			}
 			switch source.op {
 			case opUpdate:
-				changed = true
-				if err := m.download(source.fileInfo, sourcePath, destPath); err != nil {
+				if !m.isDryRun(ctx) && !source.restored {
+					state, err := m.checkArchived(ctx, source.fileInfo, sourcePath)
+					switch {
+					case err != nil:
+						m.progress.processed(source.size)
+						errs.Append(err)
+						return
+					case state == archiveSkipped:
+						m.skipped(ctx)(source.fileInfo)
+						return
+					case state == archiveRestoring:
+						restoring.add(source)
+						return
+					}
+				}
+				defer m.progress.processed(source.size)
+				atomic.StoreInt32(&changed, 1)
+				if err := m.retry(ctx, func(ctx context.Context) error {
+					return m.download(ctx, source.fileInfo, sourcePath, destPath)
+				}); err != nil {
 					errs.Append(err)
 				}
 			case opDelete:
-				if err := m.deleteLocal(source.fileInfo, destPath); err != nil {
+				if err := m.deleteLocal(ctx, source.fileInfo, destPath); err != nil {
 					errs.Append(err)
 				}
 			}
+		})
+	}
+
+	var deferred []*fileOp
+	var destFiles chan *fileInfo
+	if !m.initialSeed {
+		destFiles = m.applyDestFilters(ctx, m.listDestLocalFiles(ctx, destPath, patterns))
+	}
+	for source := range m.filterFiles(ctx,
+		m.progress.trackListing(ctx, m.markNotReady(ctx, m.applyFilters(ctx, m.decodeSourceNames(ctx, m.listS3Files(ctx, sourcePath, patterns), sourcePath)))),
+		destFiles,
+	) {
+		if m.deferOp(source) {
+			deferred = append(deferred, source)
+			continue
+		}
+		dispatch(source)
+	}
+	if len(deferred) > 0 {
+		wg.Wait()
+		phases, err := m.deferredOps(deferred, errs.Len() > 0, destPath)
+		if err != nil {
+			errs.Append(err)
+		}
+		for _, phase := range phases {
+			for _, source := range phase {
+				dispatch(source)
+			}
+			wg.Wait()
 		}
 	}
 	wg.Wait()
+	m.waitRestored(ctx, restoring, sourcePath, dispatch, errs)
+	wg.Wait()
 
-	return changed, errs.ErrOrNil()
+	if err := m.createLocalFolders(ctx, folders, destPath); err != nil {
+		errs.Append(err)
+	}
+
+	return atomic.LoadInt32(&changed) == 1, errs.ErrOrNil()
 }
 
-func (m *Manager) copyS3ToS3(ctx context.Context, file *fileInfo, sourcePath *s3Path, destPath *s3Path) error {
-	copySource := filepath.ToSlash(filepath.Join(sourcePath.bucket, sourcePath.bucketPrefix, file.name))
-	destinationKey := filepath.ToSlash(filepath.Join(destPath.bucketPrefix, file.name))
-	println("Copying from", copySource, "to key", destinationKey, "in bucket", destPath.bucket)
-	if m.dryrun {
+func (m *Manager) copyS3ToS3(ctx context.Context, file *fileInfo, sourcePath *s3Path, destPath *s3Path) (err error) {
+	sourceKey := objectKey(sourcePath.bucketPrefix, file.name)
+	copySource := sourcePath.bucket + "/" + sourceKey
+	destinationKey := objectKey(destPath.bucketPrefix, file.destKeyName())
+	defer wrapFileError(&err, "copy", "", destinationKey)
+	if err := m.refuseIfReadOnly("copying", copySource); err != nil {
+		return err
+	}
+	attrs := opAttrs(ctx, "copy", destPath.bucket, destinationKey, file.size)
+	logOp(ctx, attrs, "Copying from", copySource, "to key", destinationKey, "in bucket", destPath.bucket)
+	if m.isDryRun(ctx) {
+		m.planned(ctx, FileCopied, file)
 		return nil
 	}
-
-	_, err := m.s3.CopyObject(&s3.CopyObjectInput{
-		Bucket:     aws.String(destPath.bucket),
-		CopySource: aws.String(copySource),
-		Key:        aws.String(destinationKey),
-		ACL:        m.acl,
-	})
+	defer logOpDone(ctx, attrs, time.Now(), &err)
+	defer m.emitDone(ctx, FileCopied, file, time.Now(), &err)
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
+	if file.overwritten != nil {
+		if err := m.backupRemote(ctx, destPath.bucket, destinationKey, file.overwritten.size); err != nil {
+			return err
+		}
+	}
+	fp := m.startFileProgress("copy", file)
+	defer fp.finish(&err)
+
+	input := &s3.CopyObjectInput{
+		Bucket:                         aws.String(destPath.bucket),
+		CopySource:                     aws.String(m.copySource(sourcePath.bucket, sourceKey)),
+		Key:                            aws.String(destinationKey),
+		ACL:                            m.acl,
+		ServerSideEncryption:           m.sse,
+		SSEKMSKeyId:                    m.sseKMSKeyID,
+		SSECustomerAlgorithm:           m.sseCustomerAlgorithm,
+		SSECustomerKey:                 m.sseCustomerKey,
+		CopySourceSSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		CopySourceSSECustomerKey:       m.sseCustomerKey,
+		StorageClass:                   m.storageClass,
+		ChecksumAlgorithm:              m.copyChecksumAlgorithm(ctx),
+	}
+	if m.hasMetadataOptions() || needsMultipartCopy(file.size) || m.versionTracking {
+		if err := m.replaceCopyMetadata(ctx, input, sourcePath.bucket, sourceKey); err != nil {
+			return err
+		}
+	}
+	if needsMultipartCopy(file.size) {
+		if err := m.copySourceTags(ctx, input, sourcePath.bucket, sourceKey); err != nil {
+			return err
+		}
+		err = m.multipartCopy(ctx, input, file.size)
+	} else {
+		_, err = m.destClient(ctx, destPath.bucket).CopyObjectWithContext(ctx, input, m.putRateOption(destPath.bucket, destinationKey))
+	}
 
 	if err != nil {
 		return err
 	}
+	if m.copyACL {
+		if err := m.copyObjectACL(ctx, sourcePath.bucket, sourceKey, destPath.bucket, destinationKey); err != nil {
+			return err
+		}
+	}
+	fp.add(0, file.size)
 
-	m.updateFileTransferStatistics(file.size)
+	m.updateFileTransferStatistics(ctx, file.size)
 	return nil
 }
 
-func (m *Manager) download(file *fileInfo, sourcePath *s3Path, destPath string) error {
+func (m *Manager) download(ctx context.Context, file *fileInfo, sourcePath *s3Path, destPath string) (err error) {
 	var targetFilename string
 	if !strings.HasSuffix(destPath, "/") && file.singleFile {
 		// Destination path is not a directory and source is a single file.
 		targetFilename = destPath
 	} else {
-		targetFilename = filepath.Join(destPath, file.name)
+		var ok bool
+		if targetFilename, ok = m.localFilename(destPath, file.name); !ok {
+			println("Skipping", file.name, "which can't be mapped to a local path")
+			m.emit(ctx, SyncEvent{Type: FileSkipped, Path: file.name, Size: file.size})
+			return nil
+		}
 	}
 	targetDir := filepath.Dir(targetFilename)
 
-	println("Downloading", file.name, "to", targetFilename)
-	if m.dryrun {
+	sourceFile := sourceObjectKey(file, sourcePath)
+	defer wrapFileError(&err, "download", targetFilename, sourceFile)
+
+	attrs := append(opAttrs(ctx, "download", sourcePath.bucket, sourceFile, file.size), "path", targetFilename)
+	logOp(ctx, attrs, "Downloading", file.name, "to", targetFilename)
+	if m.isDryRun(ctx) {
+		m.planned(ctx, FileDownloaded, file)
 		return nil
 	}
+	defer logOpDone(ctx, attrs, time.Now(), &err)
+	defer m.emitDone(ctx, FileDownloaded, file, time.Now(), &err)
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
 
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return err
 	}
+	if m.symlinkPolicy == SymlinkPreserveAsMetadata {
+		if file.size == 0 {
+			if linked, err := m.restoreSymlink(ctx, sourcePath.bucket, sourceFile, targetFilename); err != nil {
+				return err
+			} else if linked {
+				m.updateFileTransferStatistics(ctx, 0)
+				if m.ownership {
+					return m.restoreOwner(ctx, sourcePath.bucket, sourceFile, targetFilename)
+				}
+				return nil
+			}
+		}
+		if err := removeSymlink(targetFilename); err != nil {
+			return err
+		}
+	}
 
+	if file.overwritten != nil && m.backup != "" {
+		if err := m.backupLocal(ctx, destPath, targetFilename); err != nil {
+			return err
+		}
+	}
 	writer, err := os.Create(targetFilename)
 	if err != nil {
 		return err
@@ -321,139 +876,346 @@ func (m *Manager) download(file *fileInfo, sourcePath *s3Path, destPath string)
 
 	defer writer.Close()
 
-	var sourceFile string
-	if file.singleFile {
-		sourceFile = file.name
-	} else {
-		// Using filepath.ToSlash for change backslash to slash on Windows
-		sourceFile = filepath.ToSlash(filepath.Join(sourcePath.bucketPrefix, file.name))
-	}
+	fp := m.startFileProgress("download", file)
+	defer fp.finish(&err)
 
-	c := s3manager.NewDownloaderWithClient(m.s3, m.downloaderOpts...)
-	written, err := c.Download(writer, &s3.GetObjectInput{
-		Bucket: aws.String(sourcePath.bucket),
-		Key:    aws.String(sourceFile),
-	})
+	w := m.limitWriterAt(ctx, fp.wrapWriterAt(m.limitLocalWriterAt(ctx, writer)))
+	input := &s3.GetObjectInput{
+		Bucket:               aws.String(sourcePath.bucket),
+		Key:                  aws.String(sourceFile),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	}
+	var written int64
+	switch {
+	case isObjectLambda(sourcePath.bucket):
+		written, err = m.getWholeObject(ctx, w, input)
+	case m.encryption != nil:
+		written, err = m.getDecryptedObject(ctx, w, input)
+	case m.compression != nil && m.compression.matches(file.name):
+		written, err = m.getDecodedObject(ctx, w, input)
+	default:
+		written, err = m.getDownloader().DownloadWithContext(ctx, w, input,
+			withDownloaderClient(m.transferClient(ctx, m.regionalClient(ctx, m.sourceClient(), sourcePath.bucket), sourcePath.bucket)))
+	}
 	if err != nil {
 		return err
 	}
-	m.updateFileTransferStatistics(written)
+	if mode := m.downloadVerifyMode(); mode != 0 && !isObjectLambda(sourcePath.bucket) {
+		// The timestamp is not restored for the mismatched file not to be skipped by the next sync.
+		if err := m.verifyDownload(ctx, file, sourcePath.bucket, sourceFile, targetFilename, mode); err != nil {
+			return err
+		}
+	}
+	m.updateFileTransferStatistics(ctx, written)
 	err = os.Chtimes(targetFilename, file.lastModified, file.lastModified)
 	if err != nil {
 		return err
 	}
+	if m.ownership {
+		if err := m.restoreOwner(ctx, sourcePath.bucket, sourceFile, targetFilename); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (m *Manager) deleteLocal(file *fileInfo, destPath string) error {
+func (m *Manager) deleteLocal(ctx context.Context, file *fileInfo, destPath string) (err error) {
 	var targetFilename string
 	if !strings.HasSuffix(destPath, "/") && file.singleFile {
 		// Destination path is not a directory and source is a single file.
 		targetFilename = destPath
 	} else {
-		targetFilename = filepath.Join(destPath, file.name)
+		var ok bool
+		if targetFilename, ok = m.localFilename(destPath, file.name); !ok {
+			return nil
+		}
 	}
+	defer wrapFileError(&err, "delete", targetFilename, "")
 
-	println("Deleting", targetFilename)
-	if m.dryrun {
+	if err := m.refuseIfReadOnly("deleting", targetFilename); err != nil {
+		return err
+	}
+	attrs := []interface{}{"op", "delete", "path", targetFilename, "attempt", attemptFromContext(ctx)}
+	logOp(ctx, attrs, "Deleting", targetFilename)
+	if m.isDryRun(ctx) {
+		m.planned(ctx, FileDeleted, file)
 		return nil
 	}
-	err := os.Remove(targetFilename)
+	defer logOpDone(ctx, attrs, time.Now(), &err)
+	defer m.emitDone(ctx, FileDeleted, file, time.Now(), &err)
+
+	if m.backup != "" {
+		err = m.backupLocal(ctx, destPath, targetFilename)
+	} else if m.trash != "" {
+		err = m.trashLocal(ctx, destPath, targetFilename)
+	} else {
+		err = os.Remove(targetFilename)
+	}
 	if err != nil {
 		return err
 	}
-	m.incrementDeletedFiles()
+	m.incrementDeletedFiles(ctx)
 	return nil
 }
 
-func (m *Manager) upload(file *fileInfo, sourcePath string, destPath *s3Path) error {
-	var sourceFilename string
-	if file.singleFile {
-		sourceFilename = sourcePath
-	} else {
-		sourceFilename = filepath.Join(sourcePath, file.name)
-	}
+func (m *Manager) upload(ctx context.Context, file *fileInfo, sourcePath string, destPath *s3Path) (err error) {
+	sourceFilename := m.sourceFilename(file, sourcePath)
 
 	destFile := *destPath
 	if strings.HasSuffix(destPath.bucketPrefix, "/") || destPath.bucketPrefix == "" || !file.singleFile {
 		// If source is a single file and destination is not a directory, use destination URL as is.
 		// Using filepath.ToSlash for change backslash to slash on Windows
-		destFile.bucketPrefix = filepath.ToSlash(filepath.Join(destPath.bucketPrefix, file.name))
+		destFile.bucketPrefix = objectKey(destPath.bucketPrefix, file.destKeyName())
 	}
+	defer wrapFileError(&err, "upload", sourceFilename, destFile.bucketPrefix)
 
-	println("Uploading", file.name, "to", destFile.String())
-	if m.dryrun {
+	if err := m.refuseIfReadOnly("uploading", file.name); err != nil {
+		return err
+	}
+	if stable, err := m.waitStable(ctx, file, sourceFilename); err != nil {
+		return err
+	} else if !stable {
+		println("Skipping unstable file", sourceFilename)
+		m.emit(ctx, SyncEvent{Type: FileSkipped, Path: file.name, Size: file.size})
 		return nil
 	}
+	attrs := append(opAttrs(ctx, "upload", destFile.bucket, destFile.bucketPrefix, file.size), "path", sourceFilename)
+	logOp(ctx, attrs, "Uploading", file.name, "to", destFile.String())
+	if m.isDryRun(ctx) {
+		m.planned(ctx, FileUploaded, file)
+		return nil
+	}
+	defer logOpDone(ctx, attrs, time.Now(), &err)
+	defer m.emitDone(ctx, FileUploaded, file, time.Now(), &err)
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
 
-	var contentType *string
-	switch {
-	case m.contentType != nil:
-		contentType = m.contentType
-	case m.guessMime:
-		mime, err := mimetype.DetectFile(sourceFilename)
-		if err != nil {
+	if file.overwritten != nil {
+		if err := m.backupRemote(ctx, destFile.bucket, destFile.bucketPrefix, file.overwritten.size); err != nil {
 			return err
 		}
-		s := mime.String()
-		contentType = &s
 	}
 
-	reader, err := os.Open(sourceFilename)
+	var reader io.ReadCloser
+	switch {
+	case file.prefetched != nil:
+		reader = bytesReadCloser{bytes.NewReader(file.prefetched.data)}
+	case file.provider != nil:
+		reader, err = file.provider.Open()
+	case file.symlink != "":
+		reader = ioutil.NopCloser(strings.NewReader(""))
+	default:
+		reader, err = os.Open(sourceFilename)
+	}
 	if err != nil {
 		return err
 	}
 
 	defer reader.Close()
 
-	_, err = s3manager.NewUploaderWithClient(
-		m.s3,
-		m.uploaderOpts...,
-	).Upload(&s3manager.UploadInput{
-		Bucket:      aws.String(destFile.bucket),
-		Key:         aws.String(destFile.bucketPrefix),
-		ACL:         m.acl,
-		Body:        reader,
-		ContentType: contentType,
-	})
+	var body io.Reader = reader
+	contentType := m.contentType
+	if contentType == nil {
+		contentType = m.mappedContentType(file.name)
+	}
+	switch {
+	case !m.sniffContentType(file):
+	case file.prefetched != nil:
+		contentType = aws.String(file.prefetched.contentType)
+	default:
+		var s string
+		s, body, err = detectContentType(reader)
+		if err != nil {
+			return err
+		}
+		contentType = &s
+	}
+
+	metadata := m.userMetadata()
+	if m.ownership && file.provider == nil {
+		for k, v := range m.ownerMetadata(sourceFilename) {
+			if metadata == nil {
+				metadata = make(map[string]*string)
+			}
+			metadata[k] = v
+		}
+	}
+
+	if file.symlink != "" {
+		if metadata == nil {
+			metadata = make(map[string]*string)
+		}
+		metadata[metadataSymlink] = aws.String(file.symlink)
+	}
+	if name := m.nameMetadata(file); name != nil {
+		if metadata == nil {
+			metadata = make(map[string]*string)
+		}
+		metadata[metadataOriginalName] = name
+	}
+
+	if file.provider == nil {
+		body = m.limitLocalReader(ctx, body)
+	}
+	fp := m.startFileProgress("upload", file)
+	defer fp.finish(&err)
+	body = fp.wrapReader(body)
+	contentEncoding := m.contentEncoding
+	if file.symlink == "" && m.compressible(file.name, file.size) {
+		compressed := compressReader(m.compression.codec, body)
+		defer compressed.Close()
+		body = compressed
+		contentEncoding = aws.String(m.compression.codec.Encoding())
+	}
+	if file.symlink == "" && m.encryption != nil {
+		var envelope map[string]*string
+		body, envelope, err = m.encryption.encrypt(ctx, body, file.size)
+		if err != nil {
+			return err
+		}
+		if metadata == nil {
+			metadata = make(map[string]*string, len(envelope))
+		}
+		for k, v := range envelope {
+			metadata[k] = v
+		}
+	}
+	body = m.limitReader(ctx, body)
+
+	in := &s3manager.UploadInput{
+		Bucket:               aws.String(destFile.bucket),
+		Key:                  aws.String(destFile.bucketPrefix),
+		ACL:                  m.acl,
+		Body:                 body,
+		ContentType:          contentType,
+		ServerSideEncryption: m.sse,
+		SSEKMSKeyId:          m.sseKMSKeyID,
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+		CacheControl:         m.cacheControl,
+		ContentEncoding:      contentEncoding,
+		StorageClass:         m.storageClass,
+		Tagging:              m.tagging,
+		Metadata:             metadata,
+		ChecksumAlgorithm:    m.checksumAlgorithm(),
+	}
+	if m.uploadInputModifier != nil {
+		m.uploadInputModifier(*file.export(), in)
+	}
+	opts := []request.Option{m.putRateOption(destFile.bucket, destFile.bucketPrefix)}
+	if aws.StringValue(in.ChecksumAlgorithm) == s3.ChecksumAlgorithmSha256 {
+		opts = append(opts, sha256Checksums())
+	}
+	_, err = m.getUploader().UploadWithContext(ctx, in,
+		withUploaderRequestOptions(opts...),
+		withUploaderClient(m.transferClient(ctx, m.destClient(ctx, destFile.bucket), destFile.bucket)))
 	if err != nil {
 		return err
 	}
-	m.updateFileTransferStatistics(file.size)
+	if m.verifyMode != 0 && file.symlink == "" {
+		if err := m.verifyUpload(ctx, file, &destFile); err != nil {
+			return err
+		}
+	}
+	m.updateFileTransferStatistics(ctx, file.size)
 	return nil
 }
 
-func (m *Manager) deleteRemote(file *fileInfo, destPath *s3Path) error {
-	destFile := *destPath
-	if strings.HasSuffix(destPath.bucketPrefix, "/") || destPath.bucketPrefix == "" || !file.singleFile {
-		// If source is a single file and destination is not a directory, use destination URL as is.
-		// Using filepath.ToSlash for change backslash to slash on Windows
-		destFile.bucketPrefix = filepath.ToSlash(filepath.Join(destPath.bucketPrefix, file.name))
+// detectContentType detects MIME type from the head of the contents,
+// and returns the reader to read the whole contents.
+func detectContentType(r io.Reader) (string, io.Reader, error) {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		mime, err := mimetype.DetectReader(rs)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := rs.Seek(0, io.SeekStart); err != nil {
+			return "", nil, err
+		}
+		return mime.String(), rs, nil
+	}
+	head := &bytes.Buffer{}
+	mime, err := mimetype.DetectReader(io.TeeReader(r, head))
+	if err != nil {
+		return "", nil, err
 	}
+	return mime.String(), io.MultiReader(head, r), nil
+}
 
-	println("Deleting", destFile.String())
-	if m.dryrun {
+func (m *Manager) deleteRemote(ctx context.Context, file *fileInfo, destPath *s3Path) (err error) {
+	destFile := remoteFilePath(file, destPath)
+	defer wrapFileError(&err, "delete", "", destFile.bucketPrefix)
+	if err := m.refuseIfReadOnly("deleting", destFile.String()); err != nil {
+		return err
+	}
+	attrs := opAttrs(ctx, "delete", destFile.bucket, destFile.bucketPrefix, file.size)
+	logOp(ctx, attrs, "Deleting", destFile.String())
+	if m.isDryRun(ctx) {
+		m.planned(ctx, FileDeleted, file)
 		return nil
 	}
+	defer logOpDone(ctx, attrs, time.Now(), &err)
+	defer m.emitDone(ctx, FileDeleted, file, time.Now(), &err)
+	ctx, cancel := withTimeout(ctx, m.opTimeout)
+	defer cancel()
 
-	_, err := m.s3.DeleteObject(&s3.DeleteObjectInput{
-		Bucket: aws.String(destFile.bucket),
-		Key:    aws.String(destFile.bucketPrefix),
-	})
+	if err := m.backupRemote(ctx, destFile.bucket, destFile.bucketPrefix, file.size); err != nil {
+		return err
+	}
+	_, err = m.destClient(ctx, destFile.bucket).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket:                    aws.String(destFile.bucket),
+		Key:                       aws.String(destFile.bucketPrefix),
+		BypassGovernanceRetention: m.bypassGovernance,
+	}, m.putRateOption(destFile.bucket, destFile.bucketPrefix))
 	if err != nil {
 		return err
 	}
-	m.incrementDeletedFiles()
+	m.incrementDeletedFiles(ctx)
 	return nil
 }
 
+// withTimeout returns the context with the given timeout,
+// or the context as is if the timeout is not set.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
 // listS3Files return a channel which receives the file infos under the given s3Path.
 func (m *Manager) listS3Files(ctx context.Context, path *s3Path, patterns []*regexp.Regexp) chan *fileInfo {
 	c := make(chan *fileInfo, 50000) // TODO: revisit this buffer size later
+	if path.source {
+		trackBacklog(ctx, "source listing", func() (int, int) { return len(c), cap(c) })
+	} else {
+		trackBacklog(ctx, "destination listing", func() (int, int) { return len(c), cap(c) })
+	}
+
+	if objects, ok := m.cachedListing(ctx, path, patterns); ok {
+		go func() {
+			defer close(c)
+			m.sendS3Objects(ctx, c, path, objects, patterns)
+		}()
+		return c
+	}
 
 	go func() {
 		defer close(c)
+		if objects, ok, err := m.warmListing(ctx, path, patterns); err != nil {
+			sendErrorInfoToChannel(ctx, c, err)
+			return
+		} else if ok {
+			m.recordWarmStart(ctx, path, objects, true, true)
+			m.sendS3Objects(ctx, c, path, objects, patterns)
+			return
+		}
+		if m.maxDepth > 0 {
+			m.listS3FilesByDepth(ctx, c, path, patterns)
+			return
+		}
 		var token *string
 		for {
 			if token = m.listS3FileWithToken(ctx, c, path, token, patterns); token == nil {
@@ -467,27 +1229,49 @@ func (m *Manager) listS3Files(ctx context.Context, path *s3Path, patterns []*reg
 
 // listS3FileWithToken lists (send to the result channel) the s3 files from the given continuation token.
 func (m *Manager) listS3FileWithToken(ctx context.Context, c chan *fileInfo, path *s3Path, token *string, patterns []*regexp.Regexp) *string {
-	list, err := m.s3.ListObjectsV2(&s3.ListObjectsV2Input{
+	reqCtx, cancel := withTimeout(ctx, m.listTimeout)
+	list, err := m.client(reqCtx, path).ListObjectsV2WithContext(reqCtx, &s3.ListObjectsV2Input{
 		Bucket:            &path.bucket,
 		Prefix:            &path.bucketPrefix,
 		ContinuationToken: token,
+		StartAfter:        m.startAfter(path),
 	})
+	cancel()
 	if err != nil {
 		sendErrorInfoToChannel(ctx, c, err)
 		return nil
 	}
+	m.updateStatistics(ctx, func(s *SyncStatistics) {
+		s.ListedObjects += int64(len(list.Contents))
+	})
+	m.recordListing(ctx, path, patterns, token == nil, list.Contents)
+	m.recordWarmStart(ctx, path, list.Contents, token == nil, list.NextContinuationToken == nil)
 
-	for _, object := range list.Contents {
-		if strings.HasSuffix(*object.Key, "/") {
-			// Skip directory like object
-			continue
-		}
-		name, err := filepath.Rel(path.bucketPrefix, *object.Key)
+	if !m.sendS3Objects(ctx, c, path, list.Contents, patterns) {
+		return nil
+	}
+	return list.NextContinuationToken
+}
+
+// sendS3Objects sends the infos of the listed objects to the result channel.
+// It returns false if the listing should be stopped.
+func (m *Manager) sendS3Objects(ctx context.Context, c chan *fileInfo, path *s3Path, objects []*s3.Object, patterns []*regexp.Regexp) bool {
+	for _, object := range objects {
+		name, err := objectName(path.bucketPrefix, *object.Key)
 		if err != nil {
 			sendErrorInfoToChannel(ctx, c, err)
 			continue
 		}
-		if !matchName(name, patterns) {
+		if m.afterKeyRange(filepath.ToSlash(name)) {
+			// Objects are listed in the order of the key.
+			return false
+		}
+		if !matchName(name, patterns) || (path.source || !m.deleteExcluded) && m.ignored(ctx, name, strings.HasSuffix(*object.Key, "/")) {
+			continue
+		}
+		if strings.HasSuffix(*object.Key, "/") {
+			// Directory like object is synced as the folder
+			addFolder(ctx, path, name)
 			continue
 		}
 		var fi *fileInfo
@@ -498,6 +1282,9 @@ func (m *Manager) listS3FileWithToken(ctx context.Context, c chan *fileInfo, pat
 				path:         filepath.Dir(*object.Key),
 				size:         *object.Size,
 				lastModified: *object.LastModified,
+				etag:         aws.StringValue(object.ETag),
+				storageClass: aws.StringValue(object.StorageClass),
+				checksums:    m.objectChecksums(ctx, path, *object.Key),
 				singleFile:   true,
 			}
 		} else {
@@ -506,36 +1293,68 @@ func (m *Manager) listS3FileWithToken(ctx context.Context, c chan *fileInfo, pat
 				path:         *object.Key,
 				size:         *object.Size,
 				lastModified: *object.LastModified,
+				etag:         aws.StringValue(object.ETag),
+				storageClass: aws.StringValue(object.StorageClass),
+				checksums:    m.objectChecksums(ctx, path, *object.Key),
 			}
 		}
+		if m.versionTracking {
+			fi.head = m.objectHead(ctx, path, *object.Key)
+		}
 		select {
 		case c <- fi:
 		case <-ctx.Done():
-			return nil
+			return false
 		}
 	}
+	return true
+}
 
-	return list.NextContinuationToken
+// skipped returns the function called for each source file skipped as up-to-date.
+func (m *Manager) skipped(ctx context.Context) func(*fileInfo) {
+	return func(file *fileInfo) {
+		m.progress.processed(file.size)
+		m.emit(ctx, SyncEvent{Type: FileSkipped, Path: file.name, Size: file.size})
+		m.recordPlan(ctx, FileSkipped, file)
+	}
 }
 
 // updateSyncStatistics updates the statistics of the amount of bytes transferred for one file
-func (m *Manager) updateFileTransferStatistics(written int64) {
-	m.statistics.mutex.Lock()
-	defer m.statistics.mutex.Unlock()
-	m.statistics.Files++
-	m.statistics.Bytes += written
+func (m *Manager) updateFileTransferStatistics(ctx context.Context, written int64) {
+	m.updateStatistics(ctx, func(s *SyncStatistics) {
+		s.Files++
+		s.Bytes += written
+	})
 }
 
 // incrementDeletedFiles increments the counter used to capture the number of remote files deleted during the synchronization process
-func (m *Manager) incrementDeletedFiles() {
-	m.statistics.mutex.Lock()
-	defer m.statistics.mutex.Unlock()
-	m.statistics.DeletedFiles++
+func (m *Manager) incrementDeletedFiles(ctx context.Context) {
+	m.updateStatistics(ctx, func(s *SyncStatistics) {
+		s.DeletedFiles++
+	})
+}
+
+// updateStatistics updates the cumulative statistics and the statistics of the sync call.
+func (m *Manager) updateStatistics(ctx context.Context, update func(*SyncStatistics)) {
+	m.statisticsMu.Lock()
+	update(&m.statistics)
+	m.statisticsMu.Unlock()
+	if stats, ok := ctx.Value(callStatisticsKey{}).(*callStatistics); ok {
+		stats.mu.Lock()
+		update(&stats.s)
+		stats.mu.Unlock()
+	}
 }
 
 // listLocalFiles returns a channel which receives the infos of the files under the given basePath.
 // basePath have to be absolute path.
 func listLocalFiles(ctx context.Context, basePath string, patterns []*regexp.Regexp) chan *fileInfo {
+	return walkLocalFiles(ctx, basePath, patterns, filepath.Walk)
+}
+
+// walkLocalFiles returns a channel which receives the infos of the files under the given basePath
+// visited by the given walk function.
+func walkLocalFiles(ctx context.Context, basePath string, patterns []*regexp.Regexp, walk func(string, filepath.WalkFunc) error) chan *fileInfo {
 	c := make(chan *fileInfo)
 
 	basePath = filepath.ToSlash(basePath)
@@ -560,7 +1379,7 @@ func listLocalFiles(ctx context.Context, basePath string, patterns []*regexp.Reg
 
 		sendFileInfoToChannel(ctx, c, basePath, basePath, stat, false)
 
-		err = filepath.Walk(basePath, func(path string, stat os.FileInfo, err error) error {
+		err = walk(basePath, func(path string, stat os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -590,6 +1409,17 @@ func sendFileInfoToChannel(ctx context.Context, c chan *fileInfo, basePath, path
 		size:         stat.Size(),
 		lastModified: stat.ModTime(),
 		singleFile:   singleFile,
+		local:        true,
+	}
+	if stat.Mode()&os.ModeSymlink != 0 {
+		// Symbolic link preserved as the empty object.
+		target, err := os.Readlink(path)
+		if err != nil {
+			sendErrorInfoToChannel(ctx, c, err)
+			return
+		}
+		fi.symlink = target
+		fi.size = 0
 	}
 	select {
 	case c <- fi:
@@ -609,7 +1439,8 @@ func sendErrorInfoToChannel(ctx context.Context, c chan *fileInfo, err error) {
 
 // filterFilesForSync filters the source files from the given destination files, and returns
 // another channel which includes the files necessary to be synced.
-func filterFilesForSync(sourceFileChan, destFileChan chan *fileInfo, del bool) chan *fileOp {
+// onSkip is called for each source file skipped as up-to-date if not nil.
+func filterFilesForSync(sourceFileChan, destFileChan chan *fileInfo, del bool, cmp Comparator, onSkip func(*fileInfo)) chan *fileOp {
 	c := make(chan *fileOp)
 
 	destFiles, err := fileInfoChanToMap(destFileChan)
@@ -621,13 +1452,23 @@ func filterFilesForSync(sourceFileChan, destFileChan chan *fileInfo, del bool) c
 			return
 		}
 		for sourceInfo := range sourceFileChan {
-			destInfo, ok := destFiles[sourceInfo.name]
+			destInfo, ok := destFiles[sourceInfo.destKeyName()]
+			sourceInfo.destExists = ok
 			// source is necessary to sync if
-			// 1. The dest doesn't exist
-			// 2. The dest doesn't have the same size as the source
-			// 3. The dest is older than the source
-			if !ok || sourceInfo.size != destInfo.size || sourceInfo.lastModified.After(destInfo.lastModified) {
+			// 1. The source is not postponed
+			// 2. The dest doesn't exist
+			// 3. The comparator reports that the dest is out of date
+			if sourceInfo.postponed {
+				if onSkip != nil {
+					onSkip(sourceInfo)
+				}
+			} else if !ok || sourceInfo.err != nil || cmp.ShouldSync(sourceInfo.export(), destInfo.export()) {
+				if ok {
+					sourceInfo.overwritten = destInfo
+				}
 				c <- &fileOp{fileInfo: sourceInfo}
+			} else if onSkip != nil {
+				onSkip(sourceInfo)
 			}
 			if ok {
 				destInfo.existsInSource = true
@@ -16,6 +16,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/url"
@@ -26,6 +27,11 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 )
 
 const dummyFilename = "README.md"
@@ -557,8 +563,8 @@ func TestDryRun(t *testing.T) {
 			t.Error("File must not be downloaded on dry-run")
 		}
 		stats := m.GetStatistics()
-		if !reflect.DeepEqual(&stats, &SyncStatistics{}) {
-			t.Error("Statistics must not change on a dry-run")
+		if stats.Files != 0 || stats.Bytes != 0 || stats.DeletedFiles != 0 {
+			t.Errorf("Transfer statistics must not change on a dry-run, got %+v", stats)
 		}
 
 	})
@@ -604,8 +610,8 @@ func TestDryRun(t *testing.T) {
 			t.Error("Unexpected key", objs[0].path)
 		}
 		stats := m.GetStatistics()
-		if !reflect.DeepEqual(&stats, &SyncStatistics{}) {
-			t.Error("Statistics must not change on a dry-run")
+		if stats.Files != 0 || stats.Bytes != 0 || stats.DeletedFiles != 0 {
+			t.Errorf("Transfer statistics must not change on a dry-run, got %+v", stats)
 		}
 	})
 }
@@ -888,3 +894,59 @@ func (d *dummyLogger) Logf(format string, v ...interface{}) {
 func createLoggerWithLogFunc(log func(v ...interface{})) LoggerIF {
 	return &dummyLogger{log: log}
 }
+
+func TestOnComplete(t *testing.T) {
+	var results []SyncResult
+	m := New(getSession(), WithOnComplete(func(r SyncResult) {
+		results = append(results, r)
+	}))
+
+	err := m.Sync(context.Background(), "foo", "bar")
+	if err == nil {
+		t.Fatal("local to local sync is not supported")
+	}
+	if len(results) != 1 {
+		t.Fatalf("OnComplete must be called once, called %d times", len(results))
+	}
+	r := results[0]
+	if r.Source != "foo" || r.Dest != "bar" {
+		t.Errorf("Expected source=foo dest=bar, got source=%s dest=%s", r.Source, r.Dest)
+	}
+	if r.Err != err {
+		t.Errorf("Expected error %v, got %v", err, r.Err)
+	}
+	if r.EndTime.Before(r.StartTime) {
+		t.Errorf("EndTime %v must not be before StartTime %v", r.EndTime, r.StartTime)
+	}
+}
+
+type blockingS3 struct {
+	s3iface.S3API
+}
+
+func (s *blockingS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestOperationTimeout(t *testing.T) {
+	m := New(getSession(), WithOperationTimeout(20*time.Millisecond))
+	m.s3 = &blockingS3{}
+
+	done := make(chan error)
+	go func() {
+		done <- m.copyS3ToS3(context.Background(),
+			&fileInfo{name: "foo", size: 3},
+			&s3Path{bucket: "src"},
+			&s3Path{bucket: "dst"},
+		)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Operation must be timed out")
+	}
+}
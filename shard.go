@@ -0,0 +1,198 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+// Shard is a key range of the distributed sync processed by one of the workers.
+// The range is the same as WithKeyRange, i.e. Start < name <= End.
+type Shard struct {
+	ID    string `json:"id"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// receipt is the queue specific handle of the claimed shard.
+	receipt string
+}
+
+// ShardResult is the result of a shard reported to the queue.
+type ShardResult struct {
+	Shard
+	SyncSummary
+}
+
+// ShardQueue is the queue of the shards shared by the workers of the distributed sync.
+type ShardQueue interface {
+	// Push adds the shards to the queue.
+	Push(ctx context.Context, shards []Shard) error
+	// Claim takes a shard from the queue. It returns nil if the queue is empty.
+	Claim(ctx context.Context) (*Shard, error)
+	// Complete reports the result of the claimed shard.
+	// The failed shard should be returned to the queue to be retried.
+	Complete(ctx context.Context, shard *Shard, result ShardResult) error
+}
+
+// ShardsByBoundaries splits the whole key space into the shards
+// at the given boundaries in ascending order.
+// n boundaries make n+1 shards: ("", b1], (b1, b2], ..., (bn, "").
+func ShardsByBoundaries(boundaries ...string) []Shard {
+	shards := make([]Shard, 0, len(boundaries)+1)
+	start := ""
+	for i, b := range append(boundaries, "") {
+		shards = append(shards, Shard{ID: strconv.Itoa(i), Start: start, End: b})
+		start = b
+	}
+	return shards
+}
+
+// SyncShards runs a worker of the distributed sync.
+// It claims the shards from the queue, syncs the files in the key range of each shard
+// and reports the results until the queue becomes empty.
+// Multiple workers can run on different machines with the same source, dest and queue
+// to scale out the sync of a huge bucket.
+// The Manager must not be used for other syncs during SyncShards since the key range
+// of the Manager is overwritten by each shard.
+func (m *Manager) SyncShards(ctx context.Context, queue ShardQueue, source, dest string) error {
+	// The shards are not claimed by the Manager which fails every sync.
+	if err := m.checkPreset(); err != nil {
+		return err
+	}
+	errs := &multiErr{}
+	for {
+		shard, err := queue.Claim(ctx)
+		if err != nil {
+			errs.Append(err)
+			return errs
+		}
+		if shard == nil {
+			return errs.ErrOrNil()
+		}
+
+		r, err := m.syncShard(ctx, shard, source, dest)
+		if err != nil {
+			errs.Append(err)
+		}
+		if err := queue.Complete(ctx, shard, ShardResult{Shard: *shard, SyncSummary: r.Summary()}); err != nil {
+			errs.Append(err)
+			return errs
+		}
+	}
+}
+
+func (m *Manager) syncShard(ctx context.Context, shard *Shard, source, dest string) (*SyncResult, error) {
+	start, end := m.keyRangeStart, m.keyRangeEnd
+	defer func() {
+		m.keyRangeStart, m.keyRangeEnd = start, end
+	}()
+	m.keyRangeStart, m.keyRangeEnd = shard.Start, shard.End
+	return m.SyncWithResult(ctx, source, dest)
+}
+
+// DefaultShardWaitTime is the long polling wait time of SQSShardQueue to claim a shard.
+const DefaultShardWaitTime = 20 * time.Second
+
+// SQSShardQueue is the ShardQueue backed by the SQS queue.
+// The visibility timeout of the queue must be longer than the sync of a shard,
+// otherwise the shard is claimed by another worker during the sync.
+// The failed shards are left in the queue and retried after the visibility timeout,
+// so use the redrive policy to stop retrying the shards failing permanently.
+type SQSShardQueue struct {
+	sqs            sqsiface.SQSAPI
+	queueURL       string
+	resultQueueURL string
+	waitTime       time.Duration
+}
+
+// NewSQSShardQueue returns a new SQSShardQueue.
+// If resultQueueURL is not empty, the results of the shards are sent to it as JSON messages.
+func NewSQSShardQueue(client sqsiface.SQSAPI, queueURL, resultQueueURL string) *SQSShardQueue {
+	return &SQSShardQueue{
+		sqs:            client,
+		queueURL:       queueURL,
+		resultQueueURL: resultQueueURL,
+		waitTime:       DefaultShardWaitTime,
+	}
+}
+
+// Push implements ShardQueue.
+func (q *SQSShardQueue) Push(ctx context.Context, shards []Shard) error {
+	for _, s := range shards {
+		body, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if _, err := q.sqs.SendMessageWithContext(ctx, &sqs.SendMessageInput{
+			QueueUrl:    aws.String(q.queueURL),
+			MessageBody: aws.String(string(body)),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Claim implements ShardQueue.
+func (q *SQSShardQueue) Claim(ctx context.Context) (*Shard, error) {
+	out, err := q.sqs.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(q.queueURL),
+		MaxNumberOfMessages: aws.Int64(1),
+		WaitTimeSeconds:     aws.Int64(int64(q.waitTime / time.Second)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Messages) == 0 {
+		return nil, nil
+	}
+	msg := out.Messages[0]
+	shard := &Shard{}
+	if err := json.Unmarshal([]byte(aws.StringValue(msg.Body)), shard); err != nil {
+		return nil, err
+	}
+	shard.receipt = aws.StringValue(msg.ReceiptHandle)
+	return shard, nil
+}
+
+// Complete implements ShardQueue.
+func (q *SQSShardQueue) Complete(ctx context.Context, shard *Shard, result ShardResult) error {
+	if q.resultQueueURL != "" {
+		body, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if _, err := q.sqs.SendMessageWithContext(ctx, &sqs.SendMessageInput{
+			QueueUrl:    aws.String(q.resultQueueURL),
+			MessageBody: aws.String(string(body)),
+		}); err != nil {
+			return err
+		}
+	}
+	if result.Error != "" {
+		return nil
+	}
+	_, err := q.sqs.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(q.queueURL),
+		ReceiptHandle: aws.String(shard.receipt),
+	})
+	return err
+}
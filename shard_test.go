@@ -0,0 +1,113 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+type dummySQS struct {
+	sqsiface.SQSAPI
+	queues   map[string][]string
+	inFlight map[string]string
+	deleted  int
+}
+
+func (s *dummySQS) SendMessageWithContext(ctx aws.Context, in *sqs.SendMessageInput, opts ...request.Option) (*sqs.SendMessageOutput, error) {
+	s.queues[*in.QueueUrl] = append(s.queues[*in.QueueUrl], *in.MessageBody)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (s *dummySQS) ReceiveMessageWithContext(ctx aws.Context, in *sqs.ReceiveMessageInput, opts ...request.Option) (*sqs.ReceiveMessageOutput, error) {
+	q := s.queues[*in.QueueUrl]
+	if len(q) == 0 {
+		return &sqs.ReceiveMessageOutput{}, nil
+	}
+	s.queues[*in.QueueUrl] = q[1:]
+	receipt := strconv.Itoa(len(s.inFlight))
+	s.inFlight[receipt] = q[0]
+	return &sqs.ReceiveMessageOutput{Messages: []*sqs.Message{
+		{Body: aws.String(q[0]), ReceiptHandle: aws.String(receipt)},
+	}}, nil
+}
+
+func (s *dummySQS) DeleteMessageWithContext(ctx aws.Context, in *sqs.DeleteMessageInput, opts ...request.Option) (*sqs.DeleteMessageOutput, error) {
+	delete(s.inFlight, *in.ReceiptHandle)
+	s.deleted++
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func TestShardsByBoundaries(t *testing.T) {
+	expected := []Shard{
+		{ID: "0", Start: "", End: "g"},
+		{ID: "1", Start: "g", End: "p"},
+		{ID: "2", Start: "p", End: ""},
+	}
+	if shards := ShardsByBoundaries("g", "p"); !reflect.DeepEqual(expected, shards) {
+		t.Errorf("Expected %v, got %v", expected, shards)
+	}
+}
+
+func TestSyncShards(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	q := &dummySQS{queues: make(map[string][]string), inFlight: make(map[string]string)}
+	queue := NewSQSShardQueue(q, "shards", "results")
+	if err := queue.Push(context.Background(), ShardsByBoundaries("g")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &dummyKeyRangeS3{}
+	m := New(session.New(), WithKeyRange("a", "z"))
+	m.s3 = s
+	if err := m.SyncShards(context.Background(), queue, "s3://bucket/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"", "prefix/g"}; !reflect.DeepEqual(expected, s.startAfter) {
+		t.Errorf("Expected listings starting after %v, got %v", expected, s.startAfter)
+	}
+	if q.deleted != 2 || len(q.inFlight) != 0 {
+		t.Errorf("Expected all shards to be completed, deleted: %d, in flight: %v", q.deleted, q.inFlight)
+	}
+	if n := len(q.queues["results"]); n != 2 {
+		t.Fatalf("Expected 2 results, got %d", n)
+	}
+	var r ShardResult
+	if err := json.Unmarshal([]byte(q.queues["results"][1]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "1" || r.Start != "g" || r.Source != "s3://bucket/prefix" || r.Error != "" {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if m.keyRangeStart != "a" || m.keyRangeEnd != "z" {
+		t.Errorf("Key range of the Manager must be restored, got (%s, %s)", m.keyRangeStart, m.keyRangeEnd)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package s3sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger returns the logger writing the entries to the given slog.Logger.
+// The entries of the file operations carry structured attributes
+// such as op, bucket, key, size, duration and attempt.
+func NewSlogLogger(l *slog.Logger) LoggerIF {
+	return &slogLogger{l: l}
+}
+
+// Log implements LoggerIF.
+func (l *slogLogger) Log(v ...interface{}) {
+	l.l.Info(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// Logf implements LoggerIF.
+func (l *slogLogger) Logf(format string, v ...interface{}) {
+	l.l.Info(fmt.Sprintf(format, v...))
+}
+
+// LogAttrs implements StructuredLoggerIF.
+func (l *slogLogger) LogAttrs(ctx context.Context, msg string, err error, args ...interface{}) {
+	if err != nil {
+		l.l.ErrorContext(ctx, msg, append(args, "error", err)...)
+		return
+	}
+	l.l.InfoContext(ctx, msg, args...)
+}
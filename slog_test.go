@@ -0,0 +1,80 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package s3sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetLogger(NewSlogLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+	defer SetLogger(nil)
+
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	if err := ioutil.WriteFile(filepath.Join(temp, "foo"), []byte("foo"), 0644); err != nil {
+		t.Fatal("Failed to write", err)
+	}
+
+	m := New(getSession())
+	ctx := withAttempt(context.Background(), 2)
+	if err := m.deleteLocal(ctx, &fileInfo{name: "foo"}, temp); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.deleteLocal(ctx, &fileInfo{name: "foo"}, temp); err == nil {
+		t.Fatal("Deleting non-existent file must fail")
+	}
+
+	var entries []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var e map[string]interface{}
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	for i, level := range []string{"INFO", "INFO", "INFO", "ERROR"} {
+		e := entries[i]
+		if e["level"] != level {
+			t.Errorf("Entry %d is expected to be %s, got %v", i, level, e["level"])
+		}
+		if e["op"] != "delete" || e["path"] != filepath.Join(temp, "foo") || e["attempt"] != 2.0 {
+			t.Errorf("Entry %d has unexpected attributes: %v", i, e)
+		}
+	}
+	if _, ok := entries[1]["duration"]; !ok {
+		t.Error("Done entry must have duration")
+	}
+	if _, ok := entries[3]["error"]; !ok {
+		t.Error("Failed entry must have error")
+	}
+}
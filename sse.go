@@ -0,0 +1,25 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// sseChangesETag returns whether the ETag of the objects written with
+// the server-side encryption options is not the MD5 checksum of the contents.
+func (m *Manager) sseChangesETag() bool {
+	return m.sseCustomerKey != nil || aws.StringValue(m.sse) == s3.ServerSideEncryptionAwsKms
+}
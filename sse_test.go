@@ -0,0 +1,84 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestSSE(t *testing.T) {
+	copyFile := func(t *testing.T, opts ...Option) (*Manager, *s3.CopyObjectInput) {
+		s := &dummyMetadataS3{}
+		m := New(session.New(), opts...)
+		m.s3 = s
+		err := m.copyS3ToS3(context.Background(),
+			&fileInfo{name: "foo", size: 3},
+			&s3Path{bucket: "src"},
+			&s3Path{bucket: "dst"},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m, s.copied
+	}
+
+	t.Run("None", func(t *testing.T) {
+		m, in := copyFile(t)
+		if in.ServerSideEncryption != nil || in.SSECustomerKey != nil {
+			t.Errorf("Unexpected encryption: %v", in)
+		}
+		if m.sseChangesETag() {
+			t.Error("ETag must be MD5 without encryption")
+		}
+	})
+	t.Run("SSE-S3", func(t *testing.T) {
+		m, in := copyFile(t, WithSSE(s3.ServerSideEncryptionAes256))
+		if aws.StringValue(in.ServerSideEncryption) != s3.ServerSideEncryptionAes256 {
+			t.Errorf("Expected SSE-S3, got %v", in.ServerSideEncryption)
+		}
+		if m.sseChangesETag() {
+			t.Error("ETag of SSE-S3 object must be MD5")
+		}
+	})
+	t.Run("SSE-KMS", func(t *testing.T) {
+		m, in := copyFile(t, WithKMSKeyID("arn:aws:kms:us-east-1:123456789012:key/test"))
+		if aws.StringValue(in.ServerSideEncryption) != s3.ServerSideEncryptionAwsKms {
+			t.Errorf("Expected SSE-KMS, got %v", in.ServerSideEncryption)
+		}
+		if aws.StringValue(in.SSEKMSKeyId) != "arn:aws:kms:us-east-1:123456789012:key/test" {
+			t.Errorf("Unexpected key ID: %v", in.SSEKMSKeyId)
+		}
+		if !m.sseChangesETag() {
+			t.Error("ETag of SSE-KMS object must not be MD5")
+		}
+	})
+	t.Run("SSE-C", func(t *testing.T) {
+		key := "01234567890123456789012345678901"
+		m, in := copyFile(t, WithSSECustomerKey([]byte(key)))
+		if aws.StringValue(in.SSECustomerKey) != key || aws.StringValue(in.CopySourceSSECustomerKey) != key {
+			t.Errorf("Customer key must be set to both of source and destination: %v", in)
+		}
+		if aws.StringValue(in.SSECustomerAlgorithm) != s3.ServerSideEncryptionAes256 {
+			t.Errorf("Unexpected algorithm: %v", in.SSECustomerAlgorithm)
+		}
+		if !m.sseChangesETag() {
+			t.Error("ETag of SSE-C object must not be MD5")
+		}
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// stateFile is the content of the state file, the source and destination files
+// compared to be in sync by the previous syncs.
+type stateFile struct {
+	// Pairs is the files in sync keyed by the source and the destination of the sync,
+	// and then by the name of the source file.
+	Pairs map[string]map[string]statePair `json:"pairs"`
+}
+
+// statePair is the source and destination files compared to be in sync.
+type statePair struct {
+	Source stateEntry `json:"source"`
+	Dest   stateEntry `json:"dest"`
+}
+
+// stateEntry is the state of a file, which identifies the contents of the file.
+type stateEntry struct {
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	ETag         string    `json:"etag,omitempty"`
+}
+
+func newStateEntry(f *FileInfo) stateEntry {
+	return stateEntry{Size: f.Size, LastModified: f.LastModified, ETag: f.ETag}
+}
+
+// match returns whether the file is unchanged from the state.
+func (e stateEntry) match(f *FileInfo) bool {
+	return e.Size == f.Size && e.ETag == f.ETag && e.LastModified.Equal(f.LastModified)
+}
+
+type stateKey struct{}
+
+// stateRun is the state used by a sync call.
+type stateRun struct {
+	mu   sync.Mutex
+	prev map[string]statePair
+	next map[string]statePair
+}
+
+// useState returns the context skipping the comparisons of the files unchanged
+// since they are compared to be in sync by the previous syncs if the state file is enabled,
+// and the function to be called with the error of the sync when the sync finishes.
+// The files compared to be in sync are written to the state file. The state of the failed
+// sync is merged with the previous one since the listings may be incomplete.
+func (m *Manager) useState(ctx context.Context, source, dest string) (context.Context, func(*error)) {
+	if m.stateFilePath == "" {
+		return ctx, func(*error) {}
+	}
+	var f stateFile
+	b, err := ioutil.ReadFile(m.stateFilePath)
+	if err == nil {
+		if err = json.Unmarshal(b, &f); err != nil {
+			println("Ignoring the state file", err.Error())
+			f = stateFile{}
+		}
+	} else if !os.IsNotExist(err) {
+		println("Ignoring the state file", err.Error())
+	}
+	pair := source + "\n" + dest
+	run := &stateRun{prev: f.Pairs[pair], next: make(map[string]statePair)}
+	return context.WithValue(ctx, stateKey{}, run), func(err *error) {
+		if m.isDryRun(ctx) {
+			return
+		}
+		run.mu.Lock()
+		defer run.mu.Unlock()
+		if *err != nil {
+			for name, p := range run.prev {
+				if _, ok := run.next[name]; !ok {
+					run.next[name] = p
+				}
+			}
+		}
+		if f.Pairs == nil {
+			f.Pairs = make(map[string]map[string]statePair)
+		}
+		f.Pairs[pair] = run.next
+		b, werr := json.Marshal(&f)
+		if werr == nil {
+			werr = writeStateFile(m.stateFilePath, b)
+		}
+		if werr != nil {
+			println("Failed to write the state file", werr.Error())
+		}
+	}
+}
+
+// writeStateFile replaces the state file by a temporary file written in the same directory,
+// so that a crash or a concurrent sync never leaves the truncated state file.
+func writeStateFile(filename string, b []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(filename), ".s3sync-state-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+// stateComparator returns the comparator skipping the comparison of the files
+// unchanged since they are compared to be in sync, and recording the files
+// compared to be in sync.
+func (m *Manager) stateComparator(ctx context.Context, cmp Comparator) Comparator {
+	run, ok := ctx.Value(stateKey{}).(*stateRun)
+	if !ok {
+		return cmp
+	}
+	return ComparatorFunc(func(src, dst *FileInfo) bool {
+		p, ok := run.prev[src.Name]
+		if !ok || !p.Source.match(src) || !p.Dest.match(dst) {
+			if cmp.ShouldSync(src, dst) {
+				return true
+			}
+			p = statePair{Source: newStateEntry(src), Dest: newStateEntry(dst)}
+		}
+		run.mu.Lock()
+		run.next[src.Name] = p
+		run.mu.Unlock()
+		return false
+	})
+}
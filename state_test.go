@@ -0,0 +1,114 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestStateFile(t *testing.T) {
+	testCases := map[string]struct {
+		options []Option
+	}{
+		"Default":     {},
+		"HashWorkers": {options: []Option{WithHashWorkers(2)}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+			local := filepath.Join(temp, "local")
+			if err := os.Mkdir(local, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range []string{"a", "b", "c"} {
+				if err := ioutil.WriteFile(filepath.Join(local, name), []byte("a"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var compared int32
+			cmp := ComparatorFunc(func(src, dst *FileInfo) bool {
+				atomic.AddInt32(&compared, 1)
+				return false
+			})
+			s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b", "prefix/c"}}}
+			m := New(session.New(), append(tc.options, WithComparator(cmp), WithStateFile(filepath.Join(temp, "state.json")))...)
+			m.s3 = s
+			sync := func() int32 {
+				atomic.StoreInt32(&compared, 0)
+				if err := m.Sync(context.Background(), "s3://bucket/prefix", local); err != nil {
+					t.Fatal(err)
+				}
+				return atomic.LoadInt32(&compared)
+			}
+
+			if n := sync(); n != 3 {
+				t.Errorf("First sync must compare all files, got %d", n)
+			}
+			if n := sync(); n != 0 {
+				t.Errorf("Unchanged files must not be compared, got %d", n)
+			}
+			modified := time.Now().Add(-time.Hour)
+			if err := os.Chtimes(filepath.Join(local, "b"), modified, modified); err != nil {
+				t.Fatal(err)
+			}
+			if n := sync(); n != 1 {
+				t.Errorf("Only the changed file must be compared, got %d", n)
+			}
+			if n := sync(); n != 0 {
+				t.Errorf("The compared file must be recorded, got %d", n)
+			}
+			if len(s.downloaded) != 0 {
+				t.Errorf("Expected no download, got %v", s.downloaded)
+			}
+		})
+	}
+}
+
+func TestWriteStateFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "state.json")
+	for _, content := range []string{`{"pairs":{"long":{}}}`, `{}`} {
+		if err := writeStateFile(filename, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("Expected %s, got %s", content, b)
+		}
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("Expected only the state file, got %d files", len(files))
+	}
+}
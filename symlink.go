@@ -0,0 +1,114 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// SymlinkPolicy is the handling of the local symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinkFollow dereferences the symbolic links.
+	// Linked files are synced as regular files and linked directories are walked into.
+	SymlinkFollow SymlinkPolicy = iota
+	// SymlinkSkip ignores the symbolic links.
+	SymlinkSkip
+	// SymlinkPreserveAsMetadata uploads the symbolic links as empty objects storing
+	// the link target in the metadata, and recreates the links on download.
+	SymlinkPreserveAsMetadata
+)
+
+// Metadata key to store the target of the symbolic link.
+const metadataSymlink = "symlink-target"
+
+// symlinkWalk returns the walk function handling the symbolic links by the policy.
+func (m *Manager) symlinkWalk(walk func(string, filepath.WalkFunc) error) func(string, filepath.WalkFunc) error {
+	if m.symlinkPolicy == SymlinkPreserveAsMetadata {
+		// sendFileInfoToChannel stores the link target.
+		return walk
+	}
+	return func(root string, fn filepath.WalkFunc) error {
+		// Real paths of the walked directories to avoid the loop of the links.
+		visited := make(map[string]bool)
+		if real, err := filepath.EvalSymlinks(root); err == nil {
+			visited[real] = true
+		}
+		var walkFn filepath.WalkFunc
+		walkFn = func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.Mode()&os.ModeSymlink == 0 {
+				return fn(path, info, err)
+			}
+			if m.symlinkPolicy == SymlinkSkip {
+				return nil
+			}
+			stat, err := os.Stat(path)
+			if err != nil {
+				println("Skipping broken symbolic link", path)
+				return nil
+			}
+			if !stat.IsDir() {
+				return fn(path, stat, nil)
+			}
+			real, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return fn(path, nil, err)
+			}
+			if visited[real] && path != root {
+				println("Skipping symbolic link loop", path)
+				return nil
+			}
+			visited[real] = true
+			// Trailing separator makes the walk function to follow the link.
+			return walk(path+string(filepath.Separator), walkFn)
+		}
+		return walk(root, walkFn)
+	}
+}
+
+// restoreSymlink recreates the symbolic link if the object stores the link target,
+// and returns whether the link is created.
+func (m *Manager) restoreSymlink(ctx context.Context, bucket, key, filename string) (bool, error) {
+	head, err := m.regionalClient(ctx, m.sourceClient(), bucket).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return false, err
+	}
+	target, ok := metadataValue(head.Metadata, metadataSymlink)
+	if !ok {
+		return false, nil
+	}
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, os.Symlink(target, filename)
+}
+
+// removeSymlink removes the existing symbolic link to avoid writing to the link target.
+func removeSymlink(filename string) error {
+	if stat, err := os.Lstat(filename); err == nil && stat.Mode()&os.ModeSymlink != 0 {
+		return os.Remove(filename)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+func TestSymlinkPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require the privilege on Windows")
+	}
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	if err := os.Mkdir(filepath.Join(temp, "d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "d/x"} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte("foo"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{"b": "a", "e": "d", "loop": ".", "broken": "nonexistent"}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(temp, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := map[string]struct {
+		policy   SymlinkPolicy
+		expected []string
+	}{
+		"Follow":   {SymlinkFollow, []string{"a:3", "b:3", "d/x:3", "e/x:3"}},
+		"Skip":     {SymlinkSkip, []string{"a:3", "d/x:3"}},
+		"Preserve": {SymlinkPreserveAsMetadata, []string{"a:3", "b:0->a", "broken:0->nonexistent", "d/x:3", "e:0->d", "loop:0->."}},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), WithSymlinkPolicy(tt.policy))
+			var files []string
+			for fi := range m.listLocalFiles(context.Background(), temp, nil) {
+				if fi.err != nil {
+					t.Fatal(fi.err)
+				}
+				s := filepath.ToSlash(fi.name) + ":" + strconv.FormatInt(fi.size, 10)
+				if fi.symlink != "" {
+					s += "->" + fi.symlink
+				}
+				files = append(files, s)
+			}
+			sort.Strings(files)
+			if !reflect.DeepEqual(tt.expected, files) {
+				t.Errorf("Expected %v, got %v", tt.expected, files)
+			}
+		})
+	}
+}
+
+func TestSymlinkPolicy_Sorted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require the privilege on Windows")
+	}
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	if err := os.Mkdir(filepath.Join(temp, "real"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"link.txt", "real/x"} {
+		if err := ioutil.WriteFile(filepath.Join(temp, name), []byte("foo"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("real", filepath.Join(temp, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		policy   SymlinkPolicy
+		expected []string
+	}{
+		// "link.txt" < "link/x" in the order of S3 listing.
+		"Follow":   {SymlinkFollow, []string{"link.txt", "link/x", "real/x"}},
+		"Preserve": {SymlinkPreserveAsMetadata, []string{"link", "link.txt", "real/x"}},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), WithSymlinkPolicy(tt.policy), WithStreamingDiff())
+			var files []string
+			for fi := range m.listLocalFiles(context.Background(), temp, nil) {
+				if fi.err != nil {
+					t.Fatal(fi.err)
+				}
+				files = append(files, filepath.ToSlash(fi.name))
+			}
+			if !reflect.DeepEqual(tt.expected, files) {
+				t.Errorf("Expected %v, got %v", tt.expected, files)
+			}
+		})
+	}
+}
+
+type dummySymlinkS3 struct {
+	s3iface.S3API
+}
+
+func (s *dummySymlinkS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	if *in.Key == "link" {
+		return &s3.HeadObjectOutput{Metadata: map[string]*string{"Symlink-Target": aws.String("target")}}, nil
+	}
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func TestRestoreSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require the privilege on Windows")
+	}
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	m := New(session.New())
+	m.s3 = &dummySymlinkS3{}
+
+	filename := filepath.Join(temp, "link")
+	if err := ioutil.WriteFile(filename, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	linked, err := m.restoreSymlink(context.Background(), "bucket", "link", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !linked {
+		t.Fatal("Symbolic link must be restored")
+	}
+	if target, err := os.Readlink(filename); err != nil || target != "target" {
+		t.Errorf("Expected link to target, got %s (%v)", target, err)
+	}
+
+	if linked, err := m.restoreSymlink(context.Background(), "bucket", "file", filepath.Join(temp, "file")); err != nil || linked {
+		t.Errorf("Regular object must not be linked, got %v (%v)", linked, err)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// encodeTags returns the tag set in the URL query format of the Tagging parameter.
+func encodeTags(tags map[string]string) string {
+	v := make(url.Values, len(tags))
+	for key, value := range tags {
+		v.Set(key, value)
+	}
+	return v.Encode()
+}
+
+// copySourceTags sets the tags of the source object to the input of the multipart copy,
+// since the multipart upload doesn't copy the tags unlike CopyObject.
+func (m *Manager) copySourceTags(ctx context.Context, in *s3.CopyObjectInput, sourceBucket, sourceKey string) error {
+	out, err := m.regionalClient(ctx, m.sourceClient(), sourceBucket).GetObjectTaggingWithContext(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(sourceBucket),
+		Key:    aws.String(sourceKey),
+	})
+	if err != nil {
+		return err
+	}
+	if len(out.TagSet) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(out.TagSet))
+	for _, tag := range out.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	in.Tagging = aws.String(encodeTags(tags))
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// getUploader returns the uploader shared by all uploads of the Manager
+// so that the part buffers are pooled across the files.
+func (m *Manager) getUploader() *s3manager.Uploader {
+	m.uploaderOnce.Do(func() {
+		m.uploader = s3manager.NewUploaderWithClient(m.s3, func(u *s3manager.Uploader) {
+			if m.uploaderConcurrency > 0 {
+				u.Concurrency = m.uploaderConcurrency
+			}
+			if m.uploadPartSize > 0 {
+				u.PartSize = m.uploadPartSize
+			}
+		})
+		for _, o := range m.uploaderOpts {
+			o(m.uploader)
+		}
+	})
+	return m.uploader
+}
+
+// withUploaderRequestOptions returns the option of an upload adding the request options.
+// Unlike s3manager.WithUploaderRequestOptions, the request options of the shared uploader
+// are never overwritten by the concurrent uploads.
+func withUploaderRequestOptions(opts ...request.Option) func(*s3manager.Uploader) {
+	return func(u *s3manager.Uploader) {
+		n := len(u.RequestOptions)
+		u.RequestOptions = append(u.RequestOptions[:n:n], opts...)
+	}
+}
+
+// withUploaderClient returns the option of an upload using the client.
+func withUploaderClient(client s3iface.S3API) func(*s3manager.Uploader) {
+	return func(u *s3manager.Uploader) {
+		u.S3 = client
+	}
+}
+
+// withDownloaderClient returns the option of a download using the client.
+func withDownloaderClient(client s3iface.S3API) func(*s3manager.Downloader) {
+	return func(d *s3manager.Downloader) {
+		d.S3 = client
+	}
+}
+
+// getDownloader returns the downloader shared by all downloads of the Manager.
+func (m *Manager) getDownloader() *s3manager.Downloader {
+	m.downloaderOnce.Do(func() {
+		m.downloader = s3manager.NewDownloaderWithClient(m.sourceClient(), func(d *s3manager.Downloader) {
+			if m.downloaderConcurrency > 0 {
+				d.Concurrency = m.downloaderConcurrency
+			}
+			if m.downloadPartSize > 0 {
+				d.PartSize = m.downloadPartSize
+			}
+		})
+		for _, o := range m.downloaderOpts {
+			o(m.downloader)
+		}
+	})
+	return m.downloader
+}
+
+// getWholeObject downloads the object by a single GetObject request without the Range header
+// instead of the downloader. It is used for the S3 Object Lambda access points,
+// since the transformation may not support the range requests and the size of
+// the transformed object may differ from the listing.
+func (m *Manager) getWholeObject(ctx context.Context, w io.WriterAt, in *s3.GetObjectInput) (int64, error) {
+	out, err := m.regionalClient(ctx, m.sourceClient(), aws.StringValue(in.Bucket)).GetObjectWithContext(ctx, in)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Body.Close()
+	return io.Copy(&offsetWriter{w: w}, out.Body)
+}
+
+// offsetWriter writes to the io.WriterAt sequentially.
+type offsetWriter struct {
+	w   io.WriterAt
+	off int64
+}
+
+func (w *offsetWriter) Write(b []byte) (int, error) {
+	n, err := w.w.WriteAt(b, w.off)
+	w.off += int64(n)
+	return n, err
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyObjectLambdaS3 struct {
+	dummyKeyRangeS3
+	mu     sync.Mutex
+	ranges []string
+}
+
+func (s *dummyObjectLambdaS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ranges = append(s.ranges, aws.StringValue(in.Range))
+	// Transformed object is larger than the listed size.
+	return &s3.GetObjectOutput{
+		Body:          ioutil.NopCloser(strings.NewReader("TRANSFORMED")),
+		ContentLength: aws.Int64(11),
+	}, nil
+}
+
+func TestDownload_ObjectLambda(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	const olap = "arn:aws:s3-object-lambda:us-east-1:123456789012:accesspoint/olap"
+	s := &dummyObjectLambdaS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}}
+	m := New(session.New())
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://"+olap+"/prefix", temp); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.ranges) != 1 || s.ranges[0] != "" {
+		t.Errorf("Expected a request without range, got %v", s.ranges)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(temp, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "TRANSFORMED" {
+		t.Errorf("Unexpected content %q", b)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errTrashKind is returned if WithLocalTrash is used for the S3 destination.
+var errTrashKind = errors.New("local trash can't be used for the S3 destination")
+
+// checkTrash returns an error if the trash can't be used for the destination,
+// and purges the trashed files older than the retention.
+func (m *Manager) checkTrash(ctx context.Context, destIsS3 bool) error {
+	if m.trash == "" {
+		return nil
+	}
+	if destIsS3 {
+		return errTrashKind
+	}
+	return m.purgeTrash(ctx)
+}
+
+// trashLocal moves the local file under the destination path to the trash directory
+// instead of removing it. The trashed file has the same relative path as the file,
+// replacing the file previously trashed by the same path, and its modification
+// time is set to the time of the deletion for the retention.
+func (m *Manager) trashLocal(ctx context.Context, destPath, filename string) error {
+	trashFilename := filepath.Join(m.trash, relativeName(destPath, filename))
+	logOp(ctx, []interface{}{"op", "trash", "path", filename}, "Trashing", filename, "to", trashFilename)
+
+	if err := os.MkdirAll(filepath.Dir(trashFilename), 0755); err != nil {
+		return err
+	}
+	if err := moveLocalFile(filename, trashFilename); err != nil {
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(trashFilename, now, now)
+}
+
+// purgeTrash removes the trashed files older than the retention, and the
+// directories emptied by the purge. Nothing is purged if the retention is zero.
+func (m *Manager) purgeTrash(ctx context.Context) error {
+	if m.trashRetention <= 0 {
+		return nil
+	}
+	expiry := time.Now().Add(-m.trashRetention)
+	var dirs []string
+	err := filepath.Walk(m.trash, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == m.trash {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			if path != m.trash {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if !info.ModTime().Before(expiry) {
+			return nil
+		}
+		logOp(ctx, []interface{}{"op", "purge", "path", path}, "Purging", path)
+		if m.isDryRun(ctx) {
+			return nil
+		}
+		return os.Remove(path)
+	})
+	if err != nil || m.isDryRun(ctx) {
+		return err
+	}
+	// Remove the deepest directories first. Non-empty ones fail and are kept.
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+	return nil
+}
+
+// relativeName returns the path of the file relative to the destination path,
+// or the base name if the file is not under the destination path.
+func relativeName(destPath, filename string) string {
+	rel, err := filepath.Rel(destPath, filename)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return filepath.Base(filename)
+	}
+	return rel
+}
+
+// moveLocalFile moves the file, copying it if the rename fails across the file systems.
+func moveLocalFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyLocalFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
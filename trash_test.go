@@ -0,0 +1,128 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestLocalTrash(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	dest := filepath.Join(temp, "dest")
+	trash := filepath.Join(temp, "trash")
+
+	for name, data := range map[string]string{
+		"dest/a":            "old",
+		"dest/dir/stale":    "stale",
+		"trash/dir/stale":   "trashed before",
+		"trash/expired/old": "expired",
+	} {
+		filename := filepath.Join(temp, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+			t.Fatal("Failed to write", err)
+		}
+	}
+	expired := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(trash, "expired", "old"), expired, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}}
+	m := New(session.New(), WithDelete(), WithLocalTrash(trash, 24*time.Hour))
+	m.s3 = s
+	if err := m.Sync(context.Background(), "s3://bucket/prefix", dest); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "dir", "stale")); !os.IsNotExist(err) {
+		t.Error("Expected the stale file to be deleted")
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(trash, "dir", "stale")); err != nil || string(data) != "stale" {
+		t.Errorf("Expected the stale file to be trashed, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "a")); !os.IsNotExist(err) {
+		t.Error("Expected the overwritten file not to be trashed")
+	}
+	if _, err := os.Stat(filepath.Join(trash, "expired")); !os.IsNotExist(err) {
+		t.Error("Expected the expired file and its directory to be purged")
+	}
+}
+
+func TestLocalTrash_Retention(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	filename := filepath.Join(temp, "dir", "file")
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filename, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	expired := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filename, expired, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		opts   []Option
+		purged bool
+	}{
+		"Forever": {[]Option{WithLocalTrash(temp, 0)}, false},
+		"DryRun":  {[]Option{WithLocalTrash(temp, time.Hour), WithDryRun()}, false},
+		"Expired": {[]Option{WithLocalTrash(temp, time.Hour)}, true},
+	}
+	for _, name := range []string{"Forever", "DryRun", "Expired"} {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New(), tc.opts...)
+			if err := m.purgeTrash(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			_, err := os.Stat(filename)
+			if purged := os.IsNotExist(err); purged != tc.purged {
+				t.Errorf("Expected purged: %v, got: %v", tc.purged, purged)
+			}
+		})
+	}
+	if _, err := os.Stat(temp); err != nil {
+		t.Error("Expected the trash directory to be kept", err)
+	}
+}
+
+func TestLocalTrash_Kind(t *testing.T) {
+	m := New(session.New(), WithLocalTrash("trash", 0))
+	if err := m.checkTrash(context.Background(), true); err != errTrashKind {
+		t.Errorf("Expected %v, got %v", errTrashKind, err)
+	}
+	if err := New(session.New(), WithLocalTrash(filepath.Join(os.TempDir(), "s3sync-nonexistent"), time.Hour)).checkTrash(context.Background(), false); err != nil {
+		t.Errorf("Expected the missing trash to be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// ErrInvalidOption is returned by NewWithOptionsValidated if the options are invalid.
+var ErrInvalidOption = errors.New("invalid option")
+
+// NewWithOptionsValidated returns a new Manager like New,
+// but returns ErrInvalidOption if the options are invalid or conflicting
+// so that the misconfiguration is caught before the sync.
+func NewWithOptionsValidated(sess *session.Session, options ...Option) (*Manager, error) {
+	m := New(sess, options...)
+	if err := m.validate(sess); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// validate checks the combination of the options.
+func (m *Manager) validate(sess *session.Session) error {
+	var msgs []string
+	check := func(invalid bool, msg string) {
+		if invalid {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	anonymous := sess != nil && sess.Config.Credentials == credentials.AnonymousCredentials
+	sse := aws.StringValue(m.sse)
+
+	check(m.unknownPreset != "", fmt.Sprintf("unknown preset %q", m.unknownPreset))
+	check(m.executor == nil && m.nJobs < 1, "WithParallel must be positive")
+	check(m.readOnly && m.del, "WithDelete conflicts with WithReadOnly")
+	check(m.sseCustomerKey != nil && len(*m.sseCustomerKey) != 32, "WithSSECustomerKey requires 256-bit key")
+	check(m.sseCustomerKey != nil && m.sse != nil, "WithSSECustomerKey conflicts with WithSSE and WithKMSKeyID")
+	check(m.sse != nil && sse != s3.ServerSideEncryptionAes256 && sse != s3.ServerSideEncryptionAwsKms,
+		fmt.Sprintf("WithSSE doesn't support %q", sse))
+	check(m.sseKMSKeyID != nil && sse != s3.ServerSideEncryptionAwsKms, "WithKMSKeyID requires SSE-KMS")
+	check(anonymous && sse == s3.ServerSideEncryptionAwsKms, "SSE-KMS requires credentials")
+	check(anonymous && m.sseCustomerKey != nil, "SSE-C requires credentials")
+	check(m.manifestHMACKey != nil && m.manifestName == "", "WithManifestHMACKey requires WithManifest")
+	check(m.skipRecent < 0, "WithSkipRecentlyModified must not be negative")
+	check(m.stabilityDelay < 0, "WithStabilityCheck must not be negative")
+	check(m.keyRangeStart != "" && m.keyRangeEnd != "" && m.keyRangeStart >= m.keyRangeEnd,
+		"WithKeyRange requires start before end")
+	check(m.downloaderConcurrency < 0 || m.uploaderConcurrency < 0, "transfer concurrency must not be negative")
+	check(m.downloadPartSize < 0, "WithDownloadPartSize must not be negative")
+	check(m.uploadPartSize != 0 && m.uploadPartSize < s3manager.MinUploadPartSize,
+		fmt.Sprintf("WithUploadPartSize must be at least %d", s3manager.MinUploadPartSize))
+	check(m.copyConcurrency < 0, "WithCopyConcurrency must not be negative")
+	check(m.copyPartSize != 0 && (m.copyPartSize < s3manager.MinUploadPartSize || m.copyPartSize > maxCopyObjectSize),
+		fmt.Sprintf("WithCopyPartSize must be between %d and %d", s3manager.MinUploadPartSize, maxCopyObjectSize))
+	check(m.symlinkPolicy < SymlinkFollow || m.symlinkPolicy > SymlinkPreserveAsMetadata, "unknown symlink policy")
+	check(m.cleanupOlderThan < 0, "WithIncompleteUploadCleanup must not be negative")
+	check(m.timeBudgetMargin < 0, "WithTimeBudgetMargin must not be negative")
+	check(m.opTimeout < 0 || m.listTimeout < 0, "timeouts must not be negative")
+	check(m.listingCachePath != "" && m.listingCacheTTL <= 0, "WithListingCache requires positive TTL")
+	check(m.errorPolicy.maxErrors < 0, "MaxErrors must not be negative")
+	check(m.maxRetries < 0, "WithRetries must not be negative")
+	check(m.checksumPolicy < ChecksumDefault || m.checksumPolicy > ChecksumSHA256Only, "unknown checksum policy")
+	check(m.largeFileWorkers < 0 || m.largeFileThreshold < 0, "WithLargeFileLane must not be negative")
+	check(m.executor == nil && m.largeFileWorkers > 0 && m.largeFileWorkers >= m.nJobs,
+		"WithLargeFileLane requires fewer workers than WithParallel")
+	check(m.deleteOrdering < DeleteDuring || m.deleteOrdering > DeleteAfter, "unknown delete ordering")
+	check(m.readAheadFiles < 0 || m.readAheadSize < 0, "WithReadAhead must not be negative")
+	if m.backup != "" {
+		_, _, err := m.backupTarget()
+		check(err != nil, fmt.Sprintf("WithBackupPrefix has invalid url: %v", err))
+	}
+	if m.endpoint != "" {
+		err := validateEndpoint(m.endpoint)
+		check(err != nil, fmt.Sprintf("WithEndpoint has invalid url: %v", err))
+	}
+	check(m.oddKeyPolicy < OddKeySkip || m.oddKeyPolicy > OddKeyEscape, "unknown odd key policy")
+	check(m.compression != nil && m.compression.codec == nil, "WithCompression requires the codec")
+	check(m.compression != nil && m.compression.minSize < 0, "WithCompression must not be negative")
+	check(m.encryption != nil && m.encryption.invalidKey, "WithClientSideEncryptionKey requires a 32 bytes key")
+	check(m.encryption != nil && m.compression != nil, "WithCompression can't be used with the client-side encryption")
+	check(m.trash != "" && m.backup != "", "WithLocalTrash can't be used with WithBackupPrefix")
+	check(m.trashRetention < 0, "WithLocalTrash must not be negative")
+	check(m.verifyMode&^(VerifyETag|VerifySHA256) != 0, "unknown verify mode")
+	check(m.hashWorkers < 0, "WithHashWorkers must not be negative")
+	check(m.runtimeStatsInterval < 0, "WithRuntimeStats must not be negative")
+	check(m.maxDepth < 0, "WithMaxDepth must not be negative")
+	check(m.warmStartMaxAge < 0, "WithWarmStart must not be negative")
+	check(m.minSize < 0 || m.maxSize < 0, "WithMinSize and WithMaxSize must not be negative")
+	check(m.maxSize > 0 && m.minSize > m.maxSize, "WithMinSize must not exceed WithMaxSize")
+	check(!m.modifiedAfter.IsZero() && !m.modifiedBefore.IsZero() && !m.modifiedAfter.Before(m.modifiedBefore),
+		"WithModifiedAfter must be before WithModifiedBefore")
+	check(m.glacierPolicy.restore && m.glacierPolicy.tier != s3.TierStandard &&
+		m.glacierPolicy.tier != s3.TierBulk && m.glacierPolicy.tier != s3.TierExpedited, "unknown restore tier")
+	check(m.glacierPolicy.restore && m.glacierPolicy.days <= 0, "GlacierRestoreAndWait requires positive days")
+	check(m.verifyMode != 0 && (m.compression != nil || m.encryption != nil),
+		"WithVerify can't be used with WithCompression or the client-side encryption")
+
+	if len(msgs) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidOption, strings.Join(msgs, "; "))
+	}
+	return nil
+}
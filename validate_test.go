@@ -0,0 +1,117 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestNewWithOptionsValidated(t *testing.T) {
+	sess := session.New(&aws.Config{
+		Region: aws.String("dummy"),
+	})
+	anonymous := session.New(&aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Region:      aws.String("dummy"),
+	})
+	key := []byte("01234567890123456789012345678901")
+
+	testCases := map[string]struct {
+		sess  *session.Session
+		opts  []Option
+		valid bool
+	}{
+		"Default":         {sess, nil, true},
+		"SSEKMS":          {sess, []Option{WithKMSKeyID("key")}, true},
+		"SSEC":            {sess, []Option{WithSSECustomerKey(key)}, true},
+		"ZeroParallel":    {sess, []Option{WithParallel(0)}, false},
+		"ReadOnlyDelete":  {sess, []Option{WithReadOnly(), WithDelete()}, false},
+		"ShortSSECKey":    {sess, []Option{WithSSECustomerKey([]byte("short"))}, false},
+		"SSECAndSSE":      {sess, []Option{WithSSECustomerKey(key), WithSSE(s3.ServerSideEncryptionAes256)}, false},
+		"UnknownSSE":      {sess, []Option{WithSSE("unknown")}, false},
+		"KMSKeyWithSSES3": {sess, []Option{WithSSE(s3.ServerSideEncryptionAes256), WithKMSKeyID("key")}, false},
+		"AnonymousSSEC":   {anonymous, []Option{WithChecksum(), WithSSECustomerKey(key)}, false},
+		"HMACNoManifest":  {sess, []Option{WithManifestHMACKey([]byte("key"))}, false},
+		"HMACManifest":    {sess, []Option{WithManifest(""), WithManifestHMACKey([]byte("key"))}, true},
+		"KeyRange":        {sess, []Option{WithKeyRange("a", "b")}, true},
+		"SmallPartSize":   {sess, []Option{WithUploadPartSize(1024)}, false},
+		"ReversedRange":   {sess, []Option{WithKeyRange("b", "a")}, false},
+		"ListingCache":    {sess, []Option{WithListingCache("cache.json", time.Hour)}, true},
+		"ListingCacheTTL": {sess, []Option{WithListingCache("cache.json", 0)}, false},
+		"FailFast":        {sess, []Option{WithErrorPolicy(FailFast)}, true},
+		"NegativeMaxErrs": {sess, []Option{WithErrorPolicy(MaxErrors(-1))}, false},
+		"Retries":         {sess, []Option{WithRetries(3, nil)}, true},
+		"NegativeRetries": {sess, []Option{WithRetries(-1, nil)}, false},
+		"ChecksumPolicy":  {sess, []Option{WithChecksumPolicy(ChecksumSHA256Only)}, true},
+		"UnknownChecksum": {sess, []Option{WithChecksumPolicy(ChecksumPolicy(5))}, false},
+		"LargeFileLane":   {sess, []Option{WithParallel(4), WithLargeFileLane(1<<30, 1)}, true},
+		"AllLargeFiles":   {sess, []Option{WithParallel(4), WithLargeFileLane(1<<30, 4)}, false},
+		"DeleteAfter":     {sess, []Option{WithDelete(), WithDeleteOrdering(DeleteAfter)}, true},
+		"UnknownDelete":   {sess, []Option{WithDeleteOrdering(DeleteOrdering(5))}, false},
+		"BackupPrefix":    {sess, []Option{WithBackupPrefix("s3://bucket/backup/")}, true},
+		"BackupNoBucket":  {sess, []Option{WithBackupPrefix("s3:///backup/")}, false},
+		"ReadAhead":       {sess, []Option{WithReadAhead(8, 1<<20)}, true},
+		"NegativeAhead":   {sess, []Option{WithReadAhead(-1, 1<<20)}, false},
+		"Endpoint":        {sess, []Option{WithEndpoint("http://localhost:9000", true)}, true},
+		"NoEndpointHost":  {sess, []Option{WithEndpoint("localhost:9000", true)}, false},
+		"Compression":     {sess, []Option{WithCompression(Gzip, 1024, ".log")}, true},
+		"NoCodec":         {sess, []Option{WithCompression(nil, 0)}, false},
+		"NegativeMinSize": {sess, []Option{WithCompression(Gzip, -1)}, false},
+		"CSEKey":          {sess, []Option{WithClientSideEncryptionKey(make([]byte, 32))}, true},
+		"CSEShortKey":     {sess, []Option{WithClientSideEncryptionKey(make([]byte, 16))}, false},
+		"CSECompression":  {sess, []Option{WithClientSideEncryptionKey(make([]byte, 32)), WithCompression(Gzip, 0)}, false},
+		"LocalTrash":      {sess, []Option{WithLocalTrash("trash", time.Hour)}, true},
+		"TrashAndBackup":  {sess, []Option{WithLocalTrash("trash", 0), WithBackupPrefix("backup")}, false},
+		"NegativeTrash":   {sess, []Option{WithLocalTrash("trash", -1)}, false},
+		"Verify":          {sess, []Option{WithVerify(VerifyETag | VerifySHA256)}, true},
+		"UnknownVerify":   {sess, []Option{WithVerify(VerifySHA256 << 1)}, false},
+		"VerifyCompress":  {sess, []Option{WithVerify(VerifyETag), WithCompression(Gzip, 0)}, false},
+		"HashWorkers":     {sess, []Option{WithHashWorkers(4)}, true},
+		"NegativeHash":    {sess, []Option{WithHashWorkers(-1)}, false},
+		"RuntimeStats":    {sess, []Option{WithRuntimeStats(time.Minute)}, true},
+		"NegativeRuntime": {sess, []Option{WithRuntimeStats(-1)}, false},
+		"GlacierRestore":  {sess, []Option{WithGlacierPolicy(GlacierRestoreAndWait(s3.TierBulk, 1))}, true},
+		"GlacierTier":     {sess, []Option{WithGlacierPolicy(GlacierRestoreAndWait("Slow", 1))}, false},
+		"GlacierDays":     {sess, []Option{WithGlacierPolicy(GlacierRestoreAndWait(s3.TierBulk, 0))}, false},
+		"MaxDepth":        {sess, []Option{WithMaxDepth(1)}, true},
+		"NegativeDepth":   {sess, []Option{WithMaxDepth(-1)}, false},
+		"WarmStart":       {sess, []Option{WithWarmStart("warm.json", time.Hour)}, true},
+		"NegativeWarm":    {sess, []Option{WithWarmStart("warm.json", -1)}, false},
+		"SizeRange":       {sess, []Option{WithMinSize(1), WithMaxSize(10)}, true},
+		"InvertedSize":    {sess, []Option{WithMinSize(10), WithMaxSize(1)}, false},
+		"ModifiedRange":   {sess, []Option{WithModifiedAfter(time.Unix(1, 0)), WithModifiedBefore(time.Unix(0, 0))}, false},
+	}
+	for name, tt := range testCases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m, err := NewWithOptionsValidated(tt.sess, tt.opts...)
+			if tt.valid {
+				if err != nil || m == nil {
+					t.Errorf("Expected valid, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("Expected %v, got %v", ErrInvalidOption, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// VerifyMode is the set of the checksums verified by WithVerify after the transfers.
+type VerifyMode int
+
+const (
+	// VerifyETag verifies the MD5 based ETag of the object.
+	// The objects encrypted by SSE-KMS or SSE-C are not verified since their ETags
+	// are not the MD5 checksums.
+	VerifyETag VerifyMode = 1 << iota
+	// VerifySHA256 verifies the SHA-256 checksum of the object.
+	// The objects are uploaded with the SHA-256 checksum to be verified.
+	VerifySHA256
+)
+
+// ErrVerification is returned if the transferred file doesn't match the checksum of the object.
+var ErrVerification = errors.New("checksum mismatch after the transfer")
+
+// verifyObject reads the transferred local file again and compares its checksums
+// with the ones of the object. The checksums which can't be compared, e.g. the object
+// uploaded by the other tools with a different part size, are skipped.
+// partSize is the part size of the object if it is uploaded by multipart upload.
+func (m *Manager) verifyObject(ctx context.Context, client s3iface.S3API, bucket, key string, file *FileInfo, partSize int64, mode VerifyMode) error {
+	head, err := client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		ChecksumMode:         aws.String(s3.ChecksumModeEnabled),
+		SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+		SSECustomerKey:       m.sseCustomerKey,
+	})
+	if err != nil {
+		return err
+	}
+	if mode&VerifyETag != 0 {
+		switch sse := aws.StringValue(head.ServerSideEncryption); {
+		case head.SSECustomerAlgorithm != nil || strings.HasPrefix(sse, s3.ServerSideEncryptionAwsKms):
+			println("Skipping the ETag verification of", file.Name, "encrypted by", sse)
+		default:
+			same, err := matchETag(file, aws.StringValue(head.ETag), partSize)
+			switch {
+			case err == errPartsMismatch:
+				println("Skipping the ETag verification of", file.Name+":", err.Error())
+			case err != nil:
+				return err
+			case !same:
+				return fmt.Errorf("%w: ETag %s", ErrVerification, aws.StringValue(head.ETag))
+			}
+		}
+	}
+	if mode&VerifySHA256 != 0 {
+		sum := aws.StringValue(head.ChecksumSHA256)
+		if sum == "" {
+			println("Skipping the SHA-256 verification of", file.Name, "without the checksum")
+			return nil
+		}
+		same, err := matchChecksum(file, s3.ChecksumAlgorithmSha256, sum, partSize)
+		switch {
+		case err == errPartsMismatch:
+			println("Skipping the SHA-256 verification of", file.Name+":", err.Error())
+		case err != nil:
+			return err
+		case !same:
+			return fmt.Errorf("%w: SHA-256 %s", ErrVerification, sum)
+		}
+	}
+	return nil
+}
+
+// verifyUpload verifies the uploaded object, and deletes the object if it doesn't match
+// so that the file is uploaded again by the next sync.
+func (m *Manager) verifyUpload(ctx context.Context, file *fileInfo, destFile *s3Path) error {
+	if m.encryption != nil || m.compressible(file.name, file.size) {
+		// The object is the compressed or encrypted contents of the file.
+		println("Skipping the verification of", file.name, "transformed on upload")
+		return nil
+	}
+	client := m.destClient(ctx, destFile.bucket)
+	err := m.verifyObject(ctx, client, destFile.bucket, destFile.bucketPrefix, file.export(), m.uploadedPartSize(file.size), m.verifyMode)
+	if errors.Is(err, ErrVerification) {
+		if _, derr := client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(destFile.bucket),
+			Key:    aws.String(destFile.bucketPrefix),
+		}); derr != nil {
+			println("Failed to delete the mismatched object", destFile.String()+":", derr.Error())
+		}
+	}
+	return err
+}
+
+// verifyDownload verifies the downloaded file, and removes the file if it doesn't match
+// so that the object is downloaded again by the next sync.
+// The part size of the object is assumed to be the same as the uploads of the Manager.
+func (m *Manager) verifyDownload(ctx context.Context, file *fileInfo, bucket, key, filename string, mode VerifyMode) error {
+	if m.encryption != nil || (m.compression != nil && m.compression.matches(file.name)) {
+		// The file is the decompressed or decrypted contents of the object.
+		println("Skipping the verification of", file.name, "transformed on download")
+		return nil
+	}
+	client := m.regionalClient(ctx, m.sourceClient(), bucket)
+	local := &FileInfo{
+		Name: file.name,
+		Path: filename,
+		Size: file.size,
+		open: func() (io.ReadCloser, error) {
+			return os.Open(filename)
+		},
+	}
+	err := m.verifyObject(ctx, client, bucket, key, local, m.uploadedPartSize(file.size), mode)
+	if errors.Is(err, ErrVerification) {
+		if rerr := os.Remove(filename); rerr != nil {
+			println("Failed to remove the mismatched file", filename+":", rerr.Error())
+		}
+	}
+	return err
+}
+
+// downloadVerifyMode returns the checksums verified after the downloads.
+// The SHA-256 checksums are also verified with ChecksumSHA256Only unless the contents
+// of the objects are transformed by WithCompression or the client-side encryption.
+func (m *Manager) downloadVerifyMode() VerifyMode {
+	mode := m.verifyMode
+	if m.checksumPolicy == ChecksumSHA256Only && m.compression == nil && m.encryption == nil {
+		mode |= VerifySHA256
+	}
+	return mode
+}
+
+// uploadedPartSize returns the part size of the file uploaded by the uploader,
+// which is increased for the large file not to exceed the maximum number of parts.
+func (m *Manager) uploadedPartSize(size int64) int64 {
+	u := m.getUploader()
+	partSize, maxParts := u.PartSize, int64(u.MaxUploadParts)
+	if size/partSize >= maxParts {
+		partSize = size/maxParts + 1
+	}
+	return partSize
+}
+
+// matchChecksum calculates the checksum of the contents of the file in the format
+// of the given checksum and returns whether they match.
+// The checksum of the multipart object is the checksum of the checksums of the parts.
+func matchChecksum(f *FileInfo, algorithm, sum string, partSize int64) (bool, error) {
+	if !strings.Contains(sum, "-") {
+		calc, err := f.calcChecksum(algorithm)
+		if err != nil {
+			return false, err
+		}
+		return calc == sum, nil
+	}
+	parts, err := strconv.ParseInt(sum[strings.LastIndex(sum, "-")+1:], 10, 64)
+	if err != nil {
+		return false, err
+	}
+	if partSize <= 0 || (f.Size+partSize-1)/partSize != parts {
+		return false, errPartsMismatch
+	}
+	calc, err := f.calcPartChecksum(algorithm, partSize)
+	if err != nil {
+		return false, err
+	}
+	return calc == sum, nil
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+type dummyVerifyS3 struct {
+	dummyBudgetS3
+	head    *s3.HeadObjectOutput
+	deleted []string
+}
+
+func (s *dummyVerifyS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	if aws.StringValue(in.ChecksumMode) != s3.ChecksumModeEnabled {
+		return nil, errors.New("checksum mode must be enabled")
+	}
+	return s.head, nil
+}
+
+func (s *dummyVerifyS3) DeleteObjectWithContext(ctx aws.Context, in *s3.DeleteObjectInput, opts ...request.Option) (*s3.DeleteObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, aws.StringValue(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func md5ETag(s string) string {
+	sum := md5.Sum([]byte(s))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func sha256Checksum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestVerify_Download(t *testing.T) {
+	// Contents of the objects of dummyBudgetS3 is "a".
+	testCases := map[string]struct {
+		mode VerifyMode
+		head *s3.HeadObjectOutput
+		err  bool
+	}{
+		"ETag":         {VerifyETag, &s3.HeadObjectOutput{ETag: aws.String(md5ETag("a"))}, false},
+		"ETagMismatch": {VerifyETag, &s3.HeadObjectOutput{ETag: aws.String(md5ETag("b"))}, true},
+		"SSEKMS": {VerifyETag, &s3.HeadObjectOutput{
+			ETag:                 aws.String(md5ETag("b")),
+			ServerSideEncryption: aws.String(s3.ServerSideEncryptionAwsKms),
+		}, false},
+		"SHA256":         {VerifySHA256, &s3.HeadObjectOutput{ChecksumSHA256: aws.String(sha256Checksum("a"))}, false},
+		"SHA256Mismatch": {VerifySHA256, &s3.HeadObjectOutput{ChecksumSHA256: aws.String(sha256Checksum("b"))}, true},
+		"NoChecksum":     {VerifySHA256, &s3.HeadObjectOutput{}, false},
+		"Both": {VerifyETag | VerifySHA256, &s3.HeadObjectOutput{
+			ETag:           aws.String(md5ETag("a")),
+			ChecksumSHA256: aws.String(sha256Checksum("b")),
+		}, true},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			temp, err := ioutil.TempDir("", "s3synctest")
+			if err != nil {
+				t.Fatal("Failed to create temp dir")
+			}
+			defer os.RemoveAll(temp)
+
+			s := &dummyVerifyS3{
+				dummyBudgetS3: dummyBudgetS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a"}}},
+				head:          tc.head,
+			}
+			m := New(session.New(), WithVerify(tc.mode))
+			m.s3 = s
+			err = m.Sync(context.Background(), "s3://bucket/prefix", temp)
+			if tc.err != errors.Is(err, ErrVerification) {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+			_, err = os.Stat(filepath.Join(temp, "a"))
+			if tc.err != os.IsNotExist(err) {
+				t.Errorf("Expected the mismatched file to be removed: %v, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestVerify_Upload(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	filename := filepath.Join(temp, "file")
+	if err := ioutil.WriteFile(filename, []byte("contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		etag string
+		err  bool
+	}{
+		"Match":    {md5ETag("contents"), false},
+		"Mismatch": {md5ETag("corrupted"), true},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			s := &dummyVerifyS3{head: &s3.HeadObjectOutput{ETag: aws.String(tc.etag)}}
+			m := New(session.New(), WithVerify(VerifyETag))
+			m.s3 = s
+			file := &fileInfo{name: "file", path: filename, size: 8, local: true}
+			err := m.verifyUpload(context.Background(), file, &s3Path{bucket: "bucket", bucketPrefix: "prefix/file"})
+			if tc.err != errors.Is(err, ErrVerification) {
+				t.Fatalf("Expected error: %v, got: %v", tc.err, err)
+			}
+			if deleted := len(s.deleted) > 0; deleted != tc.err {
+				t.Errorf("Expected the mismatched object to be deleted: %v, got: %v", tc.err, s.deleted)
+			}
+		})
+	}
+}
+
+type dummyVerifyCompressionS3 struct {
+	dummyCompressionS3
+	head *s3.HeadObjectOutput
+}
+
+func (s *dummyVerifyCompressionS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	return s.head, nil
+}
+
+func TestVerify_Transformed(t *testing.T) {
+	t.Run("Download", func(t *testing.T) {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+
+		// The ETag matches the stored gzip contents, not the decompressed file.
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		w.Write([]byte(compressedContents))
+		w.Close()
+		s := &dummyVerifyCompressionS3{
+			dummyCompressionS3: dummyCompressionS3{dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a.log"}}},
+			head:               &s3.HeadObjectOutput{ETag: aws.String(md5ETag(buf.String()))},
+		}
+		m := New(session.New(), WithCompression(Gzip, 0, ".log"), WithVerify(VerifyETag))
+		m.s3 = s
+		if err := m.Sync(context.Background(), "s3://bucket/prefix", temp); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(filepath.Join(temp, "a.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != compressedContents {
+			t.Errorf("Expected decompressed contents, got %q", b)
+		}
+	})
+	t.Run("Upload", func(t *testing.T) {
+		temp, err := ioutil.TempDir("", "s3synctest")
+		if err != nil {
+			t.Fatal("Failed to create temp dir")
+		}
+		defer os.RemoveAll(temp)
+		filename := filepath.Join(temp, "file.log")
+		if err := ioutil.WriteFile(filename, []byte("contents"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for name, opt := range map[string]Option{
+			"Compression": WithCompression(Gzip, 0, ".log"),
+			"Encryption":  WithClientSideEncryptionKey(make([]byte, 32)),
+		} {
+			opt := opt
+			t.Run(name, func(t *testing.T) {
+				// The ETag of the stored contents doesn't match the local file.
+				s := &dummyVerifyS3{head: &s3.HeadObjectOutput{ETag: aws.String(md5ETag("stored"))}}
+				m := New(session.New(), opt, WithVerify(VerifyETag))
+				m.s3 = s
+				file := &fileInfo{name: "file.log", path: filename, size: 8, local: true}
+				if err := m.verifyUpload(context.Background(), file, &s3Path{bucket: "bucket", bucketPrefix: "prefix/file.log"}); err != nil {
+					t.Fatal(err)
+				}
+				if len(s.deleted) > 0 {
+					t.Errorf("Expected the object not to be deleted, got %v", s.deleted)
+				}
+			})
+		}
+	})
+}
+
+func TestMatchChecksum(t *testing.T) {
+	contents := "abcde"
+	var sums []byte
+	for _, part := range []string{"ab", "cd", "e"} {
+		sum := sha256.Sum256([]byte(part))
+		sums = append(sums, sum[:]...)
+	}
+	composite := sha256.Sum256(sums)
+	multipart := base64.StdEncoding.EncodeToString(composite[:]) + "-3"
+
+	testCases := map[string]struct {
+		sum      string
+		partSize int64
+		same     bool
+		err      error
+	}{
+		"Whole":         {sha256Checksum(contents), 0, true, nil},
+		"WholeMismatch": {sha256Checksum("abcdf"), 0, false, nil},
+		"Multipart":     {multipart, 2, true, nil},
+		"PartsMismatch": {multipart, 3, false, errPartsMismatch},
+		"Modified":      {strings.Replace(multipart, "-3", "-2", 1), 3, false, nil},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			f := &FileInfo{Size: int64(len(contents)), open: func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(contents)), nil
+			}}
+			same, err := matchChecksum(f, s3.ChecksumAlgorithmSha256, tc.sum, tc.partSize)
+			if err != tc.err {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if same != tc.same {
+				t.Errorf("Expected same: %v, got: %v", tc.same, same)
+			}
+		})
+	}
+}
+
+func TestUploadedPartSize(t *testing.T) {
+	m := New(session.New(), WithUploadPartSize(10*1024*1024))
+	testCases := map[string]struct {
+		size     int64
+		expected int64
+	}{
+		"Small": {1, 10 * 1024 * 1024},
+		"Max":   {10 * 1024 * 1024 * (s3manager.MaxUploadParts - 1), 10 * 1024 * 1024},
+		"Large": {10 * 1024 * 1024 * s3manager.MaxUploadParts, 10*1024*1024 + 1},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if actual := m.uploadedPartSize(tc.size); actual != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
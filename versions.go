@@ -0,0 +1,100 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// metadataSourceVersion is the metadata key to store the version ID of the source object
+// copied by WithVersionTracking.
+const metadataSourceVersion = "source-version-id"
+
+// objectHead returns the function to get the object by HeadObject.
+// The object is requested only once when the comparator needs it.
+func (m *Manager) objectHead(ctx context.Context, path *s3Path, key string) func() (*s3.HeadObjectOutput, error) {
+	var once sync.Once
+	var head *s3.HeadObjectOutput
+	var err error
+	return func() (*s3.HeadObjectOutput, error) {
+		once.Do(func() {
+			head, err = m.client(ctx, path).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+				Bucket:               aws.String(path.bucket),
+				Key:                  aws.String(key),
+				SSECustomerAlgorithm: m.sseCustomerAlgorithm,
+				SSECustomerKey:       m.sseCustomerKey,
+			})
+		})
+		return head, err
+	}
+}
+
+// objectVersion returns the version ID of the object, or empty if the bucket is not versioned.
+func objectVersion(head *s3.HeadObjectOutput) string {
+	if v := aws.StringValue(head.VersionId); v != "null" {
+		return v
+	}
+	return ""
+}
+
+// sameVersion returns whether the destination object is copied from the current
+// version of the source object. ok is false if the versions can't be compared,
+// e.g. the source bucket is not versioned or the destination object is not
+// copied with WithVersionTracking.
+func sameVersion(src, dst *FileInfo) (same, ok bool) {
+	if src.head == nil || dst.head == nil {
+		return false, false
+	}
+	srcHead, err := src.head()
+	if err != nil {
+		return false, false
+	}
+	version := objectVersion(srcHead)
+	if version == "" {
+		return false, false
+	}
+	dstHead, err := dst.head()
+	if err != nil {
+		return false, false
+	}
+	copied, found := metadataValue(dstHead.Metadata, metadataSourceVersion)
+	if !found {
+		return false, false
+	}
+	return copied == version, true
+}
+
+// trackVersion pins the copy to the version of the source object read by HeadObject,
+// and records the version ID in the metadata of the destination object.
+// The version ID recorded by the previous copy is removed if the source is not versioned.
+func trackVersion(in *s3.CopyObjectInput, head *s3.HeadObjectOutput) {
+	for k := range in.Metadata {
+		if strings.EqualFold(k, metadataSourceVersion) {
+			delete(in.Metadata, k)
+		}
+	}
+	version := objectVersion(head)
+	if version == "" {
+		return
+	}
+	in.Metadata[metadataSourceVersion] = aws.String(version)
+	// Copy the version read by HeadObject even if a newer version is put meanwhile.
+	in.CopySource = aws.String(aws.StringValue(in.CopySource) + "?versionId=" + url.QueryEscape(version))
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type dummyVersionS3 struct {
+	dummyKeyRangeS3
+	mu       sync.Mutex
+	versions map[string]string
+	metadata map[string]map[string]*string
+	copied   []string
+}
+
+func (s *dummyVersionS3) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := aws.StringValue(in.Key)
+	out := &s3.HeadObjectOutput{Metadata: make(map[string]*string)}
+	if v, ok := s.versions[key]; ok {
+		out.VersionId = aws.String(v)
+	}
+	// Metadata keys are canonicalized by the SDK.
+	for k, v := range s.metadata[key] {
+		out.Metadata[http.CanonicalHeaderKey(k)] = v
+	}
+	return out, nil
+}
+
+func (s *dummyVersionS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.copied = append(s.copied, aws.StringValue(in.CopySource))
+	if s.metadata == nil {
+		s.metadata = make(map[string]map[string]*string)
+	}
+	s.metadata[aws.StringValue(in.Key)] = in.Metadata
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func TestVersionTracking(t *testing.T) {
+	src := &dummyVersionS3{
+		dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}},
+		versions:        map[string]string{"prefix/a": "v1", "prefix/b": "null"},
+	}
+	dst := &dummyVersionS3{
+		dummyKeyRangeS3: dummyKeyRangeS3{keys: []string{"prefix/a", "prefix/b"}},
+		metadata: map[string]map[string]*string{
+			"prefix/a": {metadataSourceVersion: aws.String("v0")},
+			"prefix/b": {metadataSourceVersion: aws.String("v0")},
+		},
+	}
+	run := func(t *testing.T) []string {
+		dst.copied = nil
+		// Sizes and timestamps of the dummy objects are all the same.
+		m := NewWithClients(src, dst, WithVersionTracking(), WithParallel(1))
+		if err := m.Sync(context.Background(), "s3://src-bucket/prefix", "s3://dst-bucket/prefix"); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(dst.copied)
+		return dst.copied
+	}
+
+	t.Run("OtherVersion", func(t *testing.T) {
+		// The unversioned source is compared by the comparator.
+		copied := run(t)
+		if len(copied) != 1 || copied[0] != "src-bucket/prefix/a?versionId=v1" {
+			t.Fatalf("Expected the version to be copied, got %v", copied)
+		}
+		if v, _ := metadataValue(dst.metadata["prefix/a"], metadataSourceVersion); v != "v1" {
+			t.Errorf("Expected the source version to be recorded, got %q", v)
+		}
+	})
+	t.Run("SameVersion", func(t *testing.T) {
+		if copied := run(t); len(copied) != 0 {
+			t.Errorf("Expected the replicated version to be skipped, got %v", copied)
+		}
+	})
+	t.Run("NewVersion", func(t *testing.T) {
+		src.versions["prefix/a"] = "v2"
+		if copied := run(t); len(copied) != 1 || copied[0] != "src-bucket/prefix/a?versionId=v2" {
+			t.Errorf("Expected the new version to be copied, got %v", copied)
+		}
+	})
+}
+
+func TestTrackVersion(t *testing.T) {
+	testCases := map[string]struct {
+		version    *string
+		copySource string
+		recorded   string
+	}{
+		"Versioned":   {aws.String("a+b"), "bucket/key?versionId=a%2Bb", "a+b"},
+		"Unversioned": {aws.String("null"), "bucket/key", ""},
+		"NoVersion":   {nil, "bucket/key", ""},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			in := &s3.CopyObjectInput{
+				CopySource: aws.String("bucket/key"),
+				Metadata:   map[string]*string{"Source-Version-Id": aws.String("stale"), "Other": aws.String("kept")},
+			}
+			trackVersion(in, &s3.HeadObjectOutput{VersionId: tc.version})
+			if aws.StringValue(in.CopySource) != tc.copySource {
+				t.Errorf("Expected CopySource %s, got %s", tc.copySource, aws.StringValue(in.CopySource))
+			}
+			if v, ok := metadataValue(in.Metadata, metadataSourceVersion); v != tc.recorded || ok != (tc.recorded != "") {
+				t.Errorf("Expected the recorded version %q, got %q", tc.recorded, v)
+			}
+			if aws.StringValue(in.Metadata["Other"]) != "kept" {
+				t.Error("Expected the other metadata to be kept")
+			}
+		})
+	}
+}
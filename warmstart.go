@@ -0,0 +1,218 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// warmStartFile is the content of the warm start file, the destination listing
+// of the previous sync which didn't change the destination.
+type warmStartFile struct {
+	Created time.Time `json:"created"`
+	// Listing is the S3 path and the patterns of the listing.
+	Listing string `json:"listing"`
+	// MaxLastModified is the highest LastModified of the objects.
+	MaxLastModified time.Time      `json:"maxLastModified"`
+	Objects         []cachedObject `json:"objects"`
+}
+
+type warmStartKey struct{}
+
+// warmStartRun is the warm start used by a sync call.
+type warmStartRun struct {
+	mu       sync.Mutex
+	prev     *warmStartFile
+	listing  string
+	objects  []cachedObject
+	complete bool
+}
+
+// useWarmStart returns the context listing the destination by the previous listing
+// if the warm start is enabled, and the function to be called with the error of the sync
+// when the sync finishes.
+// The destination listing is written to the warm start file if the sync didn't change
+// the destination, or the file is removed since the listing is outdated.
+func (m *Manager) useWarmStart(ctx context.Context) (context.Context, func(*error)) {
+	if m.warmStartPath == "" {
+		return ctx, func(*error) {}
+	}
+	run := &warmStartRun{}
+	b, err := ioutil.ReadFile(m.warmStartPath)
+	if err == nil {
+		var prev warmStartFile
+		if err = json.Unmarshal(b, &prev); err != nil {
+			println("Ignoring the warm start file", err.Error())
+		} else if m.warmStartMaxAge > 0 && time.Since(prev.Created) > m.warmStartMaxAge {
+			println("Ignoring the warm start file expired", prev.Created.String())
+		} else {
+			run.prev = &prev
+		}
+	}
+
+	stats, _ := ctx.Value(callStatisticsKey{}).(*callStatistics)
+	before := stats.get()
+	return context.WithValue(ctx, warmStartKey{}, run), func(err *error) {
+		after := stats.get()
+		run.mu.Lock()
+		defer run.mu.Unlock()
+		if *err == nil && run.complete && after.Files == before.Files &&
+			after.DeletedFiles == before.DeletedFiles && after.FailedFiles == before.FailedFiles {
+			if werr := m.writeWarmStart(run); werr != nil {
+				println("Failed to write the warm start file", werr.Error())
+			}
+			return
+		}
+		if rerr := os.Remove(m.warmStartPath); rerr != nil && !os.IsNotExist(rerr) {
+			println("Failed to remove the warm start file", rerr.Error())
+		}
+	}
+}
+
+// writeWarmStart replaces the warm start file by the destination listing of the sync.
+func (m *Manager) writeWarmStart(run *warmStartRun) error {
+	f := &warmStartFile{
+		Created: time.Now().UTC(),
+		Listing: run.listing,
+		Objects: run.objects,
+	}
+	for _, o := range run.objects {
+		if o.LastModified.After(f.MaxLastModified) {
+			f.MaxLastModified = o.LastModified
+		}
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return writeStateFile(m.warmStartPath, b)
+}
+
+// warmStartRun returns the warm start of the destination listing, or nil if not used.
+// The listings limited by the key range or the depth are not warm started.
+func (m *Manager) warmStartRun(ctx context.Context, path *s3Path) *warmStartRun {
+	run, ok := ctx.Value(warmStartKey{}).(*warmStartRun)
+	if !ok || path.source || m.startAfter(path) != nil || m.maxDepth > 0 {
+		return nil
+	}
+	return run
+}
+
+// warmListing returns the destination objects listed by the previous listing
+// validated by the first page of the listing, and the objects added after the last key
+// of the previous listing. It returns false if the previous listing is invalid.
+// S3 can't list the objects modified since the time, so the previous listing is valid
+// if the first page is the same as the previous one, and no object in the first page
+// is modified after the previous listing.
+// The objects are not listed again on success, which assumes that the destination
+// is changed only by the sync or the new keys sorted after the existing ones.
+func (m *Manager) warmListing(ctx context.Context, path *s3Path, patterns []*regexp.Regexp) ([]*s3.Object, bool, error) {
+	run := m.warmStartRun(ctx, path)
+	if run == nil {
+		return nil, false, nil
+	}
+	listing := listingCacheEntry(path, patterns)
+	run.mu.Lock()
+	prev := run.prev
+	run.prev = nil
+	run.listing = listing
+	run.mu.Unlock()
+	if prev == nil || prev.Listing != listing || len(prev.Objects) == 0 {
+		return nil, false, nil
+	}
+
+	first, err := m.listPage(ctx, path, nil, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(first.Contents) > len(prev.Objects) {
+		println("Listing", path.String(), "since the warm start file is outdated")
+		return nil, false, nil
+	}
+	for i, o := range first.Contents {
+		if !newCachedObject(o).equal(prev.Objects[i]) || aws.TimeValue(o.LastModified).After(prev.MaxLastModified) {
+			println("Listing", path.String(), "since the warm start file is outdated")
+			return nil, false, nil
+		}
+	}
+	if !aws.BoolValue(first.IsTruncated) {
+		return first.Contents, true, nil
+	}
+
+	objects := append([]*s3.Object(nil), first.Contents...)
+	for _, o := range prev.Objects[len(first.Contents):] {
+		objects = append(objects, o.object())
+	}
+	// Objects added after the last key.
+	startAfter := aws.String(prev.Objects[len(prev.Objects)-1].Key)
+	var token *string
+	for {
+		list, err := m.listPage(ctx, path, startAfter, token)
+		if err != nil {
+			return nil, false, err
+		}
+		objects = append(objects, list.Contents...)
+		if token = list.NextContinuationToken; token == nil {
+			break
+		}
+		startAfter = nil
+	}
+	return objects, true, nil
+}
+
+// listPage lists a page of the objects under the path.
+func (m *Manager) listPage(ctx context.Context, path *s3Path, startAfter, token *string) (*s3.ListObjectsV2Output, error) {
+	ctx, cancel := withTimeout(ctx, m.listTimeout)
+	defer cancel()
+	list, err := m.client(ctx, path).ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:            &path.bucket,
+		Prefix:            &path.bucketPrefix,
+		ContinuationToken: token,
+		StartAfter:        startAfter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.updateStatistics(ctx, func(s *SyncStatistics) {
+		s.ListedObjects += int64(len(list.Contents))
+	})
+	return list, nil
+}
+
+// recordWarmStart records the page of the destination listing to be written to the warm start file.
+// The listing is recorded only if it's completed.
+func (m *Manager) recordWarmStart(ctx context.Context, path *s3Path, objects []*s3.Object, first, last bool) {
+	run := m.warmStartRun(ctx, path)
+	if run == nil {
+		return
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if first {
+		run.objects = nil
+	}
+	for _, o := range objects {
+		run.objects = append(run.objects, newCachedObject(o))
+	}
+	run.complete = last
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+type dummyWarmS3 struct {
+	s3iface.S3API
+	mu      sync.Mutex
+	buckets map[string][]*s3.Object
+	// calls is the number of the listings of each bucket.
+	calls map[string]int
+}
+
+func (s *dummyWarmS3) ListObjectsV2WithContext(ctx aws.Context, in *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[*in.Bucket]++
+	objects := s.buckets[*in.Bucket]
+	sort.Slice(objects, func(i, j int) bool { return *objects[i].Key < *objects[j].Key })
+	out := &s3.ListObjectsV2Output{IsTruncated: aws.Bool(false)}
+	start := 0
+	if in.ContinuationToken != nil {
+		start = len(*in.ContinuationToken)
+	}
+	for i := start; i < len(objects); i++ {
+		if *objects[i].Key <= aws.StringValue(in.StartAfter) || !strings.HasPrefix(*objects[i].Key, *in.Prefix) {
+			continue
+		}
+		if len(out.Contents) == 2 {
+			// Each page contains two objects and the token encodes the offset.
+			out.NextContinuationToken = aws.String(string(make([]byte, i)))
+			out.IsTruncated = aws.Bool(true)
+			break
+		}
+		o := *objects[i]
+		out.Contents = append(out.Contents, &o)
+	}
+	return out, nil
+}
+
+func (s *dummyWarmS3) CopyObjectWithContext(ctx aws.Context, in *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.buckets["dest"] {
+		if *o.Key == *in.Key {
+			o.LastModified = aws.Time(time.Now())
+		}
+	}
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (s *dummyWarmS3) put(key string, size int64, lastModified time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, bucket := range []string{"source", "dest"} {
+		s.buckets[bucket] = append(s.buckets[bucket], &s3.Object{
+			Key:          aws.String(key),
+			Size:         aws.Int64(size),
+			LastModified: aws.Time(lastModified),
+			ETag:         aws.String(`"` + key + `"`),
+		})
+	}
+}
+
+func TestWarmStart(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+	warmFile := filepath.Join(temp, "warm.json")
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &dummyWarmS3{buckets: map[string][]*s3.Object{}}
+	for _, key := range []string{"prefix/a", "prefix/b", "prefix/c", "prefix/d", "prefix/e"} {
+		s.put(key, 1, base)
+	}
+	m := New(session.New(), WithWarmStart(warmFile, time.Hour))
+	m.s3 = s
+
+	sync := func() int {
+		s.calls = map[string]int{}
+		if err := m.Sync(context.Background(), "s3://source/prefix", "s3://dest/prefix"); err != nil {
+			t.Fatal(err)
+		}
+		return s.calls["dest"]
+	}
+	if n := sync(); n != 3 {
+		t.Errorf("First sync must list all pages, got %d", n)
+	}
+	if _, err := os.Stat(warmFile); err != nil {
+		t.Fatalf("Warm start file must be written: %v", err)
+	}
+	if n := sync(); n != 2 {
+		t.Errorf("Warm started sync must list the first page and the keys after the last one, got %d", n)
+	}
+
+	// The new key after the last one is listed by the warm start.
+	s.put("prefix/f", 1, base.Add(time.Minute))
+	if n := sync(); n != 2 {
+		t.Errorf("Warm started sync must list the first page and the keys after the last one, got %d", n)
+	}
+	if n := sync(); n != 2 {
+		t.Errorf("The added key must be recorded, got %d listings", n)
+	}
+
+	// The modification in the first page falls back to the full listing.
+	s.mu.Lock()
+	s.buckets["dest"][0].LastModified = aws.Time(base.Add(time.Hour))
+	s.mu.Unlock()
+	if n := sync(); n != 4 {
+		t.Errorf("Outdated warm start file must be ignored, got %d listings", n)
+	}
+	if n := sync(); n != 2 {
+		t.Errorf("Warm start file must be rewritten, got %d listings", n)
+	}
+
+	// The changes made by the sync invalidates the warm start file.
+	s.mu.Lock()
+	s.buckets["source"][1].Size = aws.Int64(2)
+	s.mu.Unlock()
+	sync()
+	if _, err := os.Stat(warmFile); !os.IsNotExist(err) {
+		t.Errorf("Warm start file must be removed: %v", err)
+	}
+	if n := sync(); n != 3 {
+		t.Errorf("Sync without the warm start file must list all pages, got %d", n)
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchInterval is the default interval of Watch to wait for the changed files to settle.
+const DefaultWatchInterval = 2 * time.Second
+
+var errWatchDestNotS3 = errors.New("destination of the watch must be s3 url")
+
+// Watch syncs the local directory to the s3 url, and then keeps uploading
+// the changed files, and deleting the removed files if WithDelete is given,
+// until the context is canceled.
+// The changes are notified by the file system events and debounced, i.e. each
+// changed file is uploaded after it has no events for the interval given by
+// WithWatchInterval so that the files being written are not uploaded repeatedly.
+// The directory is rescanned only if the events may be lost, e.g. on the
+// overflow of the event queue. The failed uploads and deletions are retried
+// after an interval.
+// It returns nil when the context is canceled.
+func (m *Manager) Watch(ctx context.Context, localDir, s3URL string) error {
+	destURL, err := parseURL(s3URL)
+	if err != nil {
+		return err
+	}
+	if !isS3URL(destURL) {
+		return errWatchDestNotS3
+	}
+	destPath, err := urlToS3Path(destURL)
+	if err != nil {
+		return err
+	}
+	if err := m.checkPreset(); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// The directory is watched and scanned before the initial sync
+	// so that the files changed during the sync are synced afterwards.
+	if err := addWatches(watcher, localDir); err != nil {
+		return err
+	}
+	synced, err := m.scanLocal(ctx, localDir)
+	if err != nil {
+		return err
+	}
+	if err := m.Sync(ctx, localDir, s3URL); err != nil {
+		return err
+	}
+
+	interval := m.watchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// pending is the time of the last event of each changed path.
+	pending := make(map[string]time.Time)
+	rescan := false
+	for {
+		select {
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			watchEvent(watcher, localDir, ev, pending)
+			continue
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			println("Rescanning", localDir, "since the events may be lost:", err.Error())
+			rescan = true
+			continue
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+
+		// The paths without the events for an interval are synced,
+		// and the others wait for another interval.
+		now := time.Now()
+		var settled []string
+		for name, t := range pending {
+			if now.Sub(t) >= interval {
+				settled = append(settled, name)
+				delete(pending, name)
+			}
+		}
+		if len(settled) == 0 && !rescan {
+			continue
+		}
+		current, err := m.scanLocal(ctx, localDir)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			println("Failed to scan", localDir+":", err.Error())
+			for _, name := range settled {
+				pending[name] = now
+			}
+			continue
+		}
+		changed, removed := m.watchChanges(synced, current, settled, pending, rescan)
+		rescan = false
+		m.watchSync(ctx, localDir, destPath, synced, changed, removed)
+
+		// The failed ones are retried after an interval.
+		now = time.Now()
+		for _, file := range changed {
+			if synced[file.name] != file {
+				pending[filepath.ToSlash(file.name)] = now
+			}
+		}
+		for _, name := range removed {
+			if _, ok := synced[name]; ok {
+				pending[filepath.ToSlash(name)] = now
+			}
+		}
+	}
+}
+
+// addWatches watches the directory and its subdirectories.
+func addWatches(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path != root {
+				// Removed while walking.
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || path == root {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// watchEvent records the time of the event as the last one of the path,
+// and watches the created directory.
+func watchEvent(watcher *fsnotify.Watcher, localDir string, ev fsnotify.Event, pending map[string]time.Time) {
+	if ev.Op == fsnotify.Chmod {
+		return
+	}
+	if ev.Has(fsnotify.Create) {
+		if info, err := os.Lstat(ev.Name); err == nil && info.IsDir() {
+			if err := addWatches(watcher, ev.Name); err != nil {
+				println("Failed to watch", ev.Name+":", err.Error())
+			}
+		}
+	}
+	rel, err := filepath.Rel(localDir, ev.Name)
+	if err != nil {
+		return
+	}
+	pending[filepath.ToSlash(rel)] = time.Now()
+}
+
+// watchChanges returns the files changed and removed from the synced ones under
+// the settled paths, or all of them if all is true, except the paths still pending.
+// The removed files are forgotten instead unless WithDelete is given.
+func (m *Manager) watchChanges(synced, current map[string]*fileInfo, settled []string, pending map[string]time.Time, all bool) (changed []*fileInfo, removed []string) {
+	affected := func(name string) bool {
+		name = filepath.ToSlash(name)
+		if _, ok := pending[name]; ok {
+			return false
+		}
+		if all {
+			return true
+		}
+		for _, s := range settled {
+			if s == "." || name == s || strings.HasPrefix(name, s+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	for name, cur := range current {
+		if affected(name) && !sameLocalFile(synced[name], cur) {
+			changed = append(changed, cur)
+		}
+	}
+	for name := range synced {
+		if _, ok := current[name]; ok || !affected(name) {
+			continue
+		}
+		if m.del {
+			removed = append(removed, name)
+		} else {
+			delete(synced, name)
+		}
+	}
+	return changed, removed
+}
+
+// watchSync uploads the changed files and deletes the removed files,
+// and records the synced files.
+func (m *Manager) watchSync(ctx context.Context, localDir string, destPath *s3Path, synced map[string]*fileInfo, changed []*fileInfo, removed []string) {
+	// synced is looked up before starting the workers, which write it under mu.
+	for _, file := range changed {
+		file.overwritten = synced[file.name]
+	}
+	var deleted []*fileInfo
+	for _, name := range removed {
+		if file := synced[name]; file != nil {
+			deleted = append(deleted, file)
+		}
+	}
+
+	var mu sync.Mutex
+	wg := &sync.WaitGroup{}
+	workers, stopWorkers := m.startWorkers()
+	defer stopWorkers()
+
+	for _, file := range changed {
+		file := file
+		wg.Add(1)
+		workers.run(file.size, func() {
+			defer wg.Done()
+			if err := m.retry(ctx, func(ctx context.Context) error {
+				return m.upload(ctx, file, localDir, destPath)
+			}); err != nil {
+				println("Failed to upload", file.name+":", err.Error())
+				return
+			}
+			mu.Lock()
+			synced[file.name] = file
+			mu.Unlock()
+		})
+	}
+	for _, file := range deleted {
+		file := file
+		wg.Add(1)
+		workers.run(0, func() {
+			defer wg.Done()
+			if err := m.retry(ctx, func(ctx context.Context) error {
+				return m.deleteRemote(ctx, file, destPath)
+			}); err != nil {
+				println("Failed to delete", file.name+":", err.Error())
+				return
+			}
+			mu.Lock()
+			delete(synced, file.name)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+}
+
+// scanLocal returns the local files passing the filters.
+func (m *Manager) scanLocal(ctx context.Context, localDir string) (map[string]*fileInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	return fileInfoChanToMap(m.applyFilters(ctx, m.listLocalFiles(ctx, localDir, nil)))
+}
+
+// sameLocalFile returns whether the two scans of the local file are the same,
+// where nil is the file not existing.
+func sameLocalFile(a, b *fileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.size == b.size && a.lastModified.Equal(b.lastModified) && a.symlink == b.symlink
+}
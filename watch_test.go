@@ -0,0 +1,170 @@
+// Copyright 2026 SEQSENSE, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatch_DestNotS3(t *testing.T) {
+	m := New(session.New())
+	if err := m.Watch(context.Background(), "testdata", "dest"); err != errWatchDestNotS3 {
+		t.Errorf("Expected %v, got %v", errWatchDestNotS3, err)
+	}
+}
+
+func TestSameLocalFile(t *testing.T) {
+	now := time.Now()
+	file := &fileInfo{name: "a", size: 1, lastModified: now}
+
+	testCases := map[string]struct {
+		a, b     *fileInfo
+		expected bool
+	}{
+		"Same":     {file, &fileInfo{name: "a", size: 1, lastModified: now}, true},
+		"BothNil":  {nil, nil, true},
+		"Created":  {nil, file, false},
+		"Removed":  {file, nil, false},
+		"Resized":  {file, &fileInfo{name: "a", size: 2, lastModified: now}, false},
+		"Modified": {file, &fileInfo{name: "a", size: 1, lastModified: now.Add(time.Second)}, false},
+		"Relinked": {file, &fileInfo{name: "a", size: 1, lastModified: now, symlink: "b"}, false},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if got := sameLocalFile(tc.a, tc.b); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWatchChanges(t *testing.T) {
+	now := time.Now()
+	files := func(names ...string) map[string]*fileInfo {
+		m := make(map[string]*fileInfo)
+		for _, name := range names {
+			m[name] = &fileInfo{name: name, size: 1, lastModified: now}
+		}
+		return m
+	}
+	current := files("a", "b/c", "b/d", "e")
+	current["a"].size = 2
+	current["b/c"].size = 2
+	current["e"].size = 2
+
+	testCases := map[string]struct {
+		del       bool
+		settled   []string
+		pending   []string
+		all       bool
+		changed   []string
+		removed   []string
+		forgotten []string
+	}{
+		"File":       {settled: []string{"a"}, changed: []string{"a"}},
+		"Directory":  {settled: []string{"b"}, changed: []string{"b/c", "b/d"}},
+		"Root":       {settled: []string{"."}, changed: []string{"a", "b/c", "b/d", "e"}, forgotten: []string{"f"}},
+		"Rescan":     {all: true, changed: []string{"a", "b/c", "b/d", "e"}, forgotten: []string{"f"}},
+		"Pending":    {settled: []string{"b"}, pending: []string{"b/c"}, changed: []string{"b/d"}},
+		"Removed":    {del: true, settled: []string{"f"}, removed: []string{"f"}},
+		"NotRemoved": {settled: []string{"f"}, forgotten: []string{"f"}},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			m := New(session.New())
+			m.del = tc.del
+			synced := files("a", "b/c", "f")
+			pending := make(map[string]time.Time)
+			for _, name := range tc.pending {
+				pending[name] = now
+			}
+			changed, removed := m.watchChanges(synced, current, tc.settled, pending, tc.all)
+			var names []string
+			for _, file := range changed {
+				names = append(names, file.name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(tc.changed, names) {
+				t.Errorf("Expected changed %v, got %v", tc.changed, names)
+			}
+			if !reflect.DeepEqual(tc.removed, removed) {
+				t.Errorf("Expected removed %v, got %v", tc.removed, removed)
+			}
+			for _, name := range tc.forgotten {
+				if _, ok := synced[name]; ok {
+					t.Errorf("Expected %s to be forgotten", name)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchEvent(t *testing.T) {
+	temp, err := ioutil.TempDir("", "s3synctest")
+	if err != nil {
+		t.Fatal("Failed to create temp dir")
+	}
+	defer os.RemoveAll(temp)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	if err := addWatches(watcher, temp); err != nil {
+		t.Fatal(err)
+	}
+
+	pending := make(map[string]time.Time)
+	next := func() {
+		select {
+		case ev := <-watcher.Events:
+			watchEvent(watcher, temp, ev, pending)
+		case err := <-watcher.Errors:
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeout")
+		}
+	}
+
+	// The created directory is watched.
+	if err := os.Mkdir(filepath.Join(temp, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	next()
+	if _, ok := pending["dir"]; !ok {
+		t.Fatalf("Expected the event of dir, got %v", pending)
+	}
+	if err := ioutil.WriteFile(filepath.Join(temp, "dir", "file"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, ok := pending["dir/file"]; ok {
+			break
+		}
+		next()
+	}
+}